@@ -27,13 +27,20 @@ func main() {
 
 	controller := output.NewController[output.Message[string]]()
 
-	orderService, closePG := cmd.GetOrderService(ctx)
+	kafkaAdmin := cmd.GetKafkaAdmin()
+	defer kafkaAdmin.Close()
+
+	// the cli has no watchers, its changes are only published for the grpc instances
+	notifier, closeEvents := cmd.GetOrderEventsNotifier(ctx)
+	orderService, ordersCache, _, closePG := cmd.GetOrderService(ctx, cmd.AppCLI, notifier, nil)
 	commands := cli.NewCLI(cli.Deps{
 		Service: orderService,
+		Admin:   kafkaAdmin,
+		Cache:   ordersCache,
 	})
 
-	onCallProducer := cmd.GetOnCallKafkaSender(ctx)
-	defer onCallProducer.Close()
+	onCallProducer := cmd.GetOnCallKafkaSender(ctx, cmd.AppCLI)
+	defer cmd.CloseOnCallKafkaSender(onCallProducer)
 
 	jobs := getJobs(ctx, getLines())
 	app := app.NewApp(commands, jobs, onCallProducer)
@@ -57,5 +64,6 @@ func main() {
 	controller.Close()
 	commands.Close()
 	closePG()
+	closeEvents()
 	_, _ = fmt.Fprintln(os.Stdout, "done")
 }
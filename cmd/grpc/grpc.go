@@ -5,23 +5,34 @@ import (
 	"fmt"
 	"github.com/go-chi/cors"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 	"homework/config"
 	"homework/internal/api"
 	"homework/internal/api/middleware"
+	"homework/internal/cache"
+	"homework/internal/eventbus"
+	"homework/internal/health"
 	"homework/internal/infrastructure/app/oncall"
 	"homework/internal/service"
+	"homework/pkg/api/admin/v1"
 	"homework/pkg/api/order/v1"
 	gw "homework/pkg/api/order/v1"
+	orderv2 "homework/pkg/api/order/v2"
 	"log"
 	"net"
 	"net/http"
+	"os"
+	"strings"
 	"sync"
 )
 
-func startGrpcServer(ctx context.Context, cancelFunc context.CancelFunc, orderService *service.OrderService, producer *oncall.KafkaProducer) *sync.WaitGroup {
+func startGrpcServer(ctx context.Context, cancelFunc context.CancelFunc, orderService *service.OrderService,
+	ordersCache *cache.OrdersCache, pool *pgxpool.Pool, producer *oncall.KafkaProducer, bus *eventbus.Bus, checker *health.Checker) *sync.WaitGroup {
 	cfg := config.MustNewApiConfig()
 
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GrpcPort))
@@ -30,12 +41,54 @@ func startGrpcServer(ctx context.Context, cancelFunc context.CancelFunc, orderSe
 		log.Fatalf("failed to listen: %v", err)
 	}
 
-	mux := runtime.NewServeMux()
+	authenticator, err := newAuthenticator(cfg.Auth)
+	if err != nil {
+		log.Fatalf("failed to create authenticator: %v", err)
+	}
+
+	audit := middleware.NewAudit(cfg.Audit.Allow, cfg.Audit.Deny, cfg.Audit.Redact)
+	var (
+		inFlight *middleware.InFlightLimiter
+		auth     *middleware.Authenticator
+		limiter  *middleware.RateLimiter
+		rbac     *middleware.RBAC
+	)
+	if cfg.RateLimit.Enabled && cfg.RateLimit.MaxInFlight > 0 {
+		inFlight = middleware.NewInFlightLimiter(cfg.RateLimit.MaxInFlight)
+	}
+	if cfg.Auth.Enabled {
+		auth = &authenticator
+	}
+	if cfg.RateLimit.Enabled {
+		limiter = newRateLimiter(cfg.RateLimit)
+	}
+	if cfg.RBAC.Enabled {
+		policy := middleware.NewRBAC(mustNewPolicy(cfg), producer, audit)
+		rbac = &policy
+	}
+
+	mux := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(gatewayHeaderMatcher),
+		runtime.WithOutgoingHeaderMatcher(gatewayOutgoingHeaderMatcher),
+	)
 	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
 	errGw := gw.RegisterOrderHandlerFromEndpoint(ctx, mux, cfg.GrpcENDPOINT, opts)
 	if errGw != nil {
 		log.Fatalf("failed to RegisterOrderHandlerFromEndpoint: %v", errGw)
 	}
+	errGw = orderv2.RegisterOrderServiceHandlerFromEndpoint(ctx, mux, cfg.GrpcENDPOINT, opts)
+	if errGw != nil {
+		log.Fatalf("failed to RegisterOrderServiceHandlerFromEndpoint: %v", errGw)
+	}
+	errGw = admin.RegisterAdminHandlerFromEndpoint(ctx, mux, cfg.GrpcENDPOINT, opts)
+	if errGw != nil {
+		log.Fatalf("failed to RegisterAdminHandlerFromEndpoint: %v", errGw)
+	}
+
+	checker.Add("postgres", pool.Ping)
+	checker.Add("kafka_oncall_producer", producer.Ping)
+	checker.Add("cache", ordersCache.Check)
+	go checker.Run(ctx, cfg.Health.Interval)
 
 	gwServer := &http.Server{
 		Addr:    fmt.Sprintf(":%d", cfg.HttpPort),
@@ -47,6 +100,12 @@ func startGrpcServer(ctx context.Context, cancelFunc context.CancelFunc, orderSe
 		mux.HandlePath(http.MethodGet, "/metrics", func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
 			promHandler.ServeHTTP(w, r)
 		})
+		mux.HandlePath(http.MethodGet, "/healthz", checker.Healthz)
+		mux.HandlePath(http.MethodGet, "/readyz", checker.Readyz)
+		// the export isn't a grpc method, the guard checks it like the interceptors do
+		guard := middleware.NewHTTPGuard(inFlight, auth, limiter, rbac, producer, audit)
+		exportHandler := api.NewExportHandler(orderService, guard, cfg.Export.Timeout)
+		mux.HandlePath(http.MethodGet, api.ExportOrdersPath, exportHandler.Export)
 
 		err := gwServer.ListenAndServe()
 		if err != nil {
@@ -54,9 +113,38 @@ func startGrpcServer(ctx context.Context, cancelFunc context.CancelFunc, orderSe
 		}
 	}()
 
-	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(middleware.OnCall(producer)))
+	unary := []grpc.UnaryServerInterceptor{middleware.Tracing()}
+	stream := []grpc.StreamServerInterceptor{middleware.TracingStream()}
+	if inFlight != nil {
+		unary = append(unary, middleware.InFlight(inFlight))
+		stream = append(stream, middleware.InFlightStream(inFlight))
+	}
+	if cfg.Auth.Enabled {
+		unary = append(unary, middleware.Auth(authenticator, producer, audit))
+		stream = append(stream, middleware.AuthStream(authenticator, producer, audit))
+	}
+	// limits are per authenticated client, so they go after Auth
+	if limiter != nil {
+		unary = append(unary, middleware.RateLimit(limiter))
+		stream = append(stream, middleware.RateLimitStream(limiter))
+	}
+	// denied calls are audited by RBAC itself, so it goes before OnCall
+	if rbac != nil {
+		unary = append(unary, middleware.RBACUnary(*rbac))
+		stream = append(stream, middleware.RBACStream(*rbac))
+	}
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(append(unary, middleware.OnCall(producer, audit))...),
+		grpc.ChainStreamInterceptor(append(stream, middleware.OnCallStream(producer, audit))...),
+	)
 
 	order.RegisterOrderServer(grpcServer, api.NewOrderService(orderService))
+	orderv2.RegisterOrderServiceServer(grpcServer, api.NewOrderServiceV2(orderService, bus, cfg.Deliver.ChunkSize))
+	admin.RegisterAdminServer(grpcServer, api.NewAdminService(ordersCache))
+	healthpb.RegisterHealthServer(grpcServer, checker.Server())
+	if cfg.Reflection {
+		reflection.Register(grpcServer)
+	}
 	go func() {
 		err := grpcServer.Serve(lis)
 		if err != nil {
@@ -68,6 +156,9 @@ func startGrpcServer(ctx context.Context, cancelFunc context.CancelFunc, orderSe
 	wg.Add(2)
 	go func() {
 		<-ctx.Done()
+		checker.Shutdown()
+		// watchers don't end their streams themselves
+		bus.Close()
 		grpcServer.GracefulStop()
 		wg.Done()
 
@@ -77,3 +168,75 @@ func startGrpcServer(ctx context.Context, cancelFunc context.CancelFunc, orderSe
 
 	return &wg
 }
+
+// newChecker has no checks yet, the dependencies are added as they're created, all of them before Run.
+func newChecker(cfg config.HealthConfig) *health.Checker {
+	return health.NewChecker(cfg.Timeout, order.Order_ServiceDesc.ServiceName,
+		orderv2.OrderService_ServiceDesc.ServiceName, admin.Admin_ServiceDesc.ServiceName)
+}
+
+// gatewayHeaderMatcher passes the api key to the grpc server, the Authorization header is passed by default.
+// The client can't pass x-forwarded-for as metadata, the gateway sets it to the address of the http peer.
+func gatewayHeaderMatcher(key string) (string, bool) {
+	if strings.EqualFold(key, middleware.ApiKeyHeader) {
+		return middleware.ApiKeyHeader, true
+	}
+	if strings.EqualFold(key, runtime.MetadataHeaderPrefix+middleware.ForwardedForHeader) {
+		return "", false
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// gatewayOutgoingHeaderMatcher returns retry-after as the standard header instead of Grpc-Metadata-Retry-After.
+func gatewayOutgoingHeaderMatcher(key string) (string, bool) {
+	if key == middleware.RetryAfterHeader {
+		return "Retry-After", true
+	}
+	return fmt.Sprintf("%s%s", runtime.MetadataHeaderPrefix, key), true
+}
+
+func newRateLimiter(cfg config.RateLimitConfig) *middleware.RateLimiter {
+	methods := make(map[string]middleware.Limit, len(cfg.Methods))
+	for method, limit := range cfg.Methods {
+		methods[method] = middleware.Limit{Rate: limit.Rate, Burst: limit.Burst}
+	}
+	return middleware.NewRateLimiter(middleware.Limit{Rate: cfg.Default.Rate, Burst: cfg.Default.Burst}, methods, cfg.Clients)
+}
+
+func mustNewPolicy(cfg config.ApiConfig) middleware.Policy {
+	policy, err := config.NewRBACPolicy(cfg.RBAC.PolicyPath)
+	if err != nil {
+		log.Fatalf("failed to read rbac policy: %v", err)
+	}
+	from, to, location, _ := policy.WorkingHours.Parse()
+
+	roles := make(map[string]middleware.Role, len(policy.Roles))
+	for name, role := range policy.Roles {
+		roles[name] = middleware.Role{Methods: role.Methods, AfterHours: role.AfterHours}
+	}
+	bindings := make(map[string]middleware.Binding, len(policy.Bindings))
+	for _, binding := range policy.Bindings {
+		bindings[binding.Client] = middleware.Binding{Role: binding.Role, PickupPoints: binding.PickupPoints}
+	}
+
+	return middleware.Policy{
+		PickupPoint:      policy.PickupPoint,
+		WorkingHours:     middleware.WorkingHours{From: from, To: to, Location: location},
+		WorkingHoursOnly: policy.WorkingHoursOnly,
+		Roles:            roles,
+		Bindings:         bindings,
+		Public:           cfg.Auth.Public,
+	}
+}
+
+func newAuthenticator(cfg config.AuthConfig) (middleware.Authenticator, error) {
+	var jwtKey []byte
+	if cfg.JWT.Algorithm != "" {
+		var err error
+		jwtKey, err = os.ReadFile(cfg.JWT.KeyPath)
+		if err != nil {
+			return middleware.Authenticator{}, err
+		}
+	}
+	return middleware.NewAuthenticator(cfg.ApiKeys, cfg.Public, cfg.JWT.Algorithm, jwtKey, cfg.JWT.Issuer, cfg.JWT.Audience)
+}
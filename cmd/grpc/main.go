@@ -24,21 +24,29 @@ func main() {
 	tracerCloser := tracer.MustSetup(name)
 	defer tracerCloser.Close()
 
-	command, closeDB := cmd.GetOrderService(ctx)
-	producer := cmd.GetOnCallKafkaSender(ctx)
-	grpcWG := startGrpcServer(ctx, cancel, command, producer)
+	kafkaAdmin := cmd.GetKafkaAdmin()
+	defer kafkaAdmin.Close()
+
+	checker := newChecker(config.MustNewApiConfig().Health)
+	bus, notifier, closeEvents := cmd.GetOrderEvents(ctx, checker)
+	command, ordersCache, pool, closeDB := cmd.GetOrderService(ctx, cmd.AppGRPC, notifier, checker)
+	producer := cmd.GetOnCallKafkaSender(ctx, cmd.AppGRPC)
+	defer cmd.CloseOnCallKafkaSender(producer)
 
 	if outputCFG.Filter == output.Kafka {
 		kafkaMessages, handler := oncall.NewTopicHandler()
 		onCallConsumer := cmd.GetOnCallKafkaReceiver(handler)
+		checker.Add("kafka_oncall_consumer", onCallConsumer.Ping)
 		controller.Add(output.BuildMessageChan[string](output.Kafka, kafkaMessages))
 		defer onCallConsumer.Close()
 	}
+	grpcWG := startGrpcServer(ctx, cancel, command, ordersCache, pool, producer, bus, checker)
 
 	filtered := output.FilterMessageChan(outputCFG.Filter, controller.Subscribe())
 	go run(ctx, cancel, filtered)
 
 	grpcWG.Wait()
 	closeDB()
+	closeEvents()
 	_, _ = fmt.Fprintln(os.Stdout, "done")
 }
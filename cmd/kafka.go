@@ -2,16 +2,41 @@ package cmd
 
 import (
 	"context"
+	"errors"
+	"github.com/google/uuid"
 	"homework/config"
+	"homework/internal/cache"
+	"homework/internal/eventbus"
+	"homework/internal/health"
+	"homework/internal/infrastructure/app/events"
+	"homework/internal/infrastructure/app/invalidation"
 	"homework/internal/infrastructure/app/oncall"
 	"homework/internal/infrastructure/kafka"
 	"log"
+	"os"
+	"path/filepath"
+	"time"
 )
 
-func GetOnCallKafkaSender(ctx context.Context) *oncall.KafkaProducer {
+const flushTimeout = 5 * time.Second
+
+// GetOnCallKafkaSender spills the messages of every app to its own file, so a binary doesn't replay and remove
+// the messages the other one has just spilled.
+func GetOnCallKafkaSender(ctx context.Context, app string) *oncall.KafkaProducer {
 	cfg := config.MustNewKafkaConfig()
 
-	kafkaProducer, err := kafka.NewProducer(ctx, cfg.Brokers)
+	spillPath := ""
+	if cfg.Producer.SpillDir != "" {
+		if err := os.MkdirAll(cfg.Producer.SpillDir, 0o755); err != nil {
+			log.Fatalln(err)
+		}
+		spillPath = filepath.Join(cfg.Producer.SpillDir, app+".jsonl")
+	}
+	kafkaProducer, err := kafka.NewProducer(ctx, cfg.Brokers, kafka.ProducerConfig{
+		BufferSize: cfg.Producer.BufferSize,
+		Overflow:   kafka.Overflow(cfg.Producer.Overflow),
+		SpillPath:  spillPath,
+	})
 	if err != nil {
 		log.Fatalln(err)
 	}
@@ -20,6 +45,43 @@ func GetOnCallKafkaSender(ctx context.Context) *oncall.KafkaProducer {
 	return onCallConsumer
 }
 
+func CloseOnCallKafkaSender(producer *oncall.KafkaProducer) {
+	ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+	defer cancel()
+
+	if err := producer.Flush(ctx); err != nil {
+		log.Println(err)
+	}
+	if err := producer.Close(); err != nil {
+		log.Println(err)
+	}
+}
+
+func GetKafkaAdmin() *kafka.Admin {
+	cfg := config.MustNewKafkaConfig()
+
+	admin, err := kafka.NewAdmin(cfg.Brokers)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	topics := make([]kafka.TopicConfig, 0, len(cfg.Topics))
+	for _, topic := range cfg.Topics {
+		topics = append(topics, kafka.TopicConfig{
+			Name:              kafka.Topic(topic.Name),
+			Partitions:        topic.Partitions,
+			ReplicationFactor: topic.ReplicationFactor,
+			Retention:         topic.Retention,
+		})
+	}
+
+	if err := admin.EnsureTopics(topics); err != nil {
+		_ = admin.Close()
+		log.Fatalln(err)
+	}
+	return admin
+}
+
 func GetOnCallKafkaReceiver(handler oncall.HandleFunc) *oncall.KafkaConsumer {
 	cfg := config.MustNewKafkaConfig()
 
@@ -37,3 +99,153 @@ func GetOnCallKafkaReceiver(handler oncall.HandleFunc) *oncall.KafkaConsumer {
 
 	return onCallConsumer
 }
+
+// getCacheInvalidation returns nil publisher when cache_invalidation_topic isn't set.
+func getCacheInvalidation(ctx context.Context, ordersCache *cache.OrdersCache, checker *health.Checker) (*invalidation.KafkaPublisher, func()) {
+	cfg := config.MustNewKafkaConfig()
+	if cfg.CacheInvalidationTopic == "" {
+		return nil, func() {}
+	}
+
+	kafkaProducer, err := kafka.NewProducer(ctx, cfg.Brokers, kafka.ProducerConfig{
+		BufferSize: cfg.Producer.BufferSize,
+		Overflow:   kafka.OverflowDrop,
+	})
+	if err != nil {
+		log.Fatalln(err)
+	}
+	publisher := invalidation.NewKafkaPublisher(kafkaProducer, kafka.Topic(cfg.CacheInvalidationTopic))
+
+	kafkaConsumer, err := kafka.NewConsumer(cfg.Brokers)
+	if err != nil {
+		_ = publisher.Close()
+		log.Fatalln(err)
+	}
+	receiver := oncall.NewKafkaReceiver(kafkaConsumer)
+	err = receiver.Subscribe(kafka.Topic(cfg.CacheInvalidationTopic), invalidation.NewHandler(ordersCache))
+	if err != nil {
+		_ = receiver.Close()
+		_ = publisher.Close()
+		log.Fatalln(err)
+	}
+	addCheck(checker, "kafka_invalidation_producer", publisher.Ping)
+	addCheck(checker, "kafka_invalidation_consumer", receiver.Ping)
+
+	return publisher, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+		defer cancel()
+
+		if err := publisher.Flush(ctx); err != nil {
+			log.Println(err)
+		}
+		if err := publisher.Close(); err != nil {
+			log.Println(err)
+		}
+		if err := receiver.Close(); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// GetOrderEvents returns the bus WatchOrders subscribes to and the notifier the service publishes to,
+// the events are shared with the other instances through order_events_topic if it's set.
+func GetOrderEvents(ctx context.Context, checker *health.Checker) (*eventbus.Bus, *events.Notifier, func()) {
+	cfgApi := config.MustNewApiConfig()
+	cfg := config.MustNewKafkaConfig()
+	bus := eventbus.NewBus(cfgApi.Watch.History, cfgApi.Watch.Buffer)
+	pickupPoint := getPickupPoint(cfgApi.RBAC)
+	source := uuid.NewString()
+	if cfg.OrderEventsTopic == "" {
+		return bus, events.NewNotifier(bus, nil, pickupPoint, source), bus.Close
+	}
+
+	publisher := newOrderEventsPublisher(ctx, cfg)
+	kafkaConsumer, err := kafka.NewConsumer(cfg.Brokers)
+	if err != nil {
+		_ = publisher.Close()
+		log.Fatalln(err)
+	}
+	receiver := oncall.NewKafkaReceiver(kafkaConsumer)
+	err = receiver.Subscribe(kafka.Topic(cfg.OrderEventsTopic), events.NewHandler(bus, source))
+	if err != nil {
+		_ = receiver.Close()
+		_ = publisher.Close()
+		log.Fatalln(err)
+	}
+	addCheck(checker, "kafka_events_producer", publisher.Ping)
+	addCheck(checker, "kafka_events_consumer", receiver.Ping)
+
+	return bus, events.NewNotifier(bus, publisher, pickupPoint, source), func() {
+		bus.Close()
+		closeOrderEventsPublisher(publisher)
+		if err := receiver.Close(); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// GetOrderEventsNotifier returns the notifier of the apps without watchers, it only publishes to order_events_topic.
+// The notifier is nil when the topic isn't set. The pickup point is taken from the rbac policy of API_CONFIG_PATH
+// if it's set.
+func GetOrderEventsNotifier(ctx context.Context) (*events.Notifier, func()) {
+	cfg := config.MustNewKafkaConfig()
+	if cfg.OrderEventsTopic == "" {
+		return nil, func() {}
+	}
+
+	pickupPoint := ""
+	cfgRBAC, err := config.NewRBACConfig()
+	switch {
+	case err == nil:
+		pickupPoint = getPickupPoint(cfgRBAC)
+	case !errors.Is(err, config.ErrApiConfigPathIsEmpty):
+		log.Fatalln(err)
+	}
+
+	publisher := newOrderEventsPublisher(ctx, cfg)
+	return events.NewNotifier(nil, publisher, pickupPoint, uuid.NewString()), func() {
+		closeOrderEventsPublisher(publisher)
+	}
+}
+
+func newOrderEventsPublisher(ctx context.Context, cfg config.KafkaConfig) *events.KafkaPublisher {
+	kafkaProducer, err := kafka.NewProducer(ctx, cfg.Brokers, kafka.ProducerConfig{
+		BufferSize: cfg.Producer.BufferSize,
+		Overflow:   kafka.OverflowDrop,
+	})
+	if err != nil {
+		log.Fatalln(err)
+	}
+	return events.NewKafkaPublisher(kafkaProducer, kafka.Topic(cfg.OrderEventsTopic))
+}
+
+func closeOrderEventsPublisher(publisher *events.KafkaPublisher) {
+	ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+	defer cancel()
+
+	if err := publisher.Flush(ctx); err != nil {
+		log.Println(err)
+	}
+	if err := publisher.Close(); err != nil {
+		log.Println(err)
+	}
+}
+
+// addCheck skips the check if the app runs without health checks.
+func addCheck(checker *health.Checker, name string, check health.Check) {
+	if checker != nil {
+		checker.Add(name, check)
+	}
+}
+
+// getPickupPoint is the pickup point of the rbac policy, empty if there is no policy.
+func getPickupPoint(cfg config.RBACConfig) string {
+	if cfg.PolicyPath == "" {
+		return ""
+	}
+	policy, err := config.NewRBACPolicy(cfg.PolicyPath)
+	if err != nil {
+		log.Fatalf("failed to read rbac policy: %v", err)
+	}
+	return policy.PickupPoint
+}
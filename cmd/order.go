@@ -6,17 +6,51 @@ import (
 	"github.com/jackc/pgx/v4/pgxpool"
 	"homework/config"
 	"homework/internal/cache"
+	"homework/internal/dto"
+	"homework/internal/health"
+	"homework/internal/infrastructure/app/events"
+	"homework/internal/metrics"
 	"homework/internal/service"
 	"homework/internal/storage"
 	"homework/internal/storage/transactor"
 	pool "homework/pkg/postgres"
+	"io"
 	"log"
 	"os"
+	"path/filepath"
 )
 
-func GetOrderService(ctx context.Context) (*service.OrderService, func()) {
+const (
+	AppGRPC = "grpc"
+	AppCLI  = "cli"
+)
+
+// GetOrderService returns the pool too, so the grpc server can check the database.
+// The changes of the orders are published to notifier unless it's nil, the kafka clients are added to checker
+// unless it's nil.
+// The cache snapshot of every app is its own file, so the binaries don't overwrite each other's.
+func GetOrderService(ctx context.Context, app string, notifier *events.Notifier, checker *health.Checker) (*service.OrderService, *cache.OrdersCache, *pgxpool.Pool, func()) {
 	cfgCache := config.MustNewCacheConfig()
-	ordersCache := cache.NewOrdersCache(int(cfgCache.Capacity), cfgCache.TTL)
+	snapshotPath := ""
+	if cfgCache.SnapshotDir != "" {
+		snapshotPath = filepath.Join(cfgCache.SnapshotDir, app+".jsonl")
+	}
+	trace, err := openCacheTrace(cfgCache.TracePath)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	ordersCache := cache.NewOrdersCacheWithConfig(cache.OrdersCacheConfig{
+		Strategy:        cache.Strategy(cfgCache.Strategy),
+		Capacity:        int(cfgCache.Capacity),
+		TTL:             cfgCache.TTL,
+		Stale:           cfgCache.StaleTTL,
+		JanitorInterval: cfgCache.JanitorInterval,
+		Trace:           trace,
+		CapacityInBytes: cfgCache.CapacityUnit == config.CacheCapacityInBytes,
+		Shards:          int(cfgCache.Shards),
+	})
+	metrics.RegisterCache("orders", ordersCache.Stats)
 
 	pool, err := getPool(ctx)
 	if err != nil {
@@ -25,15 +59,87 @@ func GetOrderService(ctx context.Context) (*service.OrderService, func()) {
 
 	transactionManager := transactor.NewTransactionManager(pool)
 
-	orderStorage := storage.NewOrderStorage(&transactionManager, ordersCache)
+	publisher, closeInvalidation := getCacheInvalidation(ctx, ordersCache, checker)
+
+	var invalidator storage.Invalidator
+	if publisher != nil {
+		invalidator = publisher
+	}
+	orderStorage := storage.NewOrderStorage(&transactionManager, ordersCache, invalidator, cfgCache.FetchTimeout)
+	if snapshotPath != "" {
+		go warmUpCache(ctx, orderStorage, ordersCache, snapshotPath)
+	}
 	wrapperStorage := storage.NewWrapperStorage(&transactionManager)
 
-	var orderService = service.NewOrder(service.Deps{
+	deps := service.Deps{
 		Storage:            orderStorage,
 		WrapperStorage:     wrapperStorage,
 		TransactionManager: &transactionManager,
-	})
-	return &orderService, pool.Close
+	}
+	if notifier != nil {
+		deps.Events = notifier
+	}
+	var orderService = service.NewOrder(deps)
+	return &orderService, ordersCache, pool, func() {
+		if snapshotPath != "" {
+			saveCacheSnapshot(ordersCache, snapshotPath, int(cfgCache.SnapshotKeys))
+		}
+		pool.Close()
+		closeInvalidation()
+		ordersCache.Close()
+	}
+}
+
+// warmUpCache replays the queries saved by the previous run with their frequencies, a missing snapshot isn't an error.
+func warmUpCache(ctx context.Context, orderStorage *storage.OrderStorage, ordersCache *cache.OrdersCache, path string) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	if err != nil {
+		log.Printf("[cmd] cache warm up error: %v", err)
+		return
+	}
+	defer file.Close()
+
+	entries, err := cache.ReadSnapshot(file)
+	if err != nil {
+		log.Printf("[cmd] cache warm up error: %v", err)
+		return
+	}
+
+	ordersCache.Seed(entries)
+	params := make([]dto.GetParam, 0, len(entries))
+	for _, entry := range entries {
+		params = append(params, entry.Param)
+	}
+	loaded := orderStorage.WarmUp(ctx, params)
+	log.Printf("[cmd] cache warmed up with %d of %d queries", loaded, len(params))
+}
+
+func saveCacheSnapshot(ordersCache *cache.OrdersCache, path string, keys int) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		log.Printf("[cmd] cache snapshot error: %v", err)
+		return
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		log.Printf("[cmd] cache snapshot error: %v", err)
+		return
+	}
+	defer file.Close()
+
+	if err := cache.WriteSnapshot(file, ordersCache.Snapshot(keys)); err != nil {
+		log.Printf("[cmd] cache snapshot error: %v", err)
+	}
+}
+
+// openCacheTrace returns nil writer if the path is empty.
+func openCacheTrace(path string) (io.WriteCloser, error) {
+	if path == "" {
+		return nil, nil
+	}
+	return os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
 }
 
 func getPool(ctx context.Context) (*pgxpool.Pool, error) {
@@ -3,15 +3,98 @@ package config
 import (
 	"github.com/ilyakaznacheev/cleanenv"
 	"os"
+	"time"
 )
 
-type ApiConfig struct {
-	GrpcPort     uint   `yaml:"grpc_port"`
-	GrpcENDPOINT string `yaml:"grpc_endpoint"`
-	HttpPort     uint   `yaml:"http_port"`
-	HttpENDPOINT string `yaml:"http_endpoint"`
-	SwaggerPort  uint   `yaml:"swagger_port"`
-}
+type (
+	ApiConfig struct {
+		GrpcPort     uint   `yaml:"grpc_port"`
+		GrpcENDPOINT string `yaml:"grpc_endpoint"`
+		HttpPort     uint   `yaml:"http_port"`
+		HttpENDPOINT string `yaml:"http_endpoint"`
+		SwaggerPort  uint   `yaml:"swagger_port"`
+		// Reflection lets grpcurl and other clients read the services without the proto files.
+		Reflection bool            `yaml:"reflection"`
+		Audit      AuditConfig     `yaml:"audit"`
+		Auth       AuthConfig      `yaml:"auth"`
+		RBAC       RBACConfig      `yaml:"rbac"`
+		RateLimit  RateLimitConfig `yaml:"rate_limit"`
+		Health     HealthConfig    `yaml:"health"`
+		Deliver    DeliverConfig   `yaml:"deliver"`
+		Watch      WatchConfig     `yaml:"watch"`
+		Export     ExportConfig    `yaml:"export"`
+	}
+
+	ExportConfig struct {
+		// Timeout cancels an export that takes longer, 0 doesn't limit it.
+		Timeout time.Duration `yaml:"timeout" env-default:"10m"`
+	}
+
+	WatchConfig struct {
+		// History is how many last events are kept for the watchers to resume after.
+		History int `yaml:"history" env-default:"10000"`
+		// Buffer is how many events a watcher may be behind before its stream is ended.
+		Buffer int `yaml:"buffer" env-default:"256"`
+	}
+
+	DeliverConfig struct {
+		// ChunkSize is how many orders of a batch or a stream are delivered in one transaction.
+		ChunkSize int `yaml:"chunk_size" env-default:"500"`
+	}
+
+	HealthConfig struct {
+		// Interval is how often Postgres, Kafka and the cache are checked.
+		Interval time.Duration `yaml:"interval" env-default:"5s"`
+		// Timeout fails a check that hasn't answered in time.
+		Timeout time.Duration `yaml:"timeout" env-default:"2s"`
+	}
+
+	AuditConfig struct {
+		Allow  []string `yaml:"allow"`
+		Deny   []string `yaml:"deny"`
+		Redact []string `yaml:"redact"`
+	}
+
+	AuthConfig struct {
+		Enabled bool `yaml:"enabled"`
+		// ApiKeys are the keys by client names, they're only read from the environment as client:key,client:key.
+		ApiKeys map[string]string `yaml:"-" env:"API_KEYS"`
+		JWT     JWTConfig         `yaml:"jwt"`
+		// Public are the methods called without credentials.
+		Public []string `yaml:"public"`
+	}
+
+	RateLimitConfig struct {
+		Enabled bool `yaml:"enabled"`
+		// Default is the limit of every client for the methods missing in Methods.
+		Default LimitConfig            `yaml:"default"`
+		Methods map[string]LimitConfig `yaml:"methods"`
+		// Clients is how many most recently active clients keep their buckets.
+		Clients int `yaml:"clients" env-default:"10000"`
+		// MaxInFlight is the limit of calls handled at the same time by the whole server, 0 disables it.
+		MaxInFlight int `yaml:"max_in_flight"`
+	}
+
+	// LimitConfig is a token bucket refilled with Rate tokens per second up to Burst.
+	LimitConfig struct {
+		Rate  float64 `yaml:"rate"`
+		Burst int     `yaml:"burst"`
+	}
+
+	JWTConfig struct {
+		// Algorithm is HS256 or RS256, empty disables tokens.
+		Algorithm string `yaml:"algorithm"`
+		// KeyPath is the file with the HS256 secret or the PEM encoded RS256 public key.
+		KeyPath  string `yaml:"key_path" env:"JWT_KEY_PATH"`
+		Issuer   string `yaml:"issuer"`
+		Audience string `yaml:"audience"`
+	}
+)
+
+const (
+	JWTAlgorithmHS256 = "HS256"
+	JWTAlgorithmRS256 = "RS256"
+)
 
 func NewApiConfig() (ApiConfig, error) {
 	path := os.Getenv("API_CONFIG_PATH")
@@ -20,7 +103,27 @@ func NewApiConfig() (ApiConfig, error) {
 	}
 	var cfg ApiConfig
 	err := cleanenv.ReadConfig(path, &cfg)
-	return cfg, err
+	if err != nil {
+		return cfg, err
+	}
+
+	// without a principal every call but the public ones would be denied
+	if cfg.RBAC.Enabled && !cfg.Auth.Enabled {
+		return cfg, ErrRBACIsEnabledWithoutAuth
+	}
+	if cfg.Auth.Enabled && len(cfg.Auth.ApiKeys) == 0 && cfg.Auth.JWT.Algorithm == "" {
+		return cfg, ErrAuthCredentialsAreEmpty
+	}
+	switch cfg.Auth.JWT.Algorithm {
+	case "":
+	case JWTAlgorithmHS256, JWTAlgorithmRS256:
+		if cfg.Auth.JWT.KeyPath == "" {
+			return cfg, ErrJWTKeyPathIsEmpty
+		}
+	default:
+		return cfg, ErrJWTAlgorithmDoesNotExist
+	}
+	return cfg, nil
 }
 
 func MustNewApiConfig() ApiConfig {
@@ -3,12 +3,41 @@ package config
 import (
 	"github.com/ilyakaznacheev/cleanenv"
 	"os"
+	"path/filepath"
+	"slices"
 	"time"
 )
 
+const (
+	CacheCapacityInEntries = "entries"
+	CacheCapacityInBytes   = "bytes"
+
+	CacheStrategyLFU     = "lfu"
+	CacheStrategyLRU     = "lru"
+	CacheStrategyTinyLFU = "tinylfu"
+)
+
 type CacheConfig struct {
+	Strategy string        `yaml:"strategy" env-default:"lfu"`
 	TTL      time.Duration `yaml:"ttl"`
-	Capacity uint          `yaml:"capacity"`
+	// StaleTTL is how long an expired list is served while it's being refreshed, 0 disables it.
+	StaleTTL time.Duration `yaml:"stale_ttl"`
+	// FetchTimeout bounds a query shared by the callers or refreshing a stale result, 0 disables it.
+	FetchTimeout time.Duration `yaml:"fetch_timeout" env-default:"30s"`
+	// JanitorInterval is how often expired entries are removed, 0 disables it.
+	JanitorInterval time.Duration `yaml:"janitor_interval"`
+	Capacity        uint          `yaml:"capacity"`
+	// CapacityUnit is entries or bytes: the number of cached lists or the estimated memory of their orders.
+	CapacityUnit string `yaml:"capacity_unit" env-default:"entries"`
+	// TracePath is the file the looked up keys are appended to, empty disables recording.
+	TracePath string `yaml:"trace_path"`
+	// Shards is the number of independently locked parts of the cache, the capacity is split between them.
+	Shards uint `yaml:"shards" env-default:"1"`
+	// SnapshotDir is the absolute directory the hottest queries of every binary are saved to on shutdown
+	// and replayed from on startup, empty disables the warm-up.
+	SnapshotDir string `yaml:"snapshot_dir" env:"CACHE_SNAPSHOT_DIR"`
+	// SnapshotKeys is the maximum number of saved queries.
+	SnapshotKeys uint `yaml:"snapshot_keys" env-default:"100"`
 }
 
 func NewCacheConfig() (CacheConfig, error) {
@@ -18,7 +47,20 @@ func NewCacheConfig() (CacheConfig, error) {
 	}
 	var cfg CacheConfig
 	err := cleanenv.ReadConfig(path, &cfg)
-	return cfg, err
+	if err != nil {
+		return cfg, err
+	}
+
+	if !slices.Contains([]string{CacheStrategyLFU, CacheStrategyLRU, CacheStrategyTinyLFU}, cfg.Strategy) {
+		return cfg, ErrCacheStrategyDoesNotExist
+	}
+	if cfg.CapacityUnit != CacheCapacityInEntries && cfg.CapacityUnit != CacheCapacityInBytes {
+		return cfg, ErrCacheCapacityUnitIsWrong
+	}
+	if cfg.SnapshotDir != "" && !filepath.IsAbs(cfg.SnapshotDir) {
+		return cfg, ErrSnapshotDirIsNotAbsolute
+	}
+	return cfg, nil
 }
 
 func MustNewCacheConfig() CacheConfig {
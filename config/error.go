@@ -11,4 +11,17 @@ var (
 	ErrKafkaConfigPathIsEmpty    = errors.New("KAFKA_CONFIG_PATH is empty")
 	ErrApiConfigPathIsEmpty      = errors.New("API_CONFIG_PATH is empty")
 	ErrCacheConfigPathIsEmpty    = errors.New("CACHE_CONFIG_PATH is empty")
+	ErrKafkaOverflowDoesNotExist = errors.New("kafka producer overflow does not exist")
+	ErrKafkaSpillDirIsEmpty      = errors.New("kafka producer spill_dir is empty")
+	ErrSpillDirIsNotAbsolute     = errors.New("kafka producer spill_dir is not absolute")
+	ErrCacheCapacityUnitIsWrong  = errors.New("cache capacity_unit does not exist")
+	ErrCacheStrategyDoesNotExist = errors.New("cache strategy does not exist")
+	ErrSnapshotDirIsNotAbsolute  = errors.New("cache snapshot_dir is not absolute")
+	ErrJWTAlgorithmDoesNotExist  = errors.New("jwt algorithm does not exist")
+	ErrJWTKeyPathIsEmpty         = errors.New("jwt key_path is empty")
+	ErrAuthCredentialsAreEmpty   = errors.New("auth is enabled without API_KEYS and jwt")
+	ErrRBACPolicyPathIsEmpty     = errors.New("rbac policy_path is empty")
+	ErrRBACIsEnabledWithoutAuth  = errors.New("rbac is enabled without auth")
+	ErrRBACRoleDoesNotExist      = errors.New("rbac binding role does not exist")
+	ErrWorkingHoursAreNotValid   = errors.New("rbac working_hours are not valid")
 )
@@ -3,12 +3,41 @@ package config
 import (
 	"github.com/ilyakaznacheev/cleanenv"
 	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+const (
+	KafkaOverflowBlock = "block"
+	KafkaOverflowDrop  = "drop"
+	KafkaOverflowSpill = "spill"
 )
 
 type (
 	KafkaConfig struct {
 		Brokers     []string `yaml:"brokers"`
 		OnCallTopic string   `yaml:"on_call_topic"`
+		// CacheInvalidationTopic is empty when the orders cache isn't shared between instances.
+		CacheInvalidationTopic string `yaml:"cache_invalidation_topic"`
+		// OrderEventsTopic is empty when WatchOrders streams only the changes made on the instance.
+		OrderEventsTopic string              `yaml:"order_events_topic"`
+		Producer         KafkaProducerConfig `yaml:"producer"`
+		Topics           []KafkaTopicConfig  `yaml:"topics"`
+	}
+
+	KafkaTopicConfig struct {
+		Name              string        `yaml:"name"`
+		Partitions        int32         `yaml:"partitions"`
+		ReplicationFactor int16         `yaml:"replication_factor"`
+		Retention         time.Duration `yaml:"retention"`
+	}
+
+	KafkaProducerConfig struct {
+		BufferSize int    `yaml:"buffer_size"`
+		Overflow   string `yaml:"overflow" env-default:"block"`
+		// SpillDir is the absolute directory every binary spills its messages to, each to its own file.
+		SpillDir string `yaml:"spill_dir" env:"KAFKA_SPILL_DIR"`
 	}
 )
 
@@ -19,7 +48,20 @@ func NewKafkaConfig() (KafkaConfig, error) {
 	}
 	var cfg KafkaConfig
 	err := cleanenv.ReadConfig(path, &cfg)
-	return cfg, err
+	if err != nil {
+		return cfg, err
+	}
+
+	if !slices.Contains([]string{KafkaOverflowBlock, KafkaOverflowDrop, KafkaOverflowSpill}, cfg.Producer.Overflow) {
+		return cfg, ErrKafkaOverflowDoesNotExist
+	}
+	if cfg.Producer.Overflow == KafkaOverflowSpill && cfg.Producer.SpillDir == "" {
+		return cfg, ErrKafkaSpillDirIsEmpty
+	}
+	if cfg.Producer.SpillDir != "" && !filepath.IsAbs(cfg.Producer.SpillDir) {
+		return cfg, ErrSpillDirIsNotAbsolute
+	}
+	return cfg, nil
 }
 
 func MustNewKafkaConfig() KafkaConfig {
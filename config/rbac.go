@@ -0,0 +1,98 @@
+package config
+
+import (
+	"github.com/ilyakaznacheev/cleanenv"
+	"os"
+	"time"
+)
+
+const workingHoursLayout = "15:04"
+
+type (
+	RBACConfig struct {
+		Enabled    bool   `yaml:"enabled"`
+		PolicyPath string `yaml:"policy_path"`
+	}
+
+	RBACPolicy struct {
+		// PickupPoint is the pickup point served by this instance.
+		PickupPoint  string             `yaml:"pickup_point"`
+		WorkingHours WorkingHoursConfig `yaml:"working_hours"`
+		// WorkingHoursOnly are the methods allowed after hours only to roles with after_hours.
+		WorkingHoursOnly []string              `yaml:"working_hours_only"`
+		Roles            map[string]RoleConfig `yaml:"roles"`
+		Bindings         []BindingConfig       `yaml:"bindings"`
+	}
+
+	WorkingHoursConfig struct {
+		From     string `yaml:"from" env-default:"00:00"`
+		To       string `yaml:"to" env-default:"00:00"`
+		Location string `yaml:"location" env-default:"UTC"`
+	}
+
+	RoleConfig struct {
+		// Methods are full grpc names, /order.Order/* matches every method of the service and * matches any method.
+		Methods    []string `yaml:"methods"`
+		AfterHours bool     `yaml:"after_hours"`
+	}
+
+	// BindingConfig gives the role to the client authenticated with an api key or a token without the role claim.
+	BindingConfig struct {
+		Client string `yaml:"client"`
+		Role   string `yaml:"role"`
+		// PickupPoints the role is given at, * is any pickup point.
+		PickupPoints []string `yaml:"pickup_points"`
+	}
+)
+
+// NewRBACConfig reads only the rbac section of the api config, for the apps that don't serve the api.
+func NewRBACConfig() (RBACConfig, error) {
+	path := os.Getenv("API_CONFIG_PATH")
+	if path == "" {
+		return RBACConfig{}, ErrApiConfigPathIsEmpty
+	}
+	var cfg struct {
+		RBAC RBACConfig `yaml:"rbac"`
+	}
+	err := cleanenv.ReadConfig(path, &cfg)
+	return cfg.RBAC, err
+}
+
+func NewRBACPolicy(path string) (RBACPolicy, error) {
+	if path == "" {
+		return RBACPolicy{}, ErrRBACPolicyPathIsEmpty
+	}
+	var policy RBACPolicy
+	err := cleanenv.ReadConfig(path, &policy)
+	if err != nil {
+		return policy, err
+	}
+
+	for _, binding := range policy.Bindings {
+		if _, ok := policy.Roles[binding.Role]; !ok {
+			return policy, ErrRBACRoleDoesNotExist
+		}
+	}
+	if _, _, _, err := policy.WorkingHours.Parse(); err != nil {
+		return policy, ErrWorkingHoursAreNotValid
+	}
+	return policy, nil
+}
+
+// Parse returns the start and the end of the working hours as offsets from midnight in the location.
+func (c WorkingHoursConfig) Parse() (from, to time.Duration, location *time.Location, err error) {
+	start, err := time.Parse(workingHoursLayout, c.From)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	end, err := time.Parse(workingHoursLayout, c.To)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	location, err = time.LoadLocation(c.Location)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	midnight := time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC)
+	return start.Sub(midnight), end.Sub(midnight), location, nil
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"homework/pkg/api/admin/v1"
+	"homework/pkg/ds"
+)
+
+type (
+	AdminService struct {
+		cache ordersCache
+		admin.UnimplementedAdminServer
+	}
+
+	ordersCache interface {
+		Stats() ds.Stats
+		Keys() []string
+		Clear()
+		RemoveByIds(ids []string)
+	}
+)
+
+func NewAdminService(cache ordersCache) *AdminService {
+	return &AdminService{
+		cache: cache,
+	}
+}
+
+func (a *AdminService) CacheStats(ctx context.Context, _ *emptypb.Empty) (*admin.CacheStatsResponse, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "api.AdminService.CacheStats")
+	defer span.Finish()
+
+	stats := a.cache.Stats()
+	return &admin.CacheStatsResponse{
+		Hits:      stats.Hits,
+		Misses:    stats.Misses,
+		Evictions: stats.Evictions,
+		Size:      uint64(stats.Size),
+	}, nil
+}
+
+func (a *AdminService) CacheKeys(ctx context.Context, _ *emptypb.Empty) (*admin.CacheKeysResponse, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "api.AdminService.CacheKeys")
+	defer span.Finish()
+
+	return &admin.CacheKeysResponse{Keys: a.cache.Keys()}, nil
+}
+
+func (a *AdminService) FlushCache(ctx context.Context, req *admin.FlushCacheRequest) (*emptypb.Empty, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "api.AdminService.FlushCache")
+	defer span.Finish()
+
+	if err := req.ValidateAll(); err != nil {
+		return nil, invalidArgument(req, err)
+	}
+
+	if len(req.GetOrderIDs()) == 0 {
+		a.cache.Clear()
+	} else {
+		a.cache.RemoveByIds(req.GetOrderIDs())
+	}
+	return &emptypb.Empty{}, nil
+}
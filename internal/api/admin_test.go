@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"homework/internal/cache"
+	"homework/internal/model"
+	"homework/pkg/api/admin/v1"
+	"testing"
+	"time"
+)
+
+func TestAdmin_CacheStats(t *testing.T) {
+	t.Parallel()
+
+	ordersCache := cache.NewOrdersCache(10, time.Hour)
+	ordersCache.Put("key", []model.Order{{ID: "1"}})
+	ordersCache.Get("key")
+	ordersCache.Get("other")
+
+	resp, err := NewAdminService(ordersCache).CacheStats(context.Background(), &emptypb.Empty{})
+
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), resp.GetHits())
+	require.Equal(t, uint64(1), resp.GetMisses())
+	require.Equal(t, uint64(1), resp.GetSize())
+}
+
+func TestAdmin_FlushCache(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		req  *admin.FlushCacheRequest
+		keys []string
+	}{
+		{
+			name: "all",
+			req:  &admin.FlushCacheRequest{},
+			keys: []string{},
+		},
+		{
+			name: "by order id",
+			req:  &admin.FlushCacheRequest{OrderIDs: []string{"1"}},
+			keys: []string{"key2"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ordersCache := cache.NewOrdersCache(10, time.Hour)
+			ordersCache.Put("key1", []model.Order{{ID: "1"}})
+			ordersCache.Put("key2", []model.Order{{ID: "2"}})
+			service := NewAdminService(ordersCache)
+
+			_, err := service.FlushCache(context.Background(), tt.req)
+			require.NoError(t, err)
+
+			resp, err := service.CacheKeys(context.Background(), &emptypb.Empty{})
+			require.NoError(t, err)
+			require.ElementsMatch(t, tt.keys, resp.GetKeys())
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"homework/internal/eventbus"
+	"homework/internal/service"
+	"homework/internal/storage"
+	"strings"
+	"unicode"
+)
+
+const (
+	// ErrorDomain is the domain of every google.rpc.ErrorInfo the service returns.
+	ErrorDomain = "orders.homework"
+
+	ReasonOrderNotFound      = "ORDER_NOT_FOUND"
+	ReasonOrderAlreadyExists = "ORDER_ALREADY_EXISTS"
+
+	// the watcher relists the orders and watches without after_event_id
+	ReasonEventNotFound = "EVENT_NOT_FOUND"
+	ReasonEventExpired  = "EVENT_EXPIRED"
+	// the watcher resumes after the last event it has received
+	ReasonWatcherLagging = "WATCHER_LAGGING"
+	ReasonShuttingDown   = "SHUTTING_DOWN"
+)
+
+type (
+	// validationError is implemented by every error generated by protoc-gen-validate.
+	validationError interface {
+		Field() string
+		Reason() string
+		Cause() error
+	}
+
+	multiError interface {
+		AllErrors() []error
+	}
+)
+
+// toGRPCError attaches google.rpc.ErrorInfo with a stable reason, so clients don't parse the message.
+func toGRPCError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var orderServiceError service.OrderServiceError
+	if errors.As(err, &orderServiceError) {
+		code := codes.InvalidArgument
+		if orderServiceError.Kind() == service.KindFailedPrecondition {
+			code = codes.FailedPrecondition
+		}
+		var metadata map[string]string
+		if id := orderServiceError.OrderID(); id != "" {
+			metadata = map[string]string{"order_id": id}
+		}
+		return withDetails(status.New(code, err.Error()), errorInfo(orderServiceError.Reason(), metadata))
+	}
+
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		return withDetails(status.New(codes.NotFound, err.Error()), errorInfo(ReasonOrderNotFound, nil))
+	case errors.Is(err, storage.ErrDuplicateOrderID):
+		return withDetails(status.New(codes.AlreadyExists, err.Error()), errorInfo(ReasonOrderAlreadyExists, nil))
+	case errors.Is(err, eventbus.ErrEventNotFound):
+		return withDetails(status.New(codes.NotFound, err.Error()), errorInfo(ReasonEventNotFound, nil))
+	case errors.Is(err, eventbus.ErrEventIsExpired):
+		return withDetails(status.New(codes.OutOfRange, err.Error()), errorInfo(ReasonEventExpired, nil))
+	case errors.Is(err, eventbus.ErrSubscriptionIsLagging):
+		return withDetails(status.New(codes.ResourceExhausted, err.Error()), errorInfo(ReasonWatcherLagging, nil))
+	case errors.Is(err, eventbus.ErrBusIsClosed):
+		return withDetails(status.New(codes.Unavailable, err.Error()), errorInfo(ReasonShuttingDown, nil))
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// invalidArgument turns the errors of ValidateAll into google.rpc.BadRequest with the proto names of the fields.
+func invalidArgument(req proto.Message, err error) error {
+	var violations []*errdetails.BadRequest_FieldViolation
+	errs := []error{err}
+	var multi multiError
+	if errors.As(err, &multi) {
+		errs = multi.AllErrors()
+	}
+	for _, err := range errs {
+		violations = append(violations, fieldViolation(req, err))
+	}
+
+	return withDetails(status.New(codes.InvalidArgument, err.Error()), &errdetails.BadRequest{FieldViolations: violations})
+}
+
+// invalidField is a violation found outside of ValidateAll.
+func invalidField(field string, err error) error {
+	return withDetails(status.New(codes.InvalidArgument, err.Error()), &errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: field, Description: err.Error()}},
+	})
+}
+
+// fieldViolation follows the causes of nested messages, so the field is a path like order.expires_at.
+func fieldViolation(req proto.Message, err error) *errdetails.BadRequest_FieldViolation {
+	var path []string
+	description := err.Error()
+	message := req.ProtoReflect().Descriptor()
+	for err != nil {
+		var validation validationError
+		if !errors.As(err, &validation) {
+			break
+		}
+
+		// items of repeated fields are reported as Ids[0]
+		field, index, _ := strings.Cut(validation.Field(), "[")
+		if message != nil {
+			if fd := findField(message, field); fd != nil {
+				field = string(fd.Name())
+				message = fd.Message()
+			}
+		}
+		if index != "" {
+			field += "[" + index
+		}
+		path = append(path, field)
+		description = validation.Reason()
+		err = validation.Cause()
+	}
+
+	return &errdetails.BadRequest_FieldViolation{Field: strings.Join(path, "."), Description: description}
+}
+
+// findField matches the Go name protoc-gen-validate reports to the field of the message.
+func findField(message protoreflect.MessageDescriptor, goName string) protoreflect.FieldDescriptor {
+	fields := message.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if goCamelCase(string(fields.Get(i).Name())) == goName {
+			return fields.Get(i)
+		}
+	}
+	return nil
+}
+
+func goCamelCase(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func errorInfo(reason string, metadata map[string]string) *errdetails.ErrorInfo {
+	return &errdetails.ErrorInfo{Reason: reason, Domain: ErrorDomain, Metadata: metadata}
+}
+
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) error {
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"homework/internal/service"
+	"homework/internal/storage"
+	orderv1 "homework/pkg/api/order/v1"
+	"homework/pkg/api/order/v2"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func errorInfoOf(t *testing.T, err error) *errdetails.ErrorInfo {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info
+		}
+	}
+	require.Fail(t, "no error info", err)
+	return nil
+}
+
+func fieldsOf(t *testing.T, err error) []string {
+	var fields []string
+	for _, detail := range status.Convert(err).Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.GetFieldViolations() {
+				fields = append(fields, violation.GetField())
+			}
+		}
+	}
+	return fields
+}
+
+func TestToGRPCError(t *testing.T) {
+	t.Parallel()
+
+	type test struct {
+		name   string
+		err    error
+		code   codes.Code
+		reason string
+	}
+
+	tests := []test{
+		{
+			name:   "order expired",
+			err:    service.ErrOrderHasExpired,
+			code:   codes.FailedPrecondition,
+			reason: "ORDER_EXPIRED",
+		},
+		{
+			name:   "wrapped wrong recipient",
+			err:    errors.Wrap(service.ErrWrongRecipient, "refund"),
+			code:   codes.FailedPrecondition,
+			reason: "WRONG_RECIPIENT",
+		},
+		{
+			name:   "expiration date in the past",
+			err:    service.ErrExpIsNotValid,
+			code:   codes.InvalidArgument,
+			reason: "EXPIRATION_DATE_IN_PAST",
+		},
+		{
+			name:   "not found",
+			err:    storage.ErrNotFound,
+			code:   codes.NotFound,
+			reason: ReasonOrderNotFound,
+		},
+		{
+			name:   "duplicate",
+			err:    storage.ErrDuplicateOrderID,
+			code:   codes.AlreadyExists,
+			reason: ReasonOrderAlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := toGRPCError(tt.err)
+
+			require.Equal(t, tt.code, status.Code(err))
+			info := errorInfoOf(t, err)
+			require.Equal(t, tt.reason, info.GetReason())
+			require.Equal(t, ErrorDomain, info.GetDomain())
+		})
+	}
+
+	require.NoError(t, toGRPCError(nil))
+	require.Equal(t, codes.Internal, status.Code(toGRPCError(errors.New("connection refused"))))
+}
+
+func TestToGRPCError_OrderID(t *testing.T) {
+	t.Parallel()
+
+	err := toGRPCError(service.ErrOrderHasExpired.WithOrderID("7"))
+
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	require.Equal(t, map[string]string{"order_id": "7"}, errorInfoOf(t, err).GetMetadata())
+	require.Empty(t, errorInfoOf(t, toGRPCError(service.ErrOrderHasExpired)).GetMetadata())
+}
+
+func TestInvalidArgument(t *testing.T) {
+	t.Parallel()
+
+	v2 := &order.DeliverOrderRequest{ExpiresAt: timestamppb.New(time.Now().Add(-time.Hour)), PriceRub: "1,5"}
+	err := invalidArgument(v2, v2.ValidateAll())
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Equal(t, []string{"order_id", "recipient_id", "expires_at", "weight_kg", "price_rub"}, fieldsOf(t, err))
+
+	v1 := &orderv1.IssueOrdersRequest{Ids: []string{"1", ""}}
+	err = invalidArgument(v1, v1.ValidateAll())
+	require.Equal(t, []string{"ids[1]"}, fieldsOf(t, err))
+}
+
+func TestGatewayRendersDetails(t *testing.T) {
+	t.Parallel()
+
+	mux := runtime.NewServeMux()
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/v2/orders/1:issue", nil)
+
+	err := toGRPCError(service.ErrOrderHasExpired)
+	runtime.DefaultHTTPErrorHandler(context.Background(), mux, &runtime.JSONPb{}, recorder, request, err)
+
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	var body struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Details []struct {
+			Type   string `json:"@type"`
+			Reason string `json:"reason"`
+			Domain string `json:"domain"`
+		} `json:"details"`
+	}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&body))
+	require.Equal(t, int(codes.FailedPrecondition), body.Code)
+	require.Len(t, body.Details, 1)
+	require.Equal(t, "type.googleapis.com/google.rpc.ErrorInfo", body.Details[0].Type)
+	require.Equal(t, "ORDER_EXPIRED", body.Details[0].Reason)
+	require.Equal(t, ErrorDomain, body.Details[0].Domain)
+}
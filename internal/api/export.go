@@ -0,0 +1,156 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"homework/internal/dto"
+	"homework/internal/export"
+	"homework/internal/model"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+)
+
+const (
+	ExportOrdersPath = "/v2/orders:export"
+	// ExportOrdersMethod is the name the export is authorized, limited and audited by,
+	// so the roles with /order.v2.OrderService/* are allowed to export.
+	ExportOrdersMethod = "/order.v2.OrderService/ExportOrders"
+)
+
+var errStatusIsNotValid = errors.New("status is not valid")
+
+type (
+	orderExporter interface {
+		ExportOrders(ctx context.Context, param dto.ExportOrdersParam, write func([]dto.ExportedOrder) error) error
+	}
+
+	httpGuard interface {
+		Check(r *http.Request, method string) (context.Context, func(error), error)
+	}
+
+	// ExportHandler streams the orders as CSV or NDJSON as they are fetched from the database.
+	ExportHandler struct {
+		service orderExporter
+		guard   httpGuard
+		timeout time.Duration
+	}
+)
+
+// NewExportHandler cancels an export that takes longer than timeout, 0 doesn't limit it.
+func NewExportHandler(service orderExporter, guard httpGuard, timeout time.Duration) *ExportHandler {
+	return &ExportHandler{service: service, guard: guard, timeout: timeout}
+}
+
+// Export takes format, status, recipient_id, from and to query parameters. An error after
+// the first orders have been sent aborts the response, so a cut export isn't taken for a whole one.
+func (h *ExportHandler) Export(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	ctx, finish, err := h.guard.Check(r, ExportOrdersMethod)
+	if err != nil {
+		writeHTTPError(w, err)
+		return
+	}
+	// an aborted export is finished with its error too
+	var callErr error
+	defer func() {
+		finish(callErr)
+	}()
+
+	if h.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.ExportHandler.Export")
+	defer span.Finish()
+
+	format, param, err := parseExportQuery(r)
+	if err != nil {
+		callErr = err
+		writeHTTPError(w, err)
+		return
+	}
+
+	var writer *export.Writer
+	start := func() error {
+		w.Header().Set("Content-Type", format.ContentType())
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=orders.%s", format))
+		writer, err = export.NewWriter(w, format)
+		return err
+	}
+	flusher, _ := w.(http.Flusher)
+
+	err = h.service.ExportOrders(ctx, param, func(orders []dto.ExportedOrder) error {
+		if writer == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if err := writer.Write(orders); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err == nil && writer == nil {
+		err = start()
+	}
+	if err == nil {
+		return
+	}
+	callErr = toGRPCError(err)
+	if writer == nil {
+		writeHTTPError(w, callErr)
+		return
+	}
+
+	log.Printf("[api.ExportHandler] export is aborted: %v", err)
+	panic(http.ErrAbortHandler)
+}
+
+func parseExportQuery(r *http.Request) (export.Format, dto.ExportOrdersParam, error) {
+	query := r.URL.Query()
+
+	format, err := export.ParseFormat(query.Get("format"))
+	if err != nil {
+		return "", dto.ExportOrdersParam{}, invalidField("format", err)
+	}
+
+	param := dto.ExportOrdersParam{
+		Status:      model.Status(query.Get("status")),
+		RecipientID: query.Get("recipient_id"),
+	}
+	statuses := []model.Status{model.StatusNone, model.StatusDelivered, model.StatusIssued, model.StatusRefunded}
+	if !slices.Contains(statuses, param.Status) {
+		return "", dto.ExportOrdersParam{}, invalidField("status", errStatusIsNotValid)
+	}
+	if param.From, err = export.ParseTime(query.Get("from")); err != nil {
+		return "", dto.ExportOrdersParam{}, invalidField("from", err)
+	}
+	if param.To, err = export.ParseTime(query.Get("to")); err != nil {
+		return "", dto.ExportOrdersParam{}, invalidField("to", err)
+	}
+	return format, param, nil
+}
+
+// writeHTTPError writes the status like the gateway does for the grpc methods.
+func writeHTTPError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+	body, marshalErr := protojson.Marshal(st.Proto())
+	if marshalErr != nil {
+		body = []byte(`{"code": 13, "message": "failed to marshal error message"}`)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(runtime.HTTPStatusFromCode(st.Code()))
+	_, _ = w.Write(body)
+}
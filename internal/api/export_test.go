@@ -0,0 +1,185 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"homework/internal/dto"
+	"homework/internal/model"
+	"homework/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type fakeGuard struct {
+	err error
+	// finished is the result of the call, nil if finish hasn't been called.
+	finished *error
+}
+
+func (g fakeGuard) Check(r *http.Request, method string) (context.Context, func(error), error) {
+	return r.Context(), func(err error) {
+		if g.finished != nil {
+			*g.finished = err
+		}
+	}, g.err
+}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	type test struct {
+		name        string
+		url         string
+		guard       fakeGuard
+		mockFn      func(m mocks)
+		code        int
+		contentType string
+		body        string
+	}
+
+	deliveredAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	exported := dto.ExportedOrder{
+		Order:       model.Order{ID: "1", RecipientID: "2", Status: model.StatusIssued, WeightInGram: 1},
+		DeliveredAt: deliveredAt,
+	}
+	write := func(batches ...[]dto.ExportedOrder) func(context.Context, dto.ExportOrdersParam, func([]dto.ExportedOrder) error) error {
+		return func(_ context.Context, _ dto.ExportOrdersParam, write func([]dto.ExportedOrder) error) error {
+			for _, batch := range batches {
+				if err := write(batch); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	tests := []test{
+		{
+			name: "ndjson",
+			url:  "/v2/orders:export?format=ndjson&status=issued&recipient_id=2&from=2024-06-01&to=2024-07-01",
+			mockFn: func(m mocks) {
+				param := dto.ExportOrdersParam{
+					Status:      model.StatusIssued,
+					RecipientID: "2",
+					From:        deliveredAt.Add(-12 * time.Hour),
+					To:          time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+				}
+				m.mockOrderService.EXPECT().ExportOrders(gomock.Any(), param, gomock.Any()).
+					DoAndReturn(write([]dto.ExportedOrder{exported}, []dto.ExportedOrder{exported})).Times(1)
+			},
+			code:        http.StatusOK,
+			contentType: "application/x-ndjson",
+			body: `{"order_id":"1","recipient_id":"2","status":"issued","delivered_at":"2024-06-01T12:00:00Z",` +
+				`"status_updated_at":"0001-01-01T00:00:00Z","expiration_date":"0001-01-01T00:00:00Z","weight_in_gram":1,"price_in_rub":0}` + "\n" +
+				`{"order_id":"1","recipient_id":"2","status":"issued","delivered_at":"2024-06-01T12:00:00Z",` +
+				`"status_updated_at":"0001-01-01T00:00:00Z","expiration_date":"0001-01-01T00:00:00Z","weight_in_gram":1,"price_in_rub":0}` + "\n",
+		},
+		{
+			name: "empty csv",
+			url:  "/v2/orders:export",
+			mockFn: func(m mocks) {
+				m.mockOrderService.EXPECT().ExportOrders(gomock.Any(), dto.ExportOrdersParam{}, gomock.Any()).
+					DoAndReturn(write()).Times(1)
+			},
+			code:        http.StatusOK,
+			contentType: "text/csv; charset=utf-8",
+			body:        "order_id,recipient_id,status,delivered_at,status_updated_at,expiration_date,weight_in_gram,price_in_rub,wrapper,wrapper_price_in_rub\n",
+		},
+		{
+			name:        "status is not valid",
+			url:         "/v2/orders:export?status=returned",
+			code:        http.StatusBadRequest,
+			contentType: "application/json",
+		},
+		{
+			name:        "from is not valid",
+			url:         "/v2/orders:export?from=01.06.2024",
+			code:        http.StatusBadRequest,
+			contentType: "application/json",
+		},
+		{
+			name: service.ErrExportPeriodIsNotValid.Error(),
+			url:  "/v2/orders:export?from=2024-07-01&to=2024-06-01",
+			mockFn: func(m mocks) {
+				m.mockOrderService.EXPECT().ExportOrders(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(service.ErrExportPeriodIsNotValid).Times(1)
+			},
+			code:        http.StatusBadRequest,
+			contentType: "application/json",
+		},
+		{
+			name:        "unauthenticated",
+			url:         "/v2/orders:export",
+			guard:       fakeGuard{err: status.Error(codes.Unauthenticated, "credentials are missing")},
+			code:        http.StatusUnauthorized,
+			contentType: "application/json",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := newMocks(t)
+			if tt.mockFn != nil {
+				tt.mockFn(m)
+			}
+			handler := NewExportHandler(m.mockOrderService, tt.guard, time.Minute)
+			recorder := httptest.NewRecorder()
+
+			handler.Export(recorder, httptest.NewRequest(http.MethodGet, tt.url, nil), nil)
+
+			require.Equal(t, tt.code, recorder.Code)
+			require.Equal(t, tt.contentType, recorder.Header().Get("Content-Type"))
+			if tt.body != "" {
+				require.Equal(t, tt.body, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestExport_Aborted(t *testing.T) {
+	t.Parallel()
+
+	m := newMocks(t)
+	m.mockOrderService.EXPECT().ExportOrders(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ dto.ExportOrdersParam, write func([]dto.ExportedOrder) error) error {
+			require.NoError(t, write([]dto.ExportedOrder{{Order: model.Order{ID: "1"}}}))
+			return errors.New("connection is lost")
+		}).Times(1)
+	var finished error
+	handler := NewExportHandler(m.mockOrderService, fakeGuard{finished: &finished}, time.Minute)
+	recorder := httptest.NewRecorder()
+
+	require.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler.Export(recorder, httptest.NewRequest(http.MethodGet, "/v2/orders:export", nil), nil)
+	})
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, codes.Internal, status.Code(finished))
+}
+
+func TestExport_Timeout(t *testing.T) {
+	t.Parallel()
+
+	m := newMocks(t)
+	m.mockOrderService.EXPECT().ExportOrders(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ dto.ExportOrdersParam, _ func([]dto.ExportedOrder) error) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}).Times(1)
+	var finished error
+	handler := NewExportHandler(m.mockOrderService, fakeGuard{finished: &finished}, 10*time.Millisecond)
+	recorder := httptest.NewRecorder()
+
+	handler.Export(recorder, httptest.NewRequest(http.MethodGet, "/v2/orders:export", nil), nil)
+
+	require.Equal(t, http.StatusGatewayTimeout, recorder.Code)
+	require.Equal(t, codes.DeadlineExceeded, status.Code(finished))
+}
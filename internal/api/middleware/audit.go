@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"encoding/json"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const redacted = "***"
+
+type Audit struct {
+	allow  map[string]struct{}
+	deny   map[string]struct{}
+	redact map[string]struct{}
+}
+
+func NewAudit(allow, deny, redact []string) Audit {
+	return Audit{
+		allow:  toSet(allow),
+		deny:   toSet(deny),
+		redact: toSet(redact),
+	}
+}
+
+func (a Audit) Enabled(method string) bool {
+	if _, ok := a.deny[method]; ok {
+		return false
+	}
+	if len(a.allow) == 0 {
+		return true
+	}
+	_, ok := a.allow[method]
+	return ok
+}
+
+func (a Audit) Args(message any) string {
+	protoMessage, ok := message.(proto.Message)
+	if !ok {
+		return ""
+	}
+
+	raw, err := protojson.Marshal(protoMessage)
+	if err != nil || len(a.redact) == 0 {
+		return string(raw)
+	}
+
+	var args any
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return string(raw)
+	}
+
+	raw, err = json.Marshal(a.redactValue(args))
+	if err != nil {
+		return ""
+	}
+	return string(raw)
+}
+
+func (a Audit) redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if _, ok := a.redact[key]; ok {
+				v[key] = redacted
+				continue
+			}
+			v[key] = a.redactValue(field)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = a.redactValue(item)
+		}
+	}
+	return value
+}
+
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"github.com/stretchr/testify/require"
+	"homework/pkg/api/order/v1"
+	"testing"
+)
+
+const (
+	listOrders  = "/order.Order/ListOrders"
+	returnOrder = "/order.Order/ReturnOrder"
+)
+
+func TestAudit_Enabled(t *testing.T) {
+	t.Parallel()
+
+	type test struct {
+		name   string
+		audit  Audit
+		method string
+		want   bool
+	}
+
+	tests := []test{
+		{
+			name:   "empty lists",
+			audit:  NewAudit(nil, nil, nil),
+			method: listOrders,
+			want:   true,
+		},
+		{
+			name:   "denied",
+			audit:  NewAudit(nil, []string{listOrders}, nil),
+			method: listOrders,
+			want:   false,
+		},
+		{
+			name:   "not allowed",
+			audit:  NewAudit([]string{returnOrder}, nil, nil),
+			method: listOrders,
+			want:   false,
+		},
+		{
+			name:   "deny wins over allow",
+			audit:  NewAudit([]string{listOrders}, []string{listOrders}, nil),
+			method: listOrders,
+			want:   false,
+		},
+		{
+			name:   "allowed",
+			audit:  NewAudit([]string{returnOrder}, []string{listOrders}, nil),
+			method: returnOrder,
+			want:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, tt.audit.Enabled(tt.method))
+		})
+	}
+}
+
+func TestAudit_Args(t *testing.T) {
+	t.Parallel()
+
+	audit := NewAudit(nil, nil, []string{"userID"})
+
+	args := audit.Args(&order.RefundOrderRequest{UserID: "1", OrderID: "2"})
+
+	require.JSONEq(t, `{"userID":"***","orderID":"2"}`, args)
+}
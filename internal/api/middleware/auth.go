@@ -0,0 +1,242 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"strings"
+	"time"
+)
+
+const (
+	ApiKeyHeader        = "x-api-key"
+	AuthorizationHeader = "authorization"
+	bearer              = "bearer "
+	// rejectedStreamWait bounds the wait for the first message of a rejected stream
+	rejectedStreamWait = time.Second
+
+	AuthApiKey = "api_key"
+	AuthJWT    = "jwt"
+)
+
+var (
+	errUnauthenticated = status.Error(codes.Unauthenticated, "credentials are missing")
+	errInvalidApiKey   = status.Error(codes.Unauthenticated, "api key is invalid")
+)
+
+type (
+	// Principal is the authenticated client of the call.
+	Principal struct {
+		Name string
+		// Method is how the client has been authenticated: api_key or jwt.
+		Method string
+		// Role and PickupPoints come from the token claims, empty for api keys.
+		Role         string
+		PickupPoints []string
+	}
+
+	principalKey struct{}
+
+	Authenticator struct {
+		// keys are hashed, so the lookup time doesn't depend on how much of the key matches.
+		keys   map[[sha256.Size]byte]string
+		public map[string]struct{}
+
+		jwtAlgorithm string
+		jwtKey       any
+		issuer       string
+		audience     string
+	}
+
+	authServerStream struct {
+		grpc.ServerStream
+		ctx context.Context
+	}
+)
+
+// NewAuthenticator takes the api keys by client names, an empty jwtAlgorithm disables tokens.
+// jwtKey is the HS256 secret or the PEM encoded RS256 public key.
+func NewAuthenticator(keys map[string]string, public []string, jwtAlgorithm string, jwtKey []byte, issuer, audience string) (Authenticator, error) {
+	a := Authenticator{
+		keys:         make(map[[sha256.Size]byte]string, len(keys)),
+		public:       toSet(public),
+		jwtAlgorithm: jwtAlgorithm,
+		issuer:       issuer,
+		audience:     audience,
+	}
+	for client, key := range keys {
+		a.keys[sha256.Sum256([]byte(key))] = client
+	}
+
+	switch jwtAlgorithm {
+	case "":
+	case jwt.SigningMethodHS256.Alg():
+		a.jwtKey = []byte(strings.TrimSpace(string(jwtKey)))
+	case jwt.SigningMethodRS256.Alg():
+		key, err := jwt.ParseRSAPublicKeyFromPEM(jwtKey)
+		if err != nil {
+			return a, err
+		}
+		a.jwtKey = key
+	default:
+		return a, fmt.Errorf("jwt algorithm %s is not supported", jwtAlgorithm)
+	}
+	return a, nil
+}
+
+// Auth rejects the calls without valid credentials, the rejections are sent to the on-call audit
+// whatever the audit filter is, like RBAC denials.
+func Auth(a Authenticator, producer onCallProducer, audit Audit) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := a.Authenticate(ctx, info.FullMethod)
+		if err != nil {
+			send(ctx, producer, newOnCallMessage(ctx, info.FullMethod, audit.Args(req), time.Now(), err))
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// AuthStream audits the rejected call with its first message like Auth, the handler isn't called.
+func AuthStream(a Authenticator, producer onCallProducer, audit Audit) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.Authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			args := rejectedStreamArgs(ss, info.FullMethod, audit)
+			send(ctx, producer, newOnCallMessage(ctx, info.FullMethod, args, time.Now(), err))
+			return err
+		}
+		return handler(srv, &authServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func (s *authServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// rejectedStreamArgs receives the first message of the stream into a message built from the descriptor of
+// the method, since there is no handler to receive it. A client that doesn't send it in rejectedStreamWait
+// is audited without the args.
+func rejectedStreamArgs(ss grpc.ServerStream, method string, audit Audit) string {
+	name := protoreflect.FullName(strings.ReplaceAll(strings.TrimPrefix(method, "/"), "/", "."))
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(name)
+	if err != nil {
+		return ""
+	}
+	methodDesc, ok := desc.(protoreflect.MethodDescriptor)
+	if !ok {
+		return ""
+	}
+
+	args := make(chan string, 1)
+	go func() {
+		message := dynamicpb.NewMessage(methodDesc.Input())
+		if err := ss.RecvMsg(message); err != nil {
+			args <- ""
+			return
+		}
+		args <- audit.Args(message)
+	}()
+
+	select {
+	case a := <-args:
+		return a
+	case <-time.After(rejectedStreamWait):
+		return ""
+	}
+}
+
+// Authenticate puts the principal into the context, public methods are called without it.
+func (a Authenticator) Authenticate(ctx context.Context, method string) (context.Context, error) {
+	if _, ok := a.public[method]; ok {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if key := first(md.Get(ApiKeyHeader)); key != "" {
+		client, ok := a.keys[sha256.Sum256([]byte(key))]
+		if !ok {
+			return ctx, errInvalidApiKey
+		}
+		return ContextWithPrincipal(ctx, Principal{Name: client, Method: AuthApiKey}), nil
+	}
+
+	authorization := first(md.Get(AuthorizationHeader))
+	if len(authorization) > len(bearer) && strings.EqualFold(authorization[:len(bearer)], bearer) {
+		principal, err := a.parseJWT(authorization[len(bearer):])
+		if err != nil {
+			return ctx, status.Errorf(codes.Unauthenticated, "token is invalid: %v", err)
+		}
+		return ContextWithPrincipal(ctx, principal), nil
+	}
+	return ctx, errUnauthenticated
+}
+
+func (a Authenticator) parseJWT(raw string) (Principal, error) {
+	if a.jwtAlgorithm == "" {
+		return Principal{}, errors.New("tokens are disabled")
+	}
+
+	options := []jwt.ParserOption{jwt.WithValidMethods([]string{a.jwtAlgorithm}), jwt.WithExpirationRequired()}
+	if a.issuer != "" {
+		options = append(options, jwt.WithIssuer(a.issuer))
+	}
+	if a.audience != "" {
+		options = append(options, jwt.WithAudience(a.audience))
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, func(*jwt.Token) (any, error) {
+		return a.jwtKey, nil
+	}, options...)
+	if err != nil {
+		return Principal{}, err
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return Principal{}, errors.New("subject is empty")
+	}
+	role, _ := claims["role"].(string)
+	var pickupPoints []string
+	if points, ok := claims["pickup_points"].([]any); ok {
+		for _, point := range points {
+			if point, ok := point.(string); ok {
+				pickupPoints = append(pickupPoints, point)
+			}
+		}
+	}
+	return Principal{Name: subject, Method: AuthJWT, Role: role, PickupPoints: pickupPoints}, nil
+}
+
+func ContextWithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func last(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[len(values)-1]
+}
@@ -0,0 +1,218 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"homework/pkg/api/order/v1"
+	"testing"
+	"time"
+)
+
+const (
+	testMethod   = "/order.Order/DeliverOrder"
+	publicMethod = "/grpc.health.v1.Health/Check"
+	secret       = "secret"
+)
+
+func signHS256(t *testing.T, claims jwt.MapClaims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	require.NoError(t, err)
+	return token
+}
+
+func TestAuth(t *testing.T) {
+	t.Parallel()
+
+	authenticator, err := NewAuthenticator(map[string]string{"courier": "key"}, []string{publicMethod},
+		jwt.SigningMethodHS256.Alg(), []byte(secret+"\n"), "orders", "")
+	require.NoError(t, err)
+
+	type test struct {
+		name      string
+		method    string
+		md        metadata.MD
+		code      codes.Code
+		principal Principal
+	}
+
+	tests := []test{
+		{
+			name:      "api key",
+			method:    testMethod,
+			md:        metadata.Pairs(ApiKeyHeader, "key"),
+			code:      codes.OK,
+			principal: Principal{Name: "courier", Method: AuthApiKey},
+		},
+		{
+			name:   "wrong api key",
+			method: testMethod,
+			md:     metadata.Pairs(ApiKeyHeader, "wrong"),
+			code:   codes.Unauthenticated,
+		},
+		{
+			name:   "no credentials",
+			method: testMethod,
+			md:     metadata.MD{},
+			code:   codes.Unauthenticated,
+		},
+		{
+			name:   "public method",
+			method: publicMethod,
+			md:     metadata.MD{},
+			code:   codes.OK,
+		},
+		{
+			name:   "jwt",
+			method: testMethod,
+			md: metadata.Pairs(AuthorizationHeader, "Bearer "+signHS256(t, jwt.MapClaims{
+				"sub": "operator", "iss": "orders", "exp": time.Now().Add(time.Hour).Unix(),
+			})),
+			code:      codes.OK,
+			principal: Principal{Name: "operator", Method: AuthJWT},
+		},
+		{
+			name:   "expired jwt",
+			method: testMethod,
+			md: metadata.Pairs(AuthorizationHeader, "Bearer "+signHS256(t, jwt.MapClaims{
+				"sub": "operator", "iss": "orders", "exp": time.Now().Add(-time.Hour).Unix(),
+			})),
+			code: codes.Unauthenticated,
+		},
+		{
+			name:   "jwt without expiration",
+			method: testMethod,
+			md: metadata.Pairs(AuthorizationHeader, "Bearer "+signHS256(t, jwt.MapClaims{
+				"sub": "operator", "iss": "orders",
+			})),
+			code: codes.Unauthenticated,
+		},
+		{
+			name:   "wrong issuer",
+			method: testMethod,
+			md: metadata.Pairs(AuthorizationHeader, "Bearer "+signHS256(t, jwt.MapClaims{
+				"sub": "operator", "iss": "other", "exp": time.Now().Add(time.Hour).Unix(),
+			})),
+			code: codes.Unauthenticated,
+		},
+		{
+			name:   "none algorithm",
+			method: testMethod,
+			md:     metadata.Pairs(AuthorizationHeader, "Bearer eyJhbGciOiJub25lIn0.eyJzdWIiOiJvcGVyYXRvciIsImlzcyI6Im9yZGVycyJ9."),
+			code:   codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			info := &grpc.UnaryServerInfo{FullMethod: tt.method}
+
+			producer := &producer{}
+			var principal Principal
+			_, err := Auth(authenticator, producer, NewAudit(nil, nil, nil))(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+				principal, _ = PrincipalFromContext(ctx)
+				return nil, nil
+			})
+
+			require.Equal(t, tt.code, status.Code(err))
+			require.Equal(t, tt.principal, principal)
+			if tt.code == codes.OK {
+				require.Empty(t, producer.messages)
+				return
+			}
+			require.Len(t, producer.messages, 1)
+			require.Equal(t, codes.Unauthenticated.String(), producer.messages[0].Code)
+			require.Equal(t, tt.method, producer.messages[0].Method)
+		})
+	}
+}
+
+func TestAuth_RS256(t *testing.T) {
+	t.Parallel()
+
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	public, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	require.NoError(t, err)
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: public})
+
+	authenticator, err := NewAuthenticator(nil, nil, jwt.SigningMethodRS256.Alg(), publicPEM, "", "")
+	require.NoError(t, err)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "supervisor", "exp": time.Now().Add(time.Hour).Unix()}).SignedString(private)
+	require.NoError(t, err)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(AuthorizationHeader, "Bearer "+token))
+
+	ctx, err = authenticator.Authenticate(ctx, testMethod)
+	require.NoError(t, err)
+	principal, ok := PrincipalFromContext(ctx)
+	require.True(t, ok)
+	require.Equal(t, Principal{Name: "supervisor", Method: AuthJWT}, principal)
+
+	// a token signed with the public key as HS256 secret must not pass
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "admin", "exp": time.Now().Add(time.Hour).Unix()}).SignedString(publicPEM)
+	require.NoError(t, err)
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(AuthorizationHeader, "Bearer "+forged))
+
+	_, err = authenticator.Authenticate(ctx, testMethod)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+// rawServerStream sends the message encoded, the way the transport does.
+type rawServerStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	message proto.Message
+}
+
+func (s *rawServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *rawServerStream) RecvMsg(m any) error {
+	raw, err := proto.Marshal(s.message)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(raw, m.(proto.Message))
+}
+
+func TestAuthStream_Rejected(t *testing.T) {
+	t.Parallel()
+
+	authenticator, err := NewAuthenticator(map[string]string{"courier": "key"}, nil, "", nil, "", "")
+	require.NoError(t, err)
+
+	producer := &producer{}
+	stream := &rawServerStream{
+		ctx:     metadata.NewIncomingContext(context.Background(), metadata.Pairs(ApiKeyHeader, "wrong")),
+		message: &order.IssueOrdersRequest{Ids: []string{"1"}},
+	}
+	info := &grpc.StreamServerInfo{FullMethod: "/order.Order/IssueOrders"}
+
+	called := false
+	err = AuthStream(authenticator, producer, NewAudit(nil, nil, []string{"ids"}))(nil, stream, info,
+		func(srv any, stream grpc.ServerStream) error {
+			called = true
+			return nil
+		})
+
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	require.False(t, called)
+	require.Len(t, producer.messages, 1)
+	require.Equal(t, info.FullMethod, producer.messages[0].Method)
+	require.JSONEq(t, `{"ids":"***"}`, producer.messages[0].Args)
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"context"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/types/known/structpb"
+	"homework/internal/dto"
+	"net/http"
+	"time"
+)
+
+type (
+	// HTTPGuard checks the calls of the handlers registered on the gateway mux with HandlePath,
+	// they don't go through the interceptors. Nil checks are skipped, like the disabled interceptors.
+	HTTPGuard struct {
+		inFlight *InFlightLimiter
+		auth     *Authenticator
+		limiter  *RateLimiter
+		rbac     *RBAC
+		producer onCallProducer
+		audit    Audit
+	}
+
+	httpAddr string
+)
+
+// NewHTTPGuard takes the checks in the order of the interceptors, the calls are audited to the producer like OnCall does.
+func NewHTTPGuard(inFlight *InFlightLimiter, auth *Authenticator, limiter *RateLimiter, rbac *RBAC, producer onCallProducer, audit Audit) HTTPGuard {
+	return HTTPGuard{inFlight: inFlight, auth: auth, limiter: limiter, rbac: rbac, producer: producer, audit: audit}
+}
+
+// Check takes an in-flight slot, authenticates, limits and authorizes the request as a call of method,
+// the context has the principal. finish must be called with the result once the call is handled:
+// it frees the slot and audits the call with the query as the arguments.
+func (g HTTPGuard) Check(r *http.Request, method string) (context.Context, func(error), error) {
+	calledAt := time.Now()
+	md := metadata.MD{}
+	if key := r.Header.Get(ApiKeyHeader); key != "" {
+		md.Set(ApiKeyHeader, key)
+	}
+	if authorization := r.Header.Get(AuthorizationHeader); authorization != "" {
+		md.Set(AuthorizationHeader, authorization)
+	}
+	ctx := metadata.NewIncomingContext(r.Context(), md)
+	ctx = peer.NewContext(ctx, &peer.Peer{Addr: httpAddr(r.RemoteAddr)})
+
+	if g.inFlight != nil {
+		if !g.inFlight.acquire() {
+			return ctx, nil, g.inFlight.reject(ctx, method)
+		}
+	}
+	ctx, err := g.check(ctx, method)
+	if err != nil {
+		g.release()
+		return ctx, nil, err
+	}
+
+	return ctx, func(err error) {
+		g.release()
+		if g.audit.Enabled(method) {
+			g.send(ctx, newOnCallMessage(ctx, method, g.args(r), calledAt, err))
+		}
+	}, nil
+}
+
+func (g HTTPGuard) check(ctx context.Context, method string) (context.Context, error) {
+	var err error
+	if g.auth != nil {
+		if ctx, err = g.auth.Authenticate(ctx, method); err != nil {
+			g.send(ctx, newOnCallMessage(ctx, method, "", time.Now(), err))
+			return ctx, err
+		}
+	}
+	if g.limiter != nil {
+		if err := g.limiter.Allow(ctx, method); err != nil {
+			return ctx, err
+		}
+	}
+	if g.rbac != nil {
+		if err := g.rbac.Authorize(ctx, method); err != nil {
+			g.rbac.deny(ctx, method, "", err)
+			return ctx, err
+		}
+	}
+	return ctx, nil
+}
+
+// send skips the message if the guard has no producer.
+func (g HTTPGuard) send(ctx context.Context, message dto.OnCallMessage) {
+	if g.producer != nil {
+		send(ctx, g.producer, message)
+	}
+}
+
+func (g HTTPGuard) release() {
+	if g.inFlight != nil {
+		g.inFlight.release()
+	}
+}
+
+// args are the query parameters, redacted like the fields of the grpc requests.
+func (g HTTPGuard) args(r *http.Request) string {
+	query := make(map[string]any)
+	for key, values := range r.URL.Query() {
+		query[key] = values[0]
+	}
+	args, err := structpb.NewStruct(query)
+	if err != nil {
+		return ""
+	}
+	return g.audit.Args(args)
+}
+
+func (a httpAddr) Network() string {
+	return "tcp"
+}
+
+func (a httpAddr) String() string {
+	return string(a)
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPGuard(t *testing.T) {
+	t.Parallel()
+
+	authenticator, err := NewAuthenticator(map[string]string{"courier": "key"}, nil, "", nil, "", "")
+	require.NoError(t, err)
+	limiter := NewRateLimiter(Limit{Rate: 0.001, Burst: 1}, nil, 10)
+	producer := &producer{}
+	audit := NewAudit(nil, nil, []string{"recipient_id"})
+	rbac := NewRBAC(testPolicy(), producer, audit)
+	guard := NewHTTPGuard(nil, &authenticator, limiter, &rbac, producer, audit)
+	newRequest := func(key string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/v2/orders:export?status=issued&recipient_id=1", nil)
+		if key != "" {
+			r.Header.Set("X-Api-Key", key)
+		}
+		return r
+	}
+
+	_, _, err = guard.Check(newRequest(""), testMethod)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	require.Len(t, producer.messages, 1)
+
+	ctx, finish, err := guard.Check(newRequest("key"), testMethod)
+	require.NoError(t, err)
+	principal, ok := PrincipalFromContext(ctx)
+	require.True(t, ok)
+	require.Equal(t, "courier", principal.Name)
+
+	finish(status.Error(codes.Internal, "export failed"))
+	require.Len(t, producer.messages, 2)
+	require.Equal(t, testMethod, producer.messages[1].Method)
+	require.Equal(t, "courier", producer.messages[1].Client)
+	require.Equal(t, codes.Internal.String(), producer.messages[1].Code)
+	require.JSONEq(t, `{"status":"issued","recipient_id":"***"}`, producer.messages[1].Args)
+
+	// the bucket of the client is empty
+	_, _, err = guard.Check(newRequest("key"), testMethod)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	_, _, err = guard.Check(newRequest("key"), "/order.Order/IssueOrders")
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	require.Len(t, producer.messages, 3)
+
+	_, finish, err = NewHTTPGuard(nil, nil, nil, nil, nil, audit).Check(newRequest(""), testMethod)
+	require.NoError(t, err)
+	finish(nil)
+}
+
+func TestHTTPGuard_InFlight(t *testing.T) {
+	t.Parallel()
+
+	inFlight := NewInFlightLimiter(1)
+	limiter := NewRateLimiter(Limit{Rate: 0.001, Burst: 1}, nil, 10)
+	guard := NewHTTPGuard(inFlight, nil, limiter, nil, nil, NewAudit(nil, nil, nil))
+	newRequest := func(remoteAddr string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/v2/orders:export", nil)
+		r.RemoteAddr = remoteAddr
+		return r
+	}
+
+	_, finish, err := guard.Check(newRequest("10.0.0.1:1"), testMethod)
+	require.NoError(t, err)
+
+	_, _, err = guard.Check(newRequest("10.0.0.2:1"), testMethod)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	finish(errors.New("export failed"))
+	// the slot is freed by the call rejected by the limiter too
+	_, _, err = guard.Check(newRequest("10.0.0.1:1"), testMethod)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	_, finish, err = guard.Check(newRequest("10.0.0.2:1"), testMethod)
+	require.NoError(t, err)
+	finish(nil)
+}
@@ -3,31 +3,86 @@ package middleware
 import (
 	"context"
 	"google.golang.org/grpc"
-	"google.golang.org/protobuf/encoding/protojson"
-	"google.golang.org/protobuf/proto"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 	"homework/internal/dto"
 	"log"
 	"time"
 )
 
-type onCallProducer interface {
-	SendAsyncMessage(message dto.OnCallMessage) error
-}
+type (
+	onCallProducer interface {
+		SendAsyncMessage(ctx context.Context, message dto.OnCallMessage) error
+	}
+
+	onCallServerStream struct {
+		grpc.ServerStream
+		audit Audit
+		args  string
+	}
+)
 
-func OnCall(producer onCallProducer) grpc.UnaryServerInterceptor {
+func OnCall(producer onCallProducer, audit Audit) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
-		raw, _ := protojson.Marshal((req).(proto.Message))
-
-		err = producer.SendAsyncMessage(dto.OnCallMessage{
-			CalledAt: time.Now(),
-			Method:   info.FullMethod,
-			Args:     string(raw),
-		})
-		if err != nil {
-			log.Printf("[interceptor.OnCall] error:%v", err.Error())
+		if !audit.Enabled(info.FullMethod) {
+			return handler(ctx, req)
 		}
 
+		calledAt := time.Now()
 		resp, err = handler(ctx, req)
+
+		send(ctx, producer, newOnCallMessage(ctx, info.FullMethod, audit.Args(req), calledAt, err))
 		return
 	}
 }
+
+func OnCallStream(producer onCallProducer, audit Audit) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !audit.Enabled(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		calledAt := time.Now()
+		stream := &onCallServerStream{ServerStream: ss, audit: audit}
+		err := handler(srv, stream)
+
+		send(ss.Context(), producer, newOnCallMessage(ss.Context(), info.FullMethod, stream.args, calledAt, err))
+		return err
+	}
+}
+
+// RecvMsg keeps the first received message as the call arguments.
+func (s *onCallServerStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil && s.args == "" {
+		s.args = s.audit.Args(m)
+	}
+	return err
+}
+
+func send(ctx context.Context, producer onCallProducer, message dto.OnCallMessage) {
+	err := producer.SendAsyncMessage(ctx, message)
+	if err != nil {
+		log.Printf("[interceptor.OnCall] error:%v", err.Error())
+	}
+}
+
+func newOnCallMessage(ctx context.Context, method string, args string, calledAt time.Time, err error) dto.OnCallMessage {
+	message := dto.OnCallMessage{
+		CalledAt: calledAt,
+		Method:   method,
+		Args:     args,
+		Code:     status.Code(err).String(),
+		Duration: time.Since(calledAt),
+	}
+	if err != nil {
+		message.Error = status.Convert(err).Message()
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		message.Peer = p.Addr.String()
+	}
+	if principal, ok := PrincipalFromContext(ctx); ok {
+		message.Client = principal.Name
+	}
+	return message
+}
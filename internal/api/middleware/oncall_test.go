@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"homework/internal/dto"
+	"homework/pkg/api/order/v1"
+	"net"
+	"testing"
+)
+
+type producer struct {
+	messages []dto.OnCallMessage
+}
+
+func (p *producer) SendAsyncMessage(ctx context.Context, message dto.OnCallMessage) error {
+	p.messages = append(p.messages, message)
+	return nil
+}
+
+func TestOnCall(t *testing.T) {
+	t.Parallel()
+
+	type test struct {
+		name  string
+		err   error
+		code  string
+		error string
+	}
+
+	tests := []test{
+		{
+			name: "ok",
+			code: codes.OK.String(),
+		},
+		{
+			name:  "error",
+			err:   status.Error(codes.NotFound, "not found"),
+			code:  codes.NotFound.String(),
+			error: "not found",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			producer := &producer{}
+			addr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1}
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+			info := &grpc.UnaryServerInfo{FullMethod: "/order.Order/ReturnOrder"}
+
+			_, err := OnCall(producer, NewAudit(nil, nil, nil))(ctx, &order.ReturnOrderRequest{Id: "1"}, info, func(ctx context.Context, req any) (any, error) {
+				return nil, tt.err
+			})
+
+			require.ErrorIs(t, err, tt.err)
+			require.Len(t, producer.messages, 1)
+			require.Equal(t, info.FullMethod, producer.messages[0].Method)
+			require.Equal(t, tt.code, producer.messages[0].Code)
+			require.Equal(t, tt.error, producer.messages[0].Error)
+			require.Equal(t, addr.String(), producer.messages[0].Peer)
+			require.JSONEq(t, `{"id":"1"}`, producer.messages[0].Args)
+		})
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	messages []*order.IssueOrdersRequest
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *serverStream) RecvMsg(m any) error {
+	req := m.(*order.IssueOrdersRequest)
+	req.Ids = s.messages[0].Ids
+	s.messages = s.messages[1:]
+	return nil
+}
+
+func TestOnCallStream(t *testing.T) {
+	t.Parallel()
+
+	producer := &producer{}
+	stream := &serverStream{
+		ctx: context.Background(),
+		messages: []*order.IssueOrdersRequest{
+			{Ids: []string{"1"}},
+			{Ids: []string{"2"}},
+		},
+	}
+	info := &grpc.StreamServerInfo{FullMethod: "/order.Order/IssueOrders"}
+
+	err := OnCallStream(producer, NewAudit(nil, nil, nil))(nil, stream, info, func(srv any, stream grpc.ServerStream) error {
+		for i := 0; i < 2; i++ {
+			if err := stream.RecvMsg(&order.IssueOrdersRequest{}); err != nil {
+				return err
+			}
+		}
+		return status.Error(codes.Internal, "internal")
+	})
+
+	require.Error(t, err)
+	require.Len(t, producer.messages, 1)
+	require.Equal(t, codes.Internal.String(), producer.messages[0].Code)
+	require.JSONEq(t, `{"ids":["1"]}`, producer.messages[0].Args)
+}
+
+func TestOnCall_Denied(t *testing.T) {
+	t.Parallel()
+
+	producer := &producer{}
+	info := &grpc.UnaryServerInfo{FullMethod: listOrders}
+
+	_, err := OnCall(producer, NewAudit(nil, []string{listOrders}, nil))(context.Background(), &order.ListOrdersRequest{}, info,
+		func(ctx context.Context, req any) (any, error) {
+			return nil, nil
+		})
+
+	require.NoError(t, err)
+	require.Empty(t, producer.messages)
+}
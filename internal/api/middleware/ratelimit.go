@@ -0,0 +1,193 @@
+package middleware
+
+import (
+	"context"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"homework/internal/metrics"
+	"homework/pkg/ds/lru"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	RetryAfterHeader = "retry-after"
+	// ForwardedForHeader is set by the gateway to the addresses of the http request.
+	ForwardedForHeader = "x-forwarded-for"
+	// limiterTTL forgets the bucket of a client idle for that long, it's full again by then.
+	limiterTTL = 10 * time.Minute
+)
+
+type (
+	// Limit is a token bucket refilled with Rate tokens per second up to Burst.
+	Limit struct {
+		Rate  float64
+		Burst int
+	}
+
+	// RateLimiter keeps a bucket per client and method.
+	RateLimiter struct {
+		defaultLimit Limit
+		methods      map[string]Limit
+
+		// lock makes getting and creating the bucket atomic, lru has its own lock for the rest
+		lock     sync.Mutex
+		limiters *lru.Cache[string, *rate.Limiter]
+	}
+
+	// InFlightLimiter rejects calls while max calls are being handled.
+	InFlightLimiter struct {
+		slots chan struct{}
+	}
+)
+
+// NewRateLimiter keeps the buckets of at most clients most recently active client and method pairs.
+func NewRateLimiter(defaultLimit Limit, methods map[string]Limit, clients int) *RateLimiter {
+	return &RateLimiter{
+		defaultLimit: defaultLimit,
+		methods:      methods,
+		limiters:     lru.NewLRUCache[string, *rate.Limiter](clients, limiterTTL),
+	}
+}
+
+func RateLimit(l *RateLimiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := l.Allow(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func RateLimitStream(l *RateLimiter) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := l.Allow(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// Allow takes a token from the bucket of the client, if it's empty the error tells when to retry.
+func (l *RateLimiter) Allow(ctx context.Context, method string) error {
+	reservation := l.limiter(clientKey(ctx), method).Reserve()
+	delay := reservation.Delay()
+	if !reservation.OK() {
+		// a zero burst never lets the call through
+		delay = time.Second
+	}
+	if delay == 0 {
+		return nil
+	}
+	reservation.Cancel()
+
+	metrics.AddRejectedRequest(method, metrics.RateLimited)
+	return resourceExhausted(ctx, delay, "rate limit of %s is exceeded", method)
+}
+
+func (l *RateLimiter) limiter(client string, method string) *rate.Limiter {
+	key := client + " " + method
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if limiter, ok := l.limiters.Get(key); ok {
+		return limiter
+	}
+
+	limit, ok := l.methods[method]
+	if !ok {
+		limit = l.defaultLimit
+	}
+	limiter := rate.NewLimiter(rate.Limit(limit.Rate), limit.Burst)
+	l.limiters.Put(key, limiter)
+	return limiter
+}
+
+// clientKey is the authenticated client or the ip of the peer.
+// The calls of the gateway come from loopback, their client is the http peer the gateway puts last into x-forwarded-for,
+// the addresses before it are sent by the client and can't be trusted. The gateway adds its value after the metadata
+// the client may pass in Grpc-Metadata-X-Forwarded-For, so only the last value is read.
+func clientKey(ctx context.Context) string {
+	if principal, ok := PrincipalFromContext(ctx); ok {
+		return principal.Name
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if forwarded := last(md.Get(ForwardedForHeader)); forwarded != "" {
+			return strings.TrimSpace(forwarded[strings.LastIndex(forwarded, ",")+1:])
+		}
+	}
+	return host
+}
+
+func NewInFlightLimiter(max int) *InFlightLimiter {
+	return &InFlightLimiter{slots: make(chan struct{}, max)}
+}
+
+func InFlight(l *InFlightLimiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !l.acquire() {
+			return nil, l.reject(ctx, info.FullMethod)
+		}
+		defer l.release()
+		return handler(ctx, req)
+	}
+}
+
+// InFlightStream holds the slot while the stream is open. Server streams like WatchOrders are
+// idle most of the time, so they don't take the slots of the calls.
+func InFlightStream(l *InFlightLimiter) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if info.IsServerStream && !info.IsClientStream {
+			return handler(srv, ss)
+		}
+		if !l.acquire() {
+			return l.reject(ss.Context(), info.FullMethod)
+		}
+		defer l.release()
+		return handler(srv, ss)
+	}
+}
+
+func (l *InFlightLimiter) acquire() bool {
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (l *InFlightLimiter) release() {
+	<-l.slots
+}
+
+func (l *InFlightLimiter) reject(ctx context.Context, method string) error {
+	metrics.AddRejectedRequest(method, metrics.InFlightLimited)
+	return resourceExhausted(ctx, time.Second, "too many requests in flight")
+}
+
+// resourceExhausted sets the retry-after header in whole seconds, the gateway passes it as Retry-After.
+func resourceExhausted(ctx context.Context, retryAfter time.Duration, format string, args ...any) error {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	// there is no transport stream in unit tests
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RetryAfterHeader, strconv.Itoa(seconds)))
+	return status.Errorf(codes.ResourceExhausted, format, args...)
+}
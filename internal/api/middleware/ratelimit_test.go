@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"net"
+	"testing"
+)
+
+func call(interceptor grpc.UnaryServerInterceptor, ctx context.Context, method string) error {
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req any) (any, error) {
+		return nil, nil
+	})
+	return err
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewRateLimiter(Limit{Rate: 0.001, Burst: 2}, map[string]Limit{refundOrder: {Rate: 0.001, Burst: 1}}, 10)
+	interceptor := RateLimit(limiter)
+	courier := ContextWithPrincipal(context.Background(), Principal{Name: "courier"})
+	operator := ContextWithPrincipal(context.Background(), Principal{Name: "operator"})
+
+	require.NoError(t, call(interceptor, courier, listOrders))
+	require.NoError(t, call(interceptor, courier, listOrders))
+	err := call(interceptor, courier, listOrders)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// the buckets are per client and per method
+	require.NoError(t, call(interceptor, operator, listOrders))
+	require.NoError(t, call(interceptor, courier, refundOrder))
+	err = call(interceptor, courier, refundOrder)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestRateLimit_PeerIP(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewRateLimiter(Limit{Rate: 0.001, Burst: 1}, nil, 10)
+	interceptor := RateLimit(limiter)
+	newPeer := func(port int) context.Context {
+		addr := &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: port}
+		return peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+	}
+
+	require.NoError(t, call(interceptor, newPeer(1), listOrders))
+	// another connection from the same host shares the bucket
+	err := call(interceptor, newPeer(2), listOrders)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestRateLimit_Gateway(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewRateLimiter(Limit{Rate: 0.001, Burst: 1}, nil, 10)
+	interceptor := RateLimit(limiter)
+	gateway := &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1}}
+	newRequest := func(forwardedFor ...string) context.Context {
+		ctx := peer.NewContext(context.Background(), gateway)
+		return metadata.NewIncomingContext(ctx, metadata.MD{ForwardedForHeader: forwardedFor})
+	}
+
+	require.NoError(t, call(interceptor, newRequest("10.0.0.1"), listOrders))
+	// other http clients behind the gateway have their own buckets
+	require.NoError(t, call(interceptor, newRequest("10.0.0.2"), listOrders))
+	// the address sent by the client doesn't give it another bucket
+	err := call(interceptor, newRequest("10.0.0.3, 10.0.0.1"), listOrders)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	// neither does the metadata sent by the client before the value of the gateway
+	err = call(interceptor, newRequest("10.0.0.4", "10.0.0.1"), listOrders)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestInFlight(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewInFlightLimiter(1)
+	interceptor := InFlight(limiter)
+	ctx := context.Background()
+	info := &grpc.UnaryServerInfo{FullMethod: listOrders}
+
+	_, err := interceptor(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+		err := call(interceptor, ctx, listOrders)
+		require.Equal(t, codes.ResourceExhausted, status.Code(err))
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, call(interceptor, ctx, listOrders))
+}
+
+func TestInFlightStream_ServerStream(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewInFlightLimiter(1)
+	interceptor := InFlightStream(limiter)
+	stream := &serverStream{ctx: context.Background()}
+	clientStream := &grpc.StreamServerInfo{FullMethod: "/order.v2.OrderService/StreamDeliverOrders", IsClientStream: true}
+	watch := &grpc.StreamServerInfo{FullMethod: "/order.v2.OrderService/WatchOrders", IsServerStream: true}
+
+	err := interceptor(nil, stream, watch, func(srv any, ss grpc.ServerStream) error {
+		return interceptor(nil, stream, clientStream, func(srv any, ss grpc.ServerStream) error {
+			err := interceptor(nil, stream, clientStream, func(srv any, ss grpc.ServerStream) error { return nil })
+			require.Equal(t, codes.ResourceExhausted, status.Code(err))
+			return interceptor(nil, stream, watch, func(srv any, ss grpc.ServerStream) error { return nil })
+		})
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"slices"
+	"strings"
+	"time"
+)
+
+const anyPickupPoint = "*"
+
+type (
+	Role struct {
+		Methods    []string
+		AfterHours bool
+	}
+
+	Binding struct {
+		Role         string
+		PickupPoints []string
+	}
+
+	// WorkingHours are offsets from midnight in the location, From after To means the hours pass midnight.
+	WorkingHours struct {
+		From, To time.Duration
+		Location *time.Location
+	}
+
+	Policy struct {
+		PickupPoint  string
+		WorkingHours WorkingHours
+		// WorkingHoursOnly are allowed after hours only to roles with AfterHours.
+		WorkingHoursOnly []string
+		Roles            map[string]Role
+		// Bindings are by client names, they are used when the principal has no role.
+		Bindings map[string]Binding
+		// Public are called without a principal.
+		Public []string
+	}
+
+	// RBAC allows the method if the principal's role has it at the pickup point of this instance,
+	// denials are sent to the on-call audit whatever the audit filter is.
+	RBAC struct {
+		policy   Policy
+		producer onCallProducer
+		audit    Audit
+		now      func() time.Time
+	}
+)
+
+func NewRBAC(policy Policy, producer onCallProducer, audit Audit) RBAC {
+	return RBAC{policy: policy, producer: producer, audit: audit, now: time.Now}
+}
+
+func RBACUnary(rbac RBAC) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := rbac.Authorize(ctx, info.FullMethod); err != nil {
+			rbac.deny(ctx, info.FullMethod, rbac.audit.Args(req), err)
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func RBACStream(rbac RBAC) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := rbac.Authorize(ss.Context(), info.FullMethod); err != nil {
+			rbac.deny(ss.Context(), info.FullMethod, "", err)
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// Authorize returns PermissionDenied with the reason.
+func (r RBAC) Authorize(ctx context.Context, method string) error {
+	if slices.Contains(r.policy.Public, method) {
+		return nil
+	}
+
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return status.Error(codes.PermissionDenied, "the caller is not authenticated")
+	}
+
+	roleName, pickupPoints := principal.Role, principal.PickupPoints
+	if roleName == "" {
+		binding, ok := r.policy.Bindings[principal.Name]
+		if !ok {
+			return status.Errorf(codes.PermissionDenied, "%s has no role", principal.Name)
+		}
+		roleName, pickupPoints = binding.Role, binding.PickupPoints
+	}
+
+	role, ok := r.policy.Roles[roleName]
+	if !ok {
+		return status.Errorf(codes.PermissionDenied, "role %s does not exist", roleName)
+	}
+	if !slices.Contains(pickupPoints, r.policy.PickupPoint) && !slices.Contains(pickupPoints, anyPickupPoint) {
+		return status.Errorf(codes.PermissionDenied, "role %s is not given at pickup point %s", roleName, r.policy.PickupPoint)
+	}
+	if !matchMethod(role.Methods, method) {
+		return status.Errorf(codes.PermissionDenied, "role %s can't call %s", roleName, method)
+	}
+	if !role.AfterHours && matchMethod(r.policy.WorkingHoursOnly, method) && !r.policy.WorkingHours.contain(r.now()) {
+		return status.Errorf(codes.PermissionDenied, "role %s can't call %s after hours", roleName, method)
+	}
+	return nil
+}
+
+func (r RBAC) deny(ctx context.Context, method string, args string, err error) {
+	send(ctx, r.producer, newOnCallMessage(ctx, method, args, r.now(), err))
+}
+
+// matchMethod supports * for any method and /package.Service/* for every method of the service.
+func matchMethod(patterns []string, method string) bool {
+	for _, pattern := range patterns {
+		if pattern == "*" || pattern == method {
+			return true
+		}
+		if service, ok := strings.CutSuffix(pattern, "/*"); ok && strings.HasPrefix(method, service+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// contain treats equal From and To as the whole day.
+func (h WorkingHours) contain(t time.Time) bool {
+	if h.From == h.To {
+		return true
+	}
+
+	t = t.In(h.Location)
+	sinceMidnight := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	if h.From < h.To {
+		return sinceMidnight >= h.From && sinceMidnight < h.To
+	}
+	return sinceMidnight >= h.From || sinceMidnight < h.To
+}
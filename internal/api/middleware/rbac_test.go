@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"homework/pkg/api/order/v1"
+	"testing"
+	"time"
+)
+
+const refundOrder = "/order.Order/RefundOrder"
+
+func testPolicy() Policy {
+	return Policy{
+		PickupPoint:      "msk-1",
+		WorkingHours:     WorkingHours{From: 9 * time.Hour, To: 21 * time.Hour, Location: time.UTC},
+		WorkingHoursOnly: []string{refundOrder},
+		Roles: map[string]Role{
+			"readonly":   {Methods: []string{listOrders}},
+			"operator":   {Methods: []string{listOrders, refundOrder, testMethod}},
+			"supervisor": {Methods: []string{"/order.Order/*"}, AfterHours: true},
+			"admin":      {Methods: []string{"*"}},
+		},
+		Bindings: map[string]Binding{
+			"courier": {Role: "operator", PickupPoints: []string{"*"}},
+		},
+		Public: []string{publicMethod},
+	}
+}
+
+func TestRBAC(t *testing.T) {
+	t.Parallel()
+
+	var (
+		day   = time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+		night = time.Date(2024, 7, 1, 23, 0, 0, 0, time.UTC)
+	)
+
+	type test struct {
+		name      string
+		principal *Principal
+		method    string
+		now       time.Time
+		code      codes.Code
+	}
+
+	tests := []test{
+		{
+			name:      "readonly lists orders",
+			principal: &Principal{Name: "viewer", Role: "readonly", PickupPoints: []string{"msk-1"}},
+			method:    listOrders,
+			now:       day,
+			code:      codes.OK,
+		},
+		{
+			name:      "readonly can't deliver",
+			principal: &Principal{Name: "viewer", Role: "readonly", PickupPoints: []string{"msk-1"}},
+			method:    testMethod,
+			now:       day,
+			code:      codes.PermissionDenied,
+		},
+		{
+			name:      "operator refunds in working hours",
+			principal: &Principal{Name: "operator", Role: "operator", PickupPoints: []string{"msk-1"}},
+			method:    refundOrder,
+			now:       day,
+			code:      codes.OK,
+		},
+		{
+			name:      "operator can't refund after hours",
+			principal: &Principal{Name: "operator", Role: "operator", PickupPoints: []string{"msk-1"}},
+			method:    refundOrder,
+			now:       night,
+			code:      codes.PermissionDenied,
+		},
+		{
+			name:      "supervisor refunds after hours",
+			principal: &Principal{Name: "supervisor", Role: "supervisor", PickupPoints: []string{"msk-1"}},
+			method:    refundOrder,
+			now:       night,
+			code:      codes.OK,
+		},
+		{
+			name:      "admin can't refund after hours",
+			principal: &Principal{Name: "admin", Role: "admin", PickupPoints: []string{"*"}},
+			method:    refundOrder,
+			now:       night,
+			code:      codes.PermissionDenied,
+		},
+		{
+			name:      "other pickup point",
+			principal: &Principal{Name: "supervisor", Role: "supervisor", PickupPoints: []string{"spb-1"}},
+			method:    listOrders,
+			now:       day,
+			code:      codes.PermissionDenied,
+		},
+		{
+			name:      "binding of api key client",
+			principal: &Principal{Name: "courier", Method: AuthApiKey},
+			method:    testMethod,
+			now:       day,
+			code:      codes.OK,
+		},
+		{
+			name:      "client without role",
+			principal: &Principal{Name: "unknown", Method: AuthApiKey},
+			method:    listOrders,
+			now:       day,
+			code:      codes.PermissionDenied,
+		},
+		{
+			name:   "not authenticated",
+			method: listOrders,
+			now:    day,
+			code:   codes.PermissionDenied,
+		},
+		{
+			name:   "public method",
+			method: publicMethod,
+			now:    day,
+			code:   codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			producer := &producer{}
+			rbac := NewRBAC(testPolicy(), producer, NewAudit(nil, nil, []string{"userID"}))
+			rbac.now = func() time.Time { return tt.now }
+
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = ContextWithPrincipal(ctx, *tt.principal)
+			}
+			info := &grpc.UnaryServerInfo{FullMethod: tt.method}
+			req := &order.RefundOrderRequest{OrderID: "1", UserID: "1"}
+
+			_, err := RBACUnary(rbac)(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+				return nil, nil
+			})
+
+			require.Equal(t, tt.code, status.Code(err))
+			if tt.code == codes.OK {
+				require.Empty(t, producer.messages)
+				return
+			}
+			require.Len(t, producer.messages, 1)
+			require.Equal(t, codes.PermissionDenied.String(), producer.messages[0].Code)
+			require.Equal(t, tt.method, producer.messages[0].Method)
+			require.NotContains(t, producer.messages[0].Args, `"userID":"1"`)
+			if tt.principal != nil {
+				require.Equal(t, tt.principal.Name, producer.messages[0].Client)
+			}
+		})
+	}
+}
+
+func TestWorkingHours_OverMidnight(t *testing.T) {
+	hours := WorkingHours{From: 20 * time.Hour, To: 8 * time.Hour, Location: time.UTC}
+
+	require.True(t, hours.contain(time.Date(2024, 7, 1, 23, 0, 0, 0, time.UTC)))
+	require.True(t, hours.contain(time.Date(2024, 7, 1, 7, 59, 0, 0, time.UTC)))
+	require.False(t, hours.contain(time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"context"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"google.golang.org/grpc"
+)
+
+type tracingServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Tracing starts the root span of the request so that the api, service, storage
+// and kafka spans end up in one trace.
+func Tracing() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		span, ctx := opentracing.StartSpanFromContext(ctx, info.FullMethod, ext.SpanKindRPCServer)
+		defer span.Finish()
+
+		resp, err := handler(ctx, req)
+		finishWithError(span, err)
+		return resp, err
+	}
+}
+
+func TracingStream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		span, ctx := opentracing.StartSpanFromContext(ss.Context(), info.FullMethod, ext.SpanKindRPCServer)
+		defer span.Finish()
+
+		err := handler(srv, &tracingServerStream{ServerStream: ss, ctx: ctx})
+		finishWithError(span, err)
+		return err
+	}
+}
+
+func (s *tracingServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func finishWithError(span opentracing.Span, err error) {
+	if err == nil {
+		return
+	}
+	ext.Error.Set(span, true)
+	span.LogKV("error", err.Error())
+}
@@ -2,18 +2,13 @@ package api
 
 import (
 	"context"
-	"errors"
 	"github.com/opentracing/opentracing-go"
 	"github.com/shopspring/decimal"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"homework/internal/dto"
 	"homework/internal/metrics"
 	"homework/internal/model"
 	"homework/internal/model/wrapper"
-	"homework/internal/service"
-	"homework/internal/storage"
 	"homework/pkg/api/order/v1"
 )
 
@@ -25,7 +20,11 @@ type (
 
 	orderService interface {
 		Deliver(ctx context.Context, order dto.DeliverOrderParam) error
+		DeliverOrders(ctx context.Context, params []dto.DeliverOrderParam) ([]error, error)
+		CheckDeliverOrders(ctx context.Context, params []dto.DeliverOrderParam) ([]error, error)
 		ListOrders(ctx context.Context, param dto.ListOrdersParam) ([]model.Order, error)
+		GetOrder(ctx context.Context, id string) (model.Order, error)
+		GetOrders(ctx context.Context, ids []string) ([]model.Order, error)
 		ReturnOrder(ctx context.Context, id string) error
 		IssueOrders(ctx context.Context, ids []string) error
 		RefundOrder(ctx context.Context, param dto.RefundOrderParam) error
@@ -43,7 +42,7 @@ func (o *OrderService) ReturnOrder(ctx context.Context, req *order.ReturnOrderRe
 	defer span.Finish()
 
 	if err := req.ValidateAll(); err != nil {
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, invalidArgument(req, err)
 	}
 
 	err := o.service.ReturnOrder(ctx, req.GetId())
@@ -60,7 +59,7 @@ func (o *OrderService) IssueOrders(ctx context.Context, req *order.IssueOrdersRe
 	defer span.Finish()
 
 	if err := req.ValidateAll(); err != nil {
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, invalidArgument(req, err)
 	}
 
 	err := o.service.IssueOrders(ctx, req.GetIds())
@@ -77,7 +76,7 @@ func (o *OrderService) RefundOrder(ctx context.Context, req *order.RefundOrderRe
 	defer span.Finish()
 
 	if err := req.ValidateAll(); err != nil {
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, invalidArgument(req, err)
 	}
 
 	err := o.service.RefundOrder(ctx, dto.RefundOrderParam{
@@ -97,7 +96,7 @@ func (o *OrderService) ListOrders(ctx context.Context, req *order.ListOrdersRequ
 	defer span.Finish()
 
 	if err := req.ValidateAll(); err != nil {
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, invalidArgument(req, err)
 	}
 
 	param := dto.ListOrdersParam{
@@ -133,13 +132,13 @@ func (o *OrderService) DeliverOrder(ctx context.Context, req *order.DeliverOrder
 	defer span.Finish()
 
 	if err := req.ValidateAll(); err != nil {
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, invalidArgument(req, err)
 	}
 
 	priceInRub := wrapper.PriceInRub(decimal.NewFromFloat(float64(req.GetPriceInRub())))
 	wrapper, err := wrapper.NewDefaultWrapper(grpcWrapperTypeToDomain(req.GetWrapperType()))
 	if req.WrapperType != order.WrapperType_WRAPPER_TYPE_NONE && err != nil {
-		return &emptypb.Empty{}, status.Error(codes.InvalidArgument, err.Error())
+		return &emptypb.Empty{}, invalidField("wrapperType", err)
 	}
 
 	err = o.service.Deliver(ctx, dto.DeliverOrderParam{
@@ -158,26 +157,6 @@ func (o *OrderService) DeliverOrder(ctx context.Context, req *order.DeliverOrder
 	return &emptypb.Empty{}, nil
 }
 
-func toGRPCError(err error) error {
-	var orderServiceError service.OrderServiceError
-	isOrderServiceError := errors.As(err, &orderServiceError)
-	if isOrderServiceError {
-		return status.Error(codes.InvalidArgument, err.Error())
-	}
-
-	switch {
-	case errors.Is(err, storage.ErrNotFound):
-		return status.Error(codes.NotFound, err.Error())
-	case errors.Is(err, storage.ErrDuplicateOrderID):
-		return status.Error(codes.AlreadyExists, err.Error())
-	default:
-		if err == nil {
-			return nil
-		}
-		return status.Error(codes.Internal, err.Error())
-	}
-}
-
 func grpcOrderStatusToDomain(orderStatus order.OrderStatus) model.Status {
 	return map[order.OrderStatus]model.Status{
 		order.OrderStatus_ORDER_STATUS_ANY:       model.StatusNone,
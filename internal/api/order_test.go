@@ -150,7 +150,7 @@ func TestListOrders(t *testing.T) {
 			mockFn: func(m mocks) {
 				orders := []model.Order{
 					{
-						Status:      model.Status(order.OrderStatus_name[int32(order1.Status)]),
+						Status:      model.StatusDelivered,
 						ID:          order1.Id,
 						RecipientID: order1.RecipientID,
 					},
@@ -229,12 +229,12 @@ func TestRefundOrder(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name: "invalid argument service",
+			name: "failed precondition refund period has expired",
 			input: &order.RefundOrderRequest{
 				OrderID: "1",
 				UserID:  "1",
 			},
-			code: codes.InvalidArgument,
+			code: codes.FailedPrecondition,
 			mockFn: func(m mocks) {
 				m.mockOrderService.EXPECT().RefundOrder(gomock.Any(), gomock.Any()).Times(1).Return(service.ErrRefundPeriodHasExpired)
 			},
@@ -317,11 +317,11 @@ func TestIssueOrder(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name: "invalid argument err extra ids in the request",
+			name: "failed precondition err extra ids in the request",
 			input: &order.IssueOrdersRequest{
 				Ids: []string{"1"},
 			},
-			code: codes.InvalidArgument,
+			code: codes.FailedPrecondition,
 			mockFn: func(m mocks) {
 				m.mockOrderService.EXPECT().IssueOrders(gomock.Any(), gomock.Any()).Times(1).Return(service.ErrExtraIDsInTheRequest)
 			},
@@ -393,11 +393,11 @@ func TestReturnOrder(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name: "invalid argument order has not expired",
+			name: "failed precondition order has not expired",
 			input: &order.ReturnOrderRequest{
 				Id: "1",
 			},
-			code: codes.InvalidArgument,
+			code: codes.FailedPrecondition,
 			mockFn: func(m mocks) {
 				m.mockOrderService.EXPECT().ReturnOrder(gomock.Any(), gomock.Any()).Times(1).Return(service.ErrOrderHasNotExpired)
 			},
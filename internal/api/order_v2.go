@@ -0,0 +1,441 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/opentracing/opentracing-go"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"homework/internal/dto"
+	"homework/internal/eventbus"
+	"homework/internal/manifest"
+	"homework/internal/metrics"
+	"homework/internal/model"
+	"homework/internal/model/wrapper"
+	"homework/pkg/api/order/v2"
+	"io"
+)
+
+// defaultDeliverChunkSize is used when the chunk size isn't configured.
+const defaultDeliverChunkSize = 500
+
+// OrderServiceV2 serves order.v2 with the same service as v1, mutations answer with the changed orders.
+type (
+	OrderServiceV2 struct {
+		service orderService
+		watcher orderWatcher
+		// deliverChunkSize is how many orders of DeliverOrders and StreamDeliverOrders share a transaction.
+		deliverChunkSize int
+		order.UnimplementedOrderServiceServer
+	}
+
+	orderWatcher interface {
+		Subscribe(filter eventbus.Filter, after string) (*eventbus.Subscription, error)
+	}
+)
+
+func NewOrderServiceV2(orderService orderService, watcher orderWatcher, deliverChunkSize int) *OrderServiceV2 {
+	if deliverChunkSize <= 0 {
+		deliverChunkSize = defaultDeliverChunkSize
+	}
+	return &OrderServiceV2{
+		service:          orderService,
+		watcher:          watcher,
+		deliverChunkSize: deliverChunkSize,
+	}
+}
+
+func (o *OrderServiceV2) DeliverOrder(ctx context.Context, req *order.DeliverOrderRequest) (*order.Order, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.OrderServiceV2.DeliverOrder")
+	defer span.Finish()
+
+	param, err := v2DeliverOrderToDomain(req)
+	if err != nil {
+		return nil, err
+	}
+
+	err = o.service.Deliver(ctx, param)
+	if err := toGRPCError(err); err != nil {
+		return nil, err
+	}
+
+	return o.getOrder(ctx, req.GetOrderId())
+}
+
+func (o *OrderServiceV2) DeliverOrders(ctx context.Context, req *order.DeliverOrdersRequest) (*order.DeliverOrdersResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.OrderServiceV2.DeliverOrders")
+	defer span.Finish()
+
+	if err := req.ValidateAll(); err != nil {
+		return nil, invalidArgument(req, err)
+	}
+
+	response := &order.DeliverOrdersResponse{}
+	orders := req.GetOrders()
+	for start := 0; start < len(orders); start += o.deliverChunkSize {
+		o.deliverChunk(ctx, orders[start:min(start+o.deliverChunkSize, len(orders))], response)
+	}
+	return response, nil
+}
+
+// StreamDeliverOrders delivers every chunk as soon as it's received, so the orders before a broken stream stay delivered.
+func (o *OrderServiceV2) StreamDeliverOrders(stream order.OrderService_StreamDeliverOrdersServer) error {
+	span, ctx := opentracing.StartSpanFromContext(stream.Context(), "api.OrderServiceV2.StreamDeliverOrders")
+	defer span.Finish()
+
+	response := &order.DeliverOrdersResponse{}
+	chunk := make([]*order.DeliverOrderRequest, 0, o.deliverChunkSize)
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		chunk = append(chunk, req)
+		if len(chunk) == o.deliverChunkSize {
+			o.deliverChunk(ctx, chunk, response)
+			chunk = chunk[:0]
+		}
+	}
+	if len(chunk) != 0 {
+		o.deliverChunk(ctx, chunk, response)
+	}
+
+	return stream.SendAndClose(response)
+}
+
+// deliverChunk delivers the orders in one transaction and appends their results to the response.
+func (o *OrderServiceV2) deliverChunk(ctx context.Context, reqs []*order.DeliverOrderRequest, response *order.DeliverOrdersResponse) {
+	results := make([]*order.DeliverOrderResult, 0, len(reqs))
+	params := make([]dto.DeliverOrderParam, 0, len(reqs))
+	// indexes[j] is the index of params[j] in results
+	indexes := make([]int, 0, len(reqs))
+	for i, req := range reqs {
+		param, err := v2DeliverOrderToDomain(req)
+		results = append(results, &order.DeliverOrderResult{OrderId: req.GetOrderId(), Status: status.Convert(err).Proto()})
+		if err != nil {
+			continue
+		}
+		params = append(params, param)
+		indexes = append(indexes, i)
+	}
+
+	if len(params) != 0 {
+		errs, err := o.service.DeliverOrders(ctx, params)
+		for j, i := range indexes {
+			// the failed transaction fails every order of the chunk
+			if err == nil {
+				results[i].Status = status.Convert(toGRPCError(errs[j])).Proto()
+			} else {
+				results[i].Status = status.Convert(toGRPCError(err)).Proto()
+			}
+		}
+	}
+
+	for _, result := range results {
+		if codes.Code(result.GetStatus().GetCode()) == codes.OK {
+			response.Delivered++
+		}
+	}
+	response.Results = append(response.Results, results...)
+}
+
+// ImportDeliveries delivers the orders of the manifest in chunks like DeliverOrders,
+// only a manifest that can't be read fails the call.
+func (o *OrderServiceV2) ImportDeliveries(ctx context.Context, req *order.ImportDeliveriesRequest) (*order.ImportDeliveriesResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.OrderServiceV2.ImportDeliveries")
+	defer span.Finish()
+
+	if err := req.ValidateAll(); err != nil {
+		return nil, invalidArgument(req, err)
+	}
+
+	format := manifest.CSV
+	if req.GetFormat() == order.ManifestFormat_MANIFEST_FORMAT_JSON {
+		format = manifest.JSON
+	}
+	rows, err := manifest.Read(bytes.NewReader(req.GetContent()), format)
+	if err != nil {
+		return nil, invalidField("content", err)
+	}
+
+	report := manifest.Import(ctx, o.service, rows, req.GetDryRun(), o.deliverChunkSize)
+	return domainImportReportToV2(report), nil
+}
+
+func (o *OrderServiceV2) GetOrder(ctx context.Context, req *order.GetOrderRequest) (*order.Order, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.OrderServiceV2.GetOrder")
+	defer span.Finish()
+
+	if err := req.ValidateAll(); err != nil {
+		return nil, invalidArgument(req, err)
+	}
+
+	return o.getOrder(ctx, req.GetOrderId())
+}
+
+func (o *OrderServiceV2) ListOrders(ctx context.Context, req *order.ListOrdersRequest) (*order.ListOrdersResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.OrderServiceV2.ListOrders")
+	defer span.Finish()
+
+	if err := req.ValidateAll(); err != nil {
+		return nil, invalidArgument(req, err)
+	}
+
+	orders, err := o.service.ListOrders(ctx, dto.ListOrdersParam{
+		UserId: req.GetRecipientId(),
+		Size:   uint(req.GetPageSize()),
+		Page:   uint(req.GetPage()),
+		Status: v2OrderStatusToDomain(req.GetStatus()),
+	})
+	if err := toGRPCError(err); err != nil {
+		return nil, err
+	}
+
+	return &order.ListOrdersResponse{Orders: domainOrdersToV2(orders)}, nil
+}
+
+func (o *OrderServiceV2) WatchOrders(req *order.WatchOrdersRequest, stream order.OrderService_WatchOrdersServer) error {
+	span, ctx := opentracing.StartSpanFromContext(stream.Context(), "api.OrderServiceV2.WatchOrders")
+	defer span.Finish()
+
+	if err := req.ValidateAll(); err != nil {
+		return invalidArgument(req, err)
+	}
+
+	subscription, err := o.watcher.Subscribe(eventbus.Filter{
+		RecipientID: req.GetRecipientId(),
+		Status:      v2OrderStatusToDomain(req.GetStatus()),
+		PickupPoint: req.GetPickupPoint(),
+	}, req.GetAfterEventId())
+	if err != nil {
+		return toGRPCError(err)
+	}
+	defer subscription.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case event, ok := <-subscription.Events():
+			if !ok {
+				return toGRPCError(subscription.Err())
+			}
+			if err := stream.Send(domainOrderEventToV2(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (o *OrderServiceV2) IssueOrder(ctx context.Context, req *order.IssueOrderRequest) (*order.Order, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.OrderServiceV2.IssueOrder")
+	defer span.Finish()
+
+	if err := req.ValidateAll(); err != nil {
+		return nil, invalidArgument(req, err)
+	}
+
+	err := o.service.IssueOrders(ctx, []string{req.GetOrderId()})
+	if err := toGRPCError(err); err != nil {
+		return nil, err
+	}
+
+	metrics.AddIssuedOrders(1)
+	return o.getOrder(ctx, req.GetOrderId())
+}
+
+func (o *OrderServiceV2) BatchIssueOrders(ctx context.Context, req *order.BatchIssueOrdersRequest) (*order.BatchIssueOrdersResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.OrderServiceV2.BatchIssueOrders")
+	defer span.Finish()
+
+	if err := req.ValidateAll(); err != nil {
+		return nil, invalidArgument(req, err)
+	}
+
+	err := o.service.IssueOrders(ctx, req.GetOrderIds())
+	if err := toGRPCError(err); err != nil {
+		return nil, err
+	}
+	metrics.AddIssuedOrders(len(req.GetOrderIds()))
+
+	orders, err := o.service.GetOrders(ctx, req.GetOrderIds())
+	if err := toGRPCError(err); err != nil {
+		return nil, err
+	}
+	return &order.BatchIssueOrdersResponse{Orders: domainOrdersToV2(orders)}, nil
+}
+
+func (o *OrderServiceV2) RefundOrder(ctx context.Context, req *order.RefundOrderRequest) (*order.Order, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.OrderServiceV2.RefundOrder")
+	defer span.Finish()
+
+	if err := req.ValidateAll(); err != nil {
+		return nil, invalidArgument(req, err)
+	}
+
+	err := o.service.RefundOrder(ctx, dto.RefundOrderParam{
+		ID:          req.GetOrderId(),
+		RecipientID: req.GetRecipientId(),
+	})
+	if err := toGRPCError(err); err != nil {
+		return nil, err
+	}
+
+	return o.getOrder(ctx, req.GetOrderId())
+}
+
+func (o *OrderServiceV2) ReturnOrder(ctx context.Context, req *order.ReturnOrderRequest) (*emptypb.Empty, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.OrderServiceV2.ReturnOrder")
+	defer span.Finish()
+
+	if err := req.ValidateAll(); err != nil {
+		return nil, invalidArgument(req, err)
+	}
+
+	err := o.service.ReturnOrder(ctx, req.GetOrderId())
+	if err := toGRPCError(err); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (o *OrderServiceV2) getOrder(ctx context.Context, id string) (*order.Order, error) {
+	found, err := o.service.GetOrder(ctx, id)
+	if err := toGRPCError(err); err != nil {
+		return nil, err
+	}
+	return domainOrderToV2(found), nil
+}
+
+// v2DeliverOrderToDomain validates the request, the error is ready to be returned to the client.
+func v2DeliverOrderToDomain(req *order.DeliverOrderRequest) (dto.DeliverOrderParam, error) {
+	if err := req.ValidateAll(); err != nil {
+		return dto.DeliverOrderParam{}, invalidArgument(req, err)
+	}
+
+	price, err := decimal.NewFromString(req.GetPriceRub())
+	if err != nil {
+		return dto.DeliverOrderParam{}, invalidField("price_rub", err)
+	}
+	var orderWrapper *wrapper.Wrapper
+	if req.GetWrapperType() != order.WrapperType_WRAPPER_TYPE_UNSPECIFIED {
+		orderWrapper, err = wrapper.NewDefaultWrapper(v2WrapperTypeToDomain(req.GetWrapperType()))
+		if err != nil {
+			return dto.DeliverOrderParam{}, invalidField("wrapper_type", err)
+		}
+	}
+
+	return dto.DeliverOrderParam{
+		ID:             req.GetOrderId(),
+		RecipientID:    req.GetRecipientId(),
+		ExpirationDate: req.GetExpiresAt().AsTime(),
+		WeightInGram:   req.GetWeightKg() * 1000,
+		Wrapper:        orderWrapper,
+		PriceInRub:     wrapper.PriceInRub(price),
+	}, nil
+}
+
+func domainImportReportToV2(report manifest.Report) *order.ImportDeliveriesResponse {
+	response := &order.ImportDeliveriesResponse{
+		Rows:     make([]*order.ImportRowResult, 0, len(report.Rows)),
+		Accepted: uint32(report.Accepted),
+		Failed:   uint32(report.Failed),
+		DryRun:   report.DryRun,
+	}
+	for _, row := range report.Rows {
+		err := toGRPCError(row.Err)
+		// the fields of the rows are named like the csv columns
+		var fieldError manifest.FieldError
+		if errors.As(row.Err, &fieldError) {
+			err = invalidField(fieldError.Field, fieldError.Err)
+		}
+		response.Rows = append(response.Rows, &order.ImportRowResult{
+			Row:     uint32(row.Number),
+			OrderId: row.ID,
+			Status:  status.Convert(err).Proto(),
+		})
+	}
+	return response
+}
+
+func domainOrderEventToV2(event dto.OrderEvent) *order.OrderEvent {
+	return &order.OrderEvent{
+		Id:          event.ID,
+		OrderId:     event.OrderID,
+		RecipientId: event.RecipientID,
+		Status:      domainOrderStatusToV2(event.Status),
+		PickupPoint: event.PickupPoint,
+		OccurredAt:  timestamppb.New(event.OccurredAt),
+	}
+}
+
+func domainOrdersToV2(orders []model.Order) []*order.Order {
+	result := make([]*order.Order, 0, len(orders))
+	for _, o := range orders {
+		result = append(result, domainOrderToV2(o))
+	}
+	return result
+}
+
+func domainOrderToV2(o model.Order) *order.Order {
+	wrapperType := order.WrapperType_WRAPPER_TYPE_UNSPECIFIED
+	if o.Wrapper != nil {
+		wrapperType = domainWrapperTypeToV2(o.Wrapper.GetType())
+	}
+
+	return &order.Order{
+		Id:              o.ID,
+		RecipientId:     o.RecipientID,
+		Status:          domainOrderStatusToV2(o.Status),
+		StatusUpdatedAt: timestamppb.New(o.StatusUpdatedAt),
+		ExpiresAt:       timestamppb.New(o.ExpirationDate),
+		WeightKg:        o.WeightInGram / 1000,
+		WrapperType:     wrapperType,
+		PriceRub:        decimal.Decimal(o.PriceInRub).StringFixed(2),
+	}
+}
+
+func v2OrderStatusToDomain(orderStatus order.OrderStatus) model.Status {
+	return map[order.OrderStatus]model.Status{
+		order.OrderStatus_ORDER_STATUS_UNSPECIFIED: model.StatusNone,
+		order.OrderStatus_ORDER_STATUS_DELIVERED:   model.StatusDelivered,
+		order.OrderStatus_ORDER_STATUS_ISSUED:      model.StatusIssued,
+		order.OrderStatus_ORDER_STATUS_REFUNDED:    model.StatusRefunded,
+		order.OrderStatus_ORDER_STATUS_RETURNED:    model.StatusReturned,
+	}[orderStatus]
+}
+
+func domainOrderStatusToV2(orderStatus model.Status) order.OrderStatus {
+	return map[model.Status]order.OrderStatus{
+		model.StatusDelivered: order.OrderStatus_ORDER_STATUS_DELIVERED,
+		model.StatusIssued:    order.OrderStatus_ORDER_STATUS_ISSUED,
+		model.StatusRefunded:  order.OrderStatus_ORDER_STATUS_REFUNDED,
+		model.StatusReturned:  order.OrderStatus_ORDER_STATUS_RETURNED,
+	}[orderStatus]
+}
+
+func v2WrapperTypeToDomain(wrapperType order.WrapperType) wrapper.WrapperType {
+	return map[order.WrapperType]wrapper.WrapperType{
+		order.WrapperType_WRAPPER_TYPE_UNSPECIFIED: wrapper.NoneWrapper,
+		order.WrapperType_WRAPPER_TYPE_BOX:         wrapper.BoxWrapper,
+		order.WrapperType_WRAPPER_TYPE_PACKAGE:     wrapper.PackageWrapper,
+		order.WrapperType_WRAPPER_TYPE_STRETCH:     wrapper.StretchWrapper,
+	}[wrapperType]
+}
+
+func domainWrapperTypeToV2(wrapperType wrapper.WrapperType) order.WrapperType {
+	return map[wrapper.WrapperType]order.WrapperType{
+		wrapper.BoxWrapper:     order.WrapperType_WRAPPER_TYPE_BOX,
+		wrapper.PackageWrapper: order.WrapperType_WRAPPER_TYPE_PACKAGE,
+		wrapper.StretchWrapper: order.WrapperType_WRAPPER_TYPE_STRETCH,
+	}[wrapperType]
+}
@@ -0,0 +1,436 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"homework/internal/dto"
+	"homework/internal/eventbus"
+	"homework/internal/model"
+	"homework/internal/model/wrapper"
+	"homework/internal/service"
+	"homework/internal/storage"
+	"homework/pkg/api/order/v2"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestDeliverV2(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx       = context.Background()
+		expiresAt = time.Now().Add(time.Hour).UTC()
+		delivered = model.Order{
+			ID:             "1",
+			RecipientID:    "2",
+			Status:         model.StatusDelivered,
+			ExpirationDate: expiresAt,
+			WeightInGram:   1500,
+			PriceInRub:     wrapper.PriceInRub(decimal.RequireFromString("110.5")),
+		}
+	)
+
+	type test struct {
+		name   string
+		input  *order.DeliverOrderRequest
+		code   codes.Code
+		mockFn func(m mocks)
+		result *order.Order
+	}
+
+	valid := func() *order.DeliverOrderRequest {
+		return &order.DeliverOrderRequest{
+			OrderId:     "1",
+			RecipientId: "2",
+			ExpiresAt:   timestamppb.New(expiresAt),
+			WeightKg:    1.5,
+			PriceRub:    "100.50",
+		}
+	}
+	tests := []test{
+		{
+			name:  "ok",
+			input: valid(),
+			code:  codes.OK,
+			mockFn: func(m mocks) {
+				m.mockOrderService.EXPECT().Deliver(gomock.Any(), dto.DeliverOrderParam{
+					ID:             "1",
+					RecipientID:    "2",
+					ExpirationDate: expiresAt,
+					WeightInGram:   1500,
+					PriceInRub:     wrapper.PriceInRub(decimal.RequireFromString("100.50")),
+				}).Return(nil).Times(1)
+				m.mockOrderService.EXPECT().GetOrder(gomock.Any(), "1").Return(delivered, nil).Times(1)
+			},
+			result: &order.Order{
+				Id:              "1",
+				RecipientId:     "2",
+				Status:          order.OrderStatus_ORDER_STATUS_DELIVERED,
+				StatusUpdatedAt: timestamppb.New(time.Time{}),
+				ExpiresAt:       timestamppb.New(expiresAt),
+				WeightKg:        1.5,
+				PriceRub:        "110.50",
+			},
+		},
+		{
+			name: "price isn't a decimal",
+			input: func() *order.DeliverOrderRequest {
+				req := valid()
+				req.PriceRub = "1e3"
+				return req
+			}(),
+			code:   codes.InvalidArgument,
+			mockFn: func(m mocks) {},
+		},
+		{
+			name: "unknown wrapper",
+			input: func() *order.DeliverOrderRequest {
+				req := valid()
+				req.WrapperType = -1
+				return req
+			}(),
+			code:   codes.InvalidArgument,
+			mockFn: func(m mocks) {},
+		},
+		{
+			name:  "already exists",
+			input: valid(),
+			code:  codes.AlreadyExists,
+			mockFn: func(m mocks) {
+				m.mockOrderService.EXPECT().Deliver(gomock.Any(), gomock.Any()).Return(storage.ErrDuplicateOrderID).Times(1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mocks := newMocks(t)
+			tt.mockFn(mocks)
+
+			result, err := NewOrderServiceV2(mocks.mockOrderService, nil, 0).DeliverOrder(ctx, tt.input)
+
+			require.Equal(t, tt.code, status.Code(err))
+			require.Equal(t, tt.result.String(), result.String())
+		})
+	}
+}
+
+func TestDeliverOrdersV2(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	expiresAt := timestamppb.New(time.Now().Add(time.Hour))
+	newRequest := func(id, recipientID string) *order.DeliverOrderRequest {
+		return &order.DeliverOrderRequest{OrderId: id, RecipientId: recipientID, ExpiresAt: expiresAt, WeightKg: 1, PriceRub: "10"}
+	}
+
+	mocks := newMocks(t)
+	gomock.InOrder(
+		mocks.mockOrderService.EXPECT().DeliverOrders(gomock.Any(), gomock.Len(1)).
+			Return([]error{nil}, nil).Times(1),
+		mocks.mockOrderService.EXPECT().DeliverOrders(gomock.Any(), gomock.Len(2)).
+			Return([]error{storage.ErrDuplicateOrderID, nil}, nil).Times(1),
+		mocks.mockOrderService.EXPECT().DeliverOrders(gomock.Any(), gomock.Len(1)).
+			Return(nil, errors.New("connection refused")).Times(1),
+	)
+
+	result, err := NewOrderServiceV2(mocks.mockOrderService, nil, 2).DeliverOrders(ctx, &order.DeliverOrdersRequest{
+		Orders: []*order.DeliverOrderRequest{
+			newRequest("1", "1"), newRequest("2", ""),
+			newRequest("3", "1"), newRequest("4", "1"),
+			newRequest("5", "1"),
+		},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), result.GetDelivered())
+	var ids []string
+	var codesOf []codes.Code
+	for _, r := range result.GetResults() {
+		ids = append(ids, r.GetOrderId())
+		codesOf = append(codesOf, codes.Code(r.GetStatus().GetCode()))
+	}
+	require.Equal(t, []string{"1", "2", "3", "4", "5"}, ids)
+	require.Equal(t, []codes.Code{codes.OK, codes.InvalidArgument, codes.AlreadyExists, codes.OK, codes.Internal}, codesOf)
+
+	_, err = NewOrderServiceV2(mocks.mockOrderService, nil, 2).DeliverOrders(ctx, &order.DeliverOrdersRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+type deliverOrdersStream struct {
+	grpc.ServerStream
+	requests []*order.DeliverOrderRequest
+	response *order.DeliverOrdersResponse
+}
+
+func (s *deliverOrdersStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *deliverOrdersStream) Recv() (*order.DeliverOrderRequest, error) {
+	if len(s.requests) == 0 {
+		return nil, io.EOF
+	}
+	req := s.requests[0]
+	s.requests = s.requests[1:]
+	return req, nil
+}
+
+func (s *deliverOrdersStream) SendAndClose(response *order.DeliverOrdersResponse) error {
+	s.response = response
+	return nil
+}
+
+func TestStreamDeliverOrdersV2(t *testing.T) {
+	t.Parallel()
+
+	expiresAt := timestamppb.New(time.Now().Add(time.Hour))
+	stream := &deliverOrdersStream{}
+	for _, id := range []string{"1", "2", "3"} {
+		stream.requests = append(stream.requests, &order.DeliverOrderRequest{
+			OrderId: id, RecipientId: "1", ExpiresAt: expiresAt, WeightKg: 1, PriceRub: "10",
+		})
+	}
+
+	mocks := newMocks(t)
+	gomock.InOrder(
+		mocks.mockOrderService.EXPECT().DeliverOrders(gomock.Any(), gomock.Len(2)).Return([]error{nil, nil}, nil).Times(1),
+		mocks.mockOrderService.EXPECT().DeliverOrders(gomock.Any(), gomock.Len(1)).Return([]error{nil}, nil).Times(1),
+	)
+
+	err := NewOrderServiceV2(mocks.mockOrderService, nil, 2).StreamDeliverOrders(stream)
+
+	require.NoError(t, err)
+	require.Equal(t, uint32(3), stream.response.GetDelivered())
+	require.Len(t, stream.response.GetResults(), 3)
+}
+
+type watchOrdersStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events chan *order.OrderEvent
+}
+
+func (s *watchOrdersStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *watchOrdersStream) Send(event *order.OrderEvent) error {
+	s.events <- event
+	return nil
+}
+
+func TestImportDeliveriesV2(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	exp := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	content := "id,user,exp,weight_in_kg,price_in_rub\n" +
+		"1,1," + exp + ",1,10\n" +
+		"2,," + exp + ",1,10\n" +
+		"3,1," + exp + ",1,10\n"
+
+	mocks := newMocks(t)
+	gomock.InOrder(
+		mocks.mockOrderService.EXPECT().CheckDeliverOrders(gomock.Any(), gomock.Len(2)).
+			Return([]error{nil, storage.ErrDuplicateOrderID}, nil).Times(1),
+		mocks.mockOrderService.EXPECT().DeliverOrders(gomock.Any(), gomock.Len(2)).
+			Return([]error{nil, nil}, nil).Times(1),
+	)
+	v2 := NewOrderServiceV2(mocks.mockOrderService, nil, 2)
+
+	result, err := v2.ImportDeliveries(ctx, &order.ImportDeliveriesRequest{Content: []byte(content), DryRun: true})
+	require.NoError(t, err)
+	require.True(t, result.GetDryRun())
+	require.Equal(t, uint32(1), result.GetAccepted())
+	require.Equal(t, uint32(2), result.GetFailed())
+	var rows []uint32
+	var codesOf []codes.Code
+	for _, r := range result.GetRows() {
+		rows = append(rows, r.GetRow())
+		codesOf = append(codesOf, codes.Code(r.GetStatus().GetCode()))
+	}
+	require.Equal(t, []uint32{1, 2, 3}, rows)
+	require.Equal(t, []codes.Code{codes.OK, codes.InvalidArgument, codes.AlreadyExists}, codesOf)
+	require.Equal(t, []string{"user"}, fieldsOf(t, status.ErrorProto(result.GetRows()[1].GetStatus())))
+
+	result, err = v2.ImportDeliveries(ctx, &order.ImportDeliveriesRequest{Content: []byte(content)})
+	require.NoError(t, err)
+	require.False(t, result.GetDryRun())
+	require.Equal(t, uint32(2), result.GetAccepted())
+
+	_, err = v2.ImportDeliveries(ctx, &order.ImportDeliveriesRequest{Content: []byte("order,user\n1,1\n")})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Equal(t, []string{"content"}, fieldsOf(t, err))
+
+	_, err = v2.ImportDeliveries(ctx, &order.ImportDeliveriesRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestWatchOrdersV2(t *testing.T) {
+	t.Parallel()
+
+	bus := eventbus.NewBus(10, 10)
+	service := NewOrderServiceV2(newMocks(t).mockOrderService, bus, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &watchOrdersStream{ctx: ctx, events: make(chan *order.OrderEvent, 10)}
+	bus.Publish(dto.OrderEvent{OrderID: "1", RecipientID: "1", Status: model.StatusDelivered})
+
+	done := make(chan error)
+	go func() {
+		done <- service.WatchOrders(&order.WatchOrdersRequest{
+			RecipientId: "1",
+			Status:      order.OrderStatus_ORDER_STATUS_RETURNED,
+		}, stream)
+	}()
+	require.Eventually(t, func() bool {
+		bus.Publish(
+			dto.OrderEvent{OrderID: "2", RecipientID: "1", Status: model.StatusIssued},
+			dto.OrderEvent{OrderID: "3", RecipientID: "1", Status: model.StatusReturned, PickupPoint: "msk-1"},
+		)
+		return len(stream.events) != 0
+	}, time.Second, 10*time.Millisecond)
+
+	event := <-stream.events
+	require.Equal(t, "3", event.GetOrderId())
+	require.Equal(t, order.OrderStatus_ORDER_STATUS_RETURNED, event.GetStatus())
+	require.Equal(t, "msk-1", event.GetPickupPoint())
+	require.NotEmpty(t, event.GetId())
+
+	cancel()
+	require.Equal(t, codes.Canceled, status.Code(<-done))
+
+	// the events after the first one are resent
+	stream = &watchOrdersStream{ctx: context.Background(), events: make(chan *order.OrderEvent, 100)}
+	go func() {
+		done <- service.WatchOrders(&order.WatchOrdersRequest{AfterEventId: event.GetId()}, stream)
+	}()
+	require.Eventually(t, func() bool {
+		return len(stream.events) != 0
+	}, time.Second, 10*time.Millisecond)
+	bus.Close()
+	err := <-done
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	require.Equal(t, ReasonShuttingDown, errorInfoOf(t, err).GetReason())
+
+	err = NewOrderServiceV2(nil, eventbus.NewBus(10, 10), 0).WatchOrders(&order.WatchOrdersRequest{AfterEventId: event.GetId()}, stream)
+	require.Equal(t, codes.NotFound, status.Code(err))
+	require.Equal(t, ReasonEventNotFound, errorInfoOf(t, err).GetReason())
+}
+
+func TestIssueOrderV2(t *testing.T) {
+	t.Parallel()
+
+	var ctx = context.Background()
+
+	type test struct {
+		name   string
+		input  *order.IssueOrderRequest
+		code   codes.Code
+		mockFn func(m mocks)
+		status order.OrderStatus
+	}
+
+	tests := []test{
+		{
+			name:  "ok",
+			input: &order.IssueOrderRequest{OrderId: "1"},
+			code:  codes.OK,
+			mockFn: func(m mocks) {
+				m.mockOrderService.EXPECT().IssueOrders(gomock.Any(), []string{"1"}).Return(nil).Times(1)
+				m.mockOrderService.EXPECT().GetOrder(gomock.Any(), "1").
+					Return(model.Order{ID: "1", Status: model.StatusIssued}, nil).Times(1)
+			},
+			status: order.OrderStatus_ORDER_STATUS_ISSUED,
+		},
+		{
+			name:   "empty id",
+			input:  &order.IssueOrderRequest{},
+			code:   codes.InvalidArgument,
+			mockFn: func(m mocks) {},
+		},
+		{
+			name:  "expired",
+			input: &order.IssueOrderRequest{OrderId: "1"},
+			code:  codes.FailedPrecondition,
+			mockFn: func(m mocks) {
+				m.mockOrderService.EXPECT().IssueOrders(gomock.Any(), []string{"1"}).Return(service.ErrOrderHasExpired).Times(1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mocks := newMocks(t)
+			tt.mockFn(mocks)
+
+			result, err := NewOrderServiceV2(mocks.mockOrderService, nil, 0).IssueOrder(ctx, tt.input)
+
+			require.Equal(t, tt.code, status.Code(err))
+			require.Equal(t, tt.status, result.GetStatus())
+		})
+	}
+}
+
+func TestBatchIssueOrdersV2(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mocks := newMocks(t)
+	mocks.mockOrderService.EXPECT().IssueOrders(gomock.Any(), []string{"1", "2"}).Return(nil).Times(1)
+	mocks.mockOrderService.EXPECT().GetOrders(gomock.Any(), []string{"1", "2"}).Return([]model.Order{
+		{ID: "1", Status: model.StatusIssued},
+		{ID: "2", Status: model.StatusIssued},
+	}, nil).Times(1)
+
+	result, err := NewOrderServiceV2(mocks.mockOrderService, nil, 0).BatchIssueOrders(ctx, &order.BatchIssueOrdersRequest{
+		OrderIds: []string{"1", "2"},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, result.GetOrders(), 2)
+
+	_, err = NewOrderServiceV2(mocks.mockOrderService, nil, 0).BatchIssueOrders(ctx, &order.BatchIssueOrdersRequest{
+		OrderIds: []string{"1", "1"},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestListOrdersV2(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mocks := newMocks(t)
+	mocks.mockOrderService.EXPECT().ListOrders(gomock.Any(), dto.ListOrdersParam{
+		UserId: "2",
+		Size:   10,
+		Page:   1,
+		Status: model.StatusRefunded,
+	}).Return([]model.Order{{ID: "1", RecipientID: "2", Status: model.StatusRefunded}}, nil).Times(1)
+
+	result, err := NewOrderServiceV2(mocks.mockOrderService, nil, 0).ListOrders(ctx, &order.ListOrdersRequest{
+		RecipientId: "2",
+		PageSize:    10,
+		Page:        1,
+		Status:      order.OrderStatus_ORDER_STATUS_REFUNDED,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, result.GetOrders(), 1)
+	require.Equal(t, "1", result.GetOrders()[0].GetId())
+	require.Equal(t, order.OrderStatus_ORDER_STATUS_REFUNDED, result.GetOrders()[0].GetStatus())
+}
@@ -16,14 +16,14 @@ import (
 
 type (
 	cli interface {
-		Run(ctx context.Context, args []string)
+		Run(ctx context.Context, args []string) error
 		GetChangeNumberWorkers() <-chan int
 		GetOutput() <-chan string
 		Exit() <-chan struct{}
 	}
 
 	onCallProducer interface {
-		SendAsyncMessage(message dto.OnCallMessage) error
+		SendAsyncMessage(ctx context.Context, message dto.OnCallMessage) error
 	}
 
 	App struct {
@@ -82,15 +82,12 @@ func (a *App) worker(ctx context.Context, n int) {
 				return
 			}
 
-			a.output.Push(fmt.Sprintf("start: job=%s, n=%v, time=%s\n", job, n, time.Now().Format(model.TimeFormat)))
-			a.cli.Run(ctx, job)
+			calledAt := time.Now()
+			a.output.Push(fmt.Sprintf("start: job=%s, n=%v, time=%s\n", job, n, calledAt.Format(model.TimeFormat)))
+			err := a.cli.Run(ctx, job)
 			a.output.Push(fmt.Sprintf("stop: job=%s, n=%v, time=%s\n", job, n, time.Now().Format(model.TimeFormat)))
 
-			_ = a.onCall.SendAsyncMessage(dto.OnCallMessage{
-				CalledAt: time.Now(),
-				Method:   job[0],
-				Args:     strings.Join(job[1:], " "),
-			})
+			_ = a.onCall.SendAsyncMessage(ctx, newOnCallMessage(job, calledAt, err))
 
 		case <-a.startWorker:
 			go a.worker(ctx, rand.Intn(math.MaxInt))
@@ -101,6 +98,22 @@ func (a *App) worker(ctx context.Context, n int) {
 	}
 }
 
+func newOnCallMessage(job []string, calledAt time.Time, err error) dto.OnCallMessage {
+	message := dto.OnCallMessage{
+		CalledAt: calledAt,
+		Method:   job[0],
+		Args:     strings.Join(job[1:], " "),
+		Code:     dto.OnCallCodeOK,
+		Duration: time.Since(calledAt),
+		Peer:     dto.OnCallPeerCLI,
+	}
+	if err != nil {
+		message.Code = dto.OnCallCodeUnknown
+		message.Error = err.Error()
+	}
+	return message
+}
+
 func (a *App) Wait() {
 	a.wg.Wait()
 }
@@ -1,7 +1,52 @@
 package cache
 
+import (
+	"homework/pkg/ds"
+	"homework/pkg/ds/lfu"
+	"homework/pkg/ds/lru"
+	"homework/pkg/ds/sharded"
+	"homework/pkg/ds/tinylfu"
+	"time"
+)
+
+type Strategy string
+
+const (
+	LFU     Strategy = "lfu"
+	LRU     Strategy = "lru"
+	TinyLFU Strategy = "tinylfu"
+)
+
 type Cache[K comparable, V any] interface {
 	Get(K) (V, bool)
 	Put(K, V)
 	Remove(K) bool
+	Keys() []K
+	Range(func(K, V) bool)
+	// RangeFrequencies passes the frequency of every entry as the strategy counts it.
+	RangeFrequencies(func(K, V, int) bool)
+	SetFrequency(K, int)
+	Clear()
+	Stats() ds.Stats
+}
+
+// newCache returns LFU if the strategy is empty, janitor is used only by LFU.
+// entrySize is the average size of a value measured by the sizer, TinyLFU sizes its frequency sketch by it.
+// With more than one shard every shard is a cache of the strategy with its part of the capacity.
+func newCache[K comparable, V any](strategy Strategy, shards, capacity int, ttl, janitor time.Duration, sizer ds.Sizer[V], entrySize int, onRemove ds.OnRemove[K, V]) Cache[K, V] {
+	if shards > 1 {
+		return sharded.New[K, V](shards, capacity, func(capacity int) sharded.Shard[K, V] {
+			return newCache[K, V](strategy, 1, capacity, ttl, janitor, sizer, entrySize, onRemove)
+		})
+	}
+
+	switch strategy {
+	case LRU:
+		return lru.NewLRUCache[K, V](capacity, ttl, lru.WithSizer[K](sizer), lru.WithOnRemove(onRemove))
+	case TinyLFU:
+		return tinylfu.NewTinyLFU[K, V](capacity, ttl, tinylfu.WithSizer[K](sizer), tinylfu.WithEntrySize[K, V](entrySize),
+			tinylfu.WithOnRemove(onRemove))
+	default:
+		return lfu.NewLFU[K, V](capacity, ttl, lfu.WithSizer[K](sizer), lfu.WithJanitor[K, V](janitor), lfu.WithOnRemove(onRemove))
+	}
 }
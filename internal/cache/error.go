@@ -0,0 +1,8 @@
+package cache
+
+import "errors"
+
+var (
+	ErrCacheIsClosed = errors.New("cache is closed")
+	ErrCacheIsLocked = errors.New("cache shards are locked")
+)
@@ -0,0 +1,122 @@
+package cache
+
+import (
+	"hash/maphash"
+	"homework/pkg/ds"
+	"sync"
+	"time"
+)
+
+// keyIndex maps order ids or filters to the cached keys and keeps the version of their last invalidation.
+// It's sharded like the cache, so changes of different orders don't wait for each other.
+// A key is indexed with the generation of the put, so a value leaving the cache doesn't unindex a later put of the key.
+type keyIndex[T comparable] struct {
+	seed   maphash.Seed
+	shards []indexShard[T]
+}
+
+type indexShard[T comparable] struct {
+	lock        sync.Mutex
+	keys        map[T]map[KeyOrder]uint64
+	invalidated map[T]invalidationStamp
+	// sweptAt is when the expired stamps have been removed last time.
+	sweptAt time.Time
+}
+
+type invalidationStamp struct {
+	version uint64
+	at      time.Time
+}
+
+func newKeyIndex[T comparable](shards int) *keyIndex[T] {
+	index := &keyIndex[T]{
+		seed:   maphash.MakeSeed(),
+		shards: make([]indexShard[T], max(shards, 1)),
+	}
+	for i := range index.shards {
+		index.shards[i].keys = make(map[T]map[KeyOrder]uint64)
+		index.shards[i].invalidated = make(map[T]invalidationStamp)
+	}
+	return index
+}
+
+func (i *keyIndex[T]) shard(t T) *indexShard[T] {
+	return &i.shards[ds.Hash(i.seed, t)%uint64(len(i.shards))]
+}
+
+func (i *keyIndex[T]) add(t T, k KeyOrder, generation uint64) {
+	shard := i.shard(t)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+
+	keys, ok := shard.keys[t]
+	if !ok {
+		keys = make(map[KeyOrder]uint64)
+		shard.keys[t] = keys
+	}
+	keys[k] = generation
+}
+
+// remove unindexes the key if it hasn't been put again since the generation.
+func (i *keyIndex[T]) remove(t T, k KeyOrder, generation uint64) {
+	shard := i.shard(t)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+
+	keys := shard.keys[t]
+	if g, ok := keys[k]; !ok || g != generation {
+		return
+	}
+	delete(keys, k)
+	if len(keys) == 0 {
+		delete(shard.keys, t)
+	}
+}
+
+func (i *keyIndex[T]) invalidatedSince(t T, version uint64) bool {
+	shard := i.shard(t)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+
+	return shard.invalidated[t].version > version
+}
+
+// invalidate stamps the version and takes the keys out of the index.
+// Stamps older than keep are forgotten: reads started that long ago have already finished. They are swept
+// at most once per keep, so a batch of invalidations doesn't walk the stamps on every id.
+func (i *keyIndex[T]) invalidate(t T, version uint64, keep time.Duration) []KeyOrder {
+	shard := i.shard(t)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+
+	now := time.Now()
+	if now.Sub(shard.sweptAt) > keep {
+		for t, stamp := range shard.invalidated {
+			if now.Sub(stamp.at) > keep {
+				delete(shard.invalidated, t)
+			}
+		}
+		shard.sweptAt = now
+	}
+
+	// concurrent invalidations of t may stamp out of order, the stamp never moves back
+	if stamp := shard.invalidated[t]; version > stamp.version {
+		shard.invalidated[t] = invalidationStamp{version: version, at: now}
+	}
+	keys := make([]KeyOrder, 0, len(shard.keys[t]))
+	for k := range shard.keys[t] {
+		keys = append(keys, k)
+	}
+	delete(shard.keys, t)
+	return keys
+}
+
+// clear removes the keys, the versions are kept.
+func (i *keyIndex[T]) clear() {
+	for j := range i.shards {
+		shard := &i.shards[j]
+		shard.lock.Lock()
+		shard.keys = make(map[T]map[KeyOrder]uint64)
+		shard.lock.Unlock()
+	}
+}
@@ -1,77 +1,309 @@
 package cache
 
 import (
+	"context"
+	"homework/internal/dto"
+	"homework/internal/metrics"
 	"homework/internal/model"
-	"homework/pkg/ds/lfu"
+	"homework/pkg/ds"
+	"io"
+	"log"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unsafe"
 )
 
+// estimatedOrdersPerEntry is the length of a typical cached list, it estimates the number of entries
+// of the capacity in bytes.
+const estimatedOrdersPerEntry = 10
+
 type (
 	KeyOrder = string
 
 	OrdersCache struct {
-		cache Cache[KeyOrder, []model.Order]
+		cache Cache[KeyOrder, ordersEntry]
+		ttl   time.Duration
+		// stale is how long an expired result is still served while it's being refreshed.
+		stale time.Duration
+		trace *traceRecorder
+
+		// the indexes keep the version of the last invalidation,
+		// so a result read before that invalidation is never put back into the cache.
+		getKeyByID     *keyIndex[string]
+		getKeyByFilter *keyIndex[dto.OrderFilter]
+		generation     atomic.Uint64
+		// version counts the invalidations applied by the instance. It's ordered by the arrival of the
+		// invalidations, so it doesn't depend on the clocks of the instances that have published them.
+		version atomic.Uint64
 
-		getKeyByID map[string][]KeyOrder
+		closed atomic.Bool
+		// probing is set while Check waits for the shards.
+		probing atomic.Bool
+		// seeds are the frequencies of the snapshot, they are set when the keys are put by the warm-up.
+		seeds sync.Map
+	}
+
+	ordersEntry struct {
+		orders    []model.Order
+		expiredAt time.Time
+		// param is the query of the result, nil if it has been put by the key.
+		param *dto.GetParam
+		// generation is the put of the entry in the indexes.
+		generation uint64
+	}
 
-		lock sync.RWMutex
+	OrdersCacheConfig struct {
+		Strategy Strategy
+		Capacity int
+		TTL      time.Duration
+		// Stale keeps results after TTL, Lookup returns them as not fresh.
+		Stale time.Duration
+		// JanitorInterval is how often expired results are removed in the background, 0 disables it.
+		JanitorInterval time.Duration
+		// Trace receives every looked up key, nil disables recording.
+		Trace io.WriteCloser
+		// CapacityInBytes makes Capacity the estimated memory of the cached orders instead of the number of results.
+		CapacityInBytes bool
+		// Shards is the number of independently locked parts of the cache, 0 or 1 means one.
+		Shards int
 	}
 )
 
 func NewOrdersCache(cap int, ttl time.Duration) *OrdersCache {
-	return &OrdersCache{
-		cache:      lfu.NewLFU[KeyOrder, []model.Order](cap, ttl),
-		getKeyByID: make(map[string][]KeyOrder),
+	return NewOrdersCacheWithConfig(OrdersCacheConfig{Capacity: cap, TTL: ttl})
+}
+
+func NewOrdersCacheWithConfig(cfg OrdersCacheConfig) *OrdersCache {
+	sizer, entrySize := ds.EntrySizer[ordersEntry], 1
+	if cfg.CapacityInBytes {
+		sizer, entrySize = ordersEntrySize, ordersEntrySize(ordersEntry{orders: make([]model.Order, estimatedOrdersPerEntry)})
 	}
+
+	var trace *traceRecorder
+	if cfg.Trace != nil {
+		trace = newTraceRecorder(cfg.Trace)
+	}
+
+	o := &OrdersCache{
+		ttl:            cfg.TTL,
+		stale:          cfg.Stale,
+		trace:          trace,
+		getKeyByID:     newKeyIndex[string](cfg.Shards),
+		getKeyByFilter: newKeyIndex[dto.OrderFilter](cfg.Shards),
+	}
+	o.cache = newCache[KeyOrder](cfg.Strategy, cfg.Shards, cfg.Capacity, cfg.TTL+cfg.Stale, cfg.JanitorInterval, sizer, entrySize, o.unindex)
+	return o
+}
+
+// ordersEntrySize estimates the memory of the entry, strings are counted by their length.
+func ordersEntrySize(entry ordersEntry) int {
+	size := int(unsafe.Sizeof(entry))
+	for _, order := range entry.orders {
+		size += int(unsafe.Sizeof(order)) + len(order.ID) + len(order.RecipientID) + len(order.Status)
+	}
+	return size
 }
 
 func (o *OrdersCache) Put(k string, v []model.Order) {
-	o.lock.Lock()
-	defer o.lock.Unlock()
+	o.put(k, nil, v, o.generation.Add(1))
+}
 
-	o.cache.Put(k, v)
+// Version is taken before the query is read, PutSince compares it with the invalidations applied since then.
+func (o *OrdersCache) Version() uint64 {
+	return o.version.Load()
+}
+
+// PutSince puts the result of the query read at the version unless it has been invalidated since then.
+// The key is indexed before it's put and the versions are checked again after,
+// so an invalidation running at the same time either finds the key or is seen by the check.
+func (o *OrdersCache) PutSince(param dto.GetParam, v []model.Order, version uint64) bool {
+	if o.invalidatedSince(param, v, version) {
+		return false
+	}
 
+	k := param.String()
+	generation := o.generation.Add(1)
+	if param.Ids == nil {
+		o.getKeyByFilter.add(param.Filter(), k, generation)
+	}
+	// an empty result for the ids must be dropped when the orders are added
+	for _, id := range param.Ids {
+		o.getKeyByID.add(id, k, generation)
+	}
+	o.put(k, &param, v, generation)
+
+	if o.invalidatedSince(param, v, version) {
+		o.cache.Remove(k)
+		return false
+	}
+	return true
+}
+
+func (o *OrdersCache) invalidatedSince(param dto.GetParam, v []model.Order, version uint64) bool {
 	for _, order := range v {
-		_, ok := o.getKeyByID[order.ID]
-		if !ok {
-			o.getKeyByID[order.ID] = make([]KeyOrder, 0, 1)
+		if o.getKeyByID.invalidatedSince(order.ID, version) {
+			return true
+		}
+	}
+	for _, id := range param.Ids {
+		if o.getKeyByID.invalidatedSince(id, version) {
+			return true
 		}
-		o.getKeyByID[order.ID] = append(o.getKeyByID[order.ID], k)
 	}
+	return param.Ids == nil && o.getKeyByFilter.invalidatedSince(param.Filter(), version)
 }
 
-func (o *OrdersCache) RemoveById(id string) {
-	o.lock.Lock()
-	defer o.lock.Unlock()
-
-	o.removeById(id)
+func (o *OrdersCache) put(k string, param *dto.GetParam, v []model.Order, generation uint64) {
+	for _, order := range v {
+		o.getKeyByID.add(order.ID, k, generation)
+	}
+	o.cache.Put(k, ordersEntry{
+		orders:     v,
+		expiredAt:  time.Now().Add(o.ttl),
+		param:      param,
+		generation: generation,
+	})
+	if freq, ok := o.seeds.LoadAndDelete(k); ok {
+		o.cache.SetFrequency(k, freq.(int))
+	}
 }
 
-func (o *OrdersCache) removeById(id string) {
-	keys, ok := o.getKeyByID[id]
-	if !ok {
+// unindex takes the key of the entry that has left the cache out of the indexes, so they don't grow with
+// every query ever cached. It's called under the lock of the cache shard.
+func (o *OrdersCache) unindex(k KeyOrder, entry ordersEntry) {
+	for _, order := range entry.orders {
+		o.getKeyByID.remove(order.ID, k, entry.generation)
+	}
+	if entry.param == nil {
 		return
 	}
+	if entry.param.Ids == nil {
+		o.getKeyByFilter.remove(entry.param.Filter(), k, entry.generation)
+	}
+	for _, id := range entry.param.Ids {
+		o.getKeyByID.remove(id, k, entry.generation)
+	}
+}
 
+func (o *OrdersCache) RemoveById(id string) {
+	o.Invalidate([]string{id}, nil)
+}
+
+func (o *OrdersCache) removeKeys(keys []KeyOrder) int {
+	removed := 0
 	for _, k := range keys {
-		o.cache.Remove(k)
+		if o.cache.Remove(k) {
+			removed++
+		}
 	}
-	delete(o.getKeyByID, id)
+	return removed
 }
 
 func (o *OrdersCache) RemoveByIds(ids []string) {
-	o.lock.Lock()
-	defer o.lock.Unlock()
+	o.Invalidate(ids, nil)
+}
+
+// Invalidate removes cached results with the orders and cached lists the changed orders match,
+// then stamps them with the next version, so a query read before it isn't put back even if the message is late.
+func (o *OrdersCache) Invalidate(ids []string, filters []dto.OrderFilter) {
+	version := o.version.Add(1)
+	removed := 0
+	defer func() {
+		metrics.AddOrdersCacheInvalidations(removed)
+	}()
 
 	for _, id := range ids {
-		o.removeById(id)
+		removed += o.removeKeys(o.getKeyByID.invalidate(id, version, o.ttl+o.stale))
+	}
+
+	for _, changed := range filters {
+		for _, filter := range changed.Matching() {
+			removed += o.removeKeys(o.getKeyByFilter.invalidate(filter, version, o.ttl+o.stale))
+		}
 	}
 }
 
 func (o *OrdersCache) Get(k string) ([]model.Order, bool) {
-	o.lock.RLock()
-	defer o.lock.RUnlock()
+	orders, fresh, ok := o.Lookup(k)
+	if !ok || !fresh {
+		return nil, false
+	}
+	return orders, true
+}
+
+// Lookup returns the result even if it has expired but is still kept as stale.
+func (o *OrdersCache) Lookup(k string) (orders []model.Order, fresh bool, ok bool) {
+	if o.trace != nil {
+		o.trace.record(k)
+	}
+
+	entry, ok := o.cache.Get(k)
+	if !ok {
+		return nil, false, false
+	}
+
+	now := time.Now()
+	if now.After(entry.expiredAt.Add(o.stale)) {
+		return nil, false, false
+	}
+	return entry.orders, now.Before(entry.expiredAt), true
+}
+
+func (o *OrdersCache) Stats() ds.Stats {
+	return o.cache.Stats()
+}
+
+func (o *OrdersCache) Keys() []string {
+	keys := o.cache.Keys()
+	slices.Sort(keys)
+	return keys
+}
+
+// Clear removes every result, the versions are kept so reads in progress still can't put stale results.
+func (o *OrdersCache) Clear() {
+	o.cache.Clear()
+	o.getKeyByID.clear()
+	o.getKeyByFilter.clear()
+}
+
+// Check fails when the cache is closed or a shard isn't unlocked before the context is done, so a stuck call
+// holding the lock of a shard shows up. A probe still waiting for the shards fails the next checks without
+// starting another one.
+func (o *OrdersCache) Check(ctx context.Context) error {
+	if o.closed.Load() {
+		return ErrCacheIsClosed
+	}
+	if !o.probing.CompareAndSwap(false, true) {
+		return ErrCacheIsLocked
+	}
+
+	done := make(chan struct{})
+	go func() {
+		// the stats take the lock of every shard
+		o.cache.Stats()
+		o.probing.Store(false)
+		close(done)
+	}()
 
-	return o.cache.Get(k)
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ErrCacheIsLocked
+	}
+}
+
+// Close stops the background work of the underlying cache and flushes the trace.
+func (o *OrdersCache) Close() {
+	o.closed.Store(true)
+	if closer, ok := o.cache.(interface{ Close() }); ok {
+		closer.Close()
+	}
+	if o.trace != nil {
+		if err := o.trace.close(); err != nil {
+			log.Printf("[cache.OrdersCache] trace error: %v", err)
+		}
+	}
 }
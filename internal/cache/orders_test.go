@@ -1,8 +1,13 @@
 package cache
 
 import (
+	"bytes"
+	"context"
 	"github.com/stretchr/testify/require"
+	"homework/internal/dto"
 	"homework/internal/model"
+	"homework/pkg/ds"
+	"sync"
 	"testing"
 	"time"
 )
@@ -33,6 +38,42 @@ func TestCacheOrders_PutGet(t *testing.T) {
 	require.Equal(t, value, cached)
 }
 
+func TestCacheOrders_CheckAfterClose(t *testing.T) {
+	cache := NewOrdersCache(10, time.Hour)
+	require.NoError(t, cache.Check(context.Background()))
+
+	cache.Close()
+
+	require.ErrorIs(t, cache.Check(context.Background()), ErrCacheIsClosed)
+}
+
+type lockedCache struct {
+	Cache[KeyOrder, ordersEntry]
+	unlock chan struct{}
+}
+
+func (c lockedCache) Stats() ds.Stats {
+	<-c.unlock
+	return c.Cache.Stats()
+}
+
+func TestCacheOrders_CheckLockedShard(t *testing.T) {
+	cache := NewOrdersCache(10, time.Hour)
+	unlock := make(chan struct{})
+	cache.cache = lockedCache{Cache: cache.cache, unlock: unlock}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, cache.Check(ctx), ErrCacheIsLocked)
+	// the probe is still waiting, another one isn't started
+	require.ErrorIs(t, cache.Check(context.Background()), ErrCacheIsLocked)
+
+	close(unlock)
+	require.Eventually(t, func() bool {
+		return cache.Check(context.Background()) == nil
+	}, time.Second, time.Millisecond)
+}
+
 func TestCacheOrders_PutRemoveByID(t *testing.T) {
 	cache := NewOrdersCache(10, time.Hour)
 	cache.Put(key, value)
@@ -52,3 +93,275 @@ func TestCacheOrders_PutRemoveByIDs(t *testing.T) {
 	_, ok := cache.Get(key)
 	require.False(t, ok)
 }
+
+func TestCacheOrders_PutSinceAfterInvalidate(t *testing.T) {
+	cache := NewOrdersCache(10, time.Hour)
+	param := dto.GetParam{Ids: []string{order1.ID, order2.ID}}
+	version := cache.Version()
+
+	cache.Invalidate([]string{order1.ID}, nil)
+	ok := cache.PutSince(param, value, version)
+	require.False(t, ok)
+
+	_, ok = cache.Get(param.String())
+	require.False(t, ok)
+}
+
+func TestCacheOrders_PutSinceBeforeInvalidate(t *testing.T) {
+	cache := NewOrdersCache(10, time.Hour)
+	param := dto.GetParam{Ids: []string{order1.ID, order2.ID}}
+
+	cache.Invalidate([]string{order1.ID}, nil)
+	ok := cache.PutSince(param, value, cache.Version())
+	require.True(t, ok)
+
+	cached, ok := cache.Get(param.String())
+	require.True(t, ok)
+	require.Equal(t, value, cached)
+}
+
+func TestCacheOrders_InvalidateOutOfOrder(t *testing.T) {
+	index := newKeyIndex[string](1)
+
+	index.invalidate(order1.ID, 2, time.Hour)
+	index.invalidate(order1.ID, 1, time.Hour)
+
+	require.True(t, index.invalidatedSince(order1.ID, 1))
+	require.False(t, index.invalidatedSince(order1.ID, 2))
+}
+
+func TestCacheOrders_InvalidateEmptyResultByID(t *testing.T) {
+	cache := NewOrdersCache(10, time.Hour)
+	param := dto.GetParam{Ids: []string{order1.ID}}
+	cache.PutSince(param, []model.Order{}, cache.Version())
+
+	cache.Invalidate([]string{order1.ID}, []dto.OrderFilter{{RecipientId: "user", Status: model.StatusDelivered}})
+
+	_, ok := cache.Get(param.String())
+	require.False(t, ok)
+}
+
+func TestCacheOrders_InvalidateByFilter(t *testing.T) {
+	t.Parallel()
+	changed := dto.OrderFilter{RecipientId: "user", Status: model.StatusDelivered}
+
+	tests := []struct {
+		name        string
+		param       dto.GetParam
+		invalidated bool
+	}{
+		{
+			name:        "same recipient and status",
+			param:       dto.GetParam{RecipientId: "user", Status: model.StatusDelivered, Limit: 10},
+			invalidated: true,
+		},
+		{
+			name:        "same recipient",
+			param:       dto.GetParam{RecipientId: "user", Limit: 10},
+			invalidated: true,
+		},
+		{
+			name:        "same status",
+			param:       dto.GetParam{Status: model.StatusDelivered, Limit: 10, Offset: 10},
+			invalidated: true,
+		},
+		{
+			name:        "all orders",
+			param:       dto.GetParam{Limit: 10},
+			invalidated: true,
+		},
+		{
+			name:        "other recipient",
+			param:       dto.GetParam{RecipientId: "other", Limit: 10},
+			invalidated: false,
+		},
+		{
+			name:        "other status",
+			param:       dto.GetParam{RecipientId: "user", Status: model.StatusRefunded, Limit: 10},
+			invalidated: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cache := NewOrdersCache(10, time.Hour)
+			cache.PutSince(tt.param, []model.Order{}, cache.Version())
+
+			cache.Invalidate([]string{order1.ID}, []dto.OrderFilter{changed})
+
+			_, ok := cache.Get(tt.param.String())
+			require.Equal(t, tt.invalidated, !ok)
+		})
+	}
+}
+
+func TestCacheOrders_PutSinceAfterFilterInvalidate(t *testing.T) {
+	cache := NewOrdersCache(10, time.Hour)
+	param := dto.GetParam{RecipientId: "user", Limit: 10}
+	version := cache.Version()
+
+	cache.Invalidate([]string{order1.ID}, []dto.OrderFilter{{RecipientId: "user", Status: model.StatusDelivered}})
+
+	ok := cache.PutSince(param, []model.Order{}, version)
+	require.False(t, ok)
+}
+
+func indexedKeys[T comparable](index *keyIndex[T]) int {
+	n := 0
+	for i := range index.shards {
+		for _, keys := range index.shards[i].keys {
+			n += len(keys)
+		}
+	}
+	return n
+}
+
+func TestCacheOrders_EvictedKeysAreUnindexed(t *testing.T) {
+	for _, strategy := range []Strategy{LFU, LRU, TinyLFU} {
+		cache := NewOrdersCacheWithConfig(OrdersCacheConfig{Strategy: strategy, Capacity: 1, TTL: time.Hour})
+		for i := 0; i < 100; i++ {
+			param := dto.GetParam{RecipientId: "user", Limit: uint(i + 1)}
+			cache.PutSince(param, []model.Order{{ID: param.String()}}, cache.Version())
+		}
+
+		require.Equal(t, 1, indexedKeys(cache.getKeyByFilter), strategy)
+		require.Equal(t, 1, indexedKeys(cache.getKeyByID), strategy)
+	}
+}
+
+func TestCacheOrders_ReplacedKeyStaysIndexed(t *testing.T) {
+	cache := NewOrdersCache(10, time.Hour)
+	cache.Put(key, []model.Order{order1})
+	cache.Put(key, []model.Order{order1, order2})
+
+	cache.Invalidate([]string{order1.ID}, nil)
+
+	_, ok := cache.Get(key)
+	require.False(t, ok)
+	require.Zero(t, indexedKeys(cache.getKeyByID))
+}
+
+func TestCacheOrders_LookupStale(t *testing.T) {
+	cache := NewOrdersCacheWithConfig(OrdersCacheConfig{Capacity: 10, Stale: time.Hour})
+	cache.Put(key, value)
+
+	_, ok := cache.Get(key)
+	require.False(t, ok)
+
+	cached, fresh, ok := cache.Lookup(key)
+	require.True(t, ok)
+	require.False(t, fresh)
+	require.Equal(t, value, cached)
+}
+
+func TestCacheOrders_LookupFresh(t *testing.T) {
+	cache := NewOrdersCacheWithConfig(OrdersCacheConfig{Capacity: 10, TTL: time.Hour, Stale: time.Hour})
+	cache.Put(key, value)
+
+	cached, fresh, ok := cache.Lookup(key)
+	require.True(t, ok)
+	require.True(t, fresh)
+	require.Equal(t, value, cached)
+}
+
+func TestCacheOrders_CapacityInBytes(t *testing.T) {
+	small := []model.Order{order1}
+	cache := NewOrdersCacheWithConfig(OrdersCacheConfig{
+		Capacity:        2 * ordersEntrySize(ordersEntry{orders: small}),
+		TTL:             time.Hour,
+		CapacityInBytes: true,
+	})
+
+	cache.Put("small1", small)
+	cache.Put("small2", small)
+	require.Len(t, cache.Keys(), 2)
+
+	cache.Put(key, value)
+	require.Len(t, cache.Keys(), 1)
+
+	cache.Put("huge", make([]model.Order, 100))
+	_, ok := cache.Get("huge")
+	require.False(t, ok)
+}
+
+func TestCacheOrders_Strategy(t *testing.T) {
+	t.Parallel()
+
+	for _, strategy := range []Strategy{LFU, LRU, TinyLFU} {
+		strategy := strategy
+		t.Run(string(strategy), func(t *testing.T) {
+			t.Parallel()
+			cache := NewOrdersCacheWithConfig(OrdersCacheConfig{Strategy: strategy, Capacity: 10, TTL: time.Hour})
+			cache.Put(key, value)
+
+			cached, ok := cache.Get(key)
+			require.True(t, ok)
+			require.Equal(t, value, cached)
+
+			cache.RemoveById(order1.ID)
+			_, ok = cache.Get(key)
+			require.False(t, ok)
+		})
+	}
+}
+
+func TestCacheOrders_Sharded(t *testing.T) {
+	cache := NewOrdersCacheWithConfig(OrdersCacheConfig{Capacity: 100, TTL: time.Hour, Shards: 4})
+	defer cache.Close()
+
+	for i := 0; i < 10; i++ {
+		param := dto.GetParam{RecipientId: "user", Offset: uint(i)}
+		require.True(t, cache.PutSince(param, value, cache.Version()))
+	}
+	require.Len(t, cache.Keys(), 10)
+
+	cache.Invalidate(nil, []dto.OrderFilter{{RecipientId: "user"}})
+	require.Empty(t, cache.Keys())
+	require.Equal(t, 0, cache.Stats().Size)
+}
+
+func TestCacheOrders_Concurrent(t *testing.T) {
+	cache := NewOrdersCacheWithConfig(OrdersCacheConfig{Capacity: 10, TTL: time.Millisecond, JanitorInterval: time.Millisecond, Shards: 4})
+	defer cache.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				param := dto.GetParam{RecipientId: order1.ID, Offset: uint(j % 20)}
+				cache.PutSince(param, value, cache.Version())
+				cache.Lookup(param.String())
+				cache.Invalidate([]string{order2.ID}, []dto.OrderFilter{{RecipientId: order1.ID}})
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+type traceBuffer struct {
+	bytes.Buffer
+}
+
+func (b *traceBuffer) Close() error {
+	return nil
+}
+
+func TestCacheOrders_Trace(t *testing.T) {
+	var buf traceBuffer
+	cache := NewOrdersCacheWithConfig(OrdersCacheConfig{Capacity: 10, TTL: time.Hour, Trace: &buf})
+	cache.Get("key1")
+	cache.Get("key2")
+
+	cache.Close()
+	// lookups in flight during shutdown are dropped
+	cache.Get("key3")
+	cache.Close()
+
+	keys, err := ReadTrace(&buf)
+	require.NoError(t, err)
+	require.Equal(t, []string{"key1", "key2"}, keys)
+}
@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"bufio"
+	"encoding/json"
+	"homework/internal/dto"
+	"io"
+	"slices"
+)
+
+// SnapshotEntry is a cached query with its frequency counted by the strategy of the cache.
+type SnapshotEntry struct {
+	Param     dto.GetParam `json:"param"`
+	Frequency int          `json:"frequency"`
+}
+
+// Snapshot returns at most n queries the strategy counts as the most frequent, the frequency survives refreshes
+// of the results. Results put by the key are skipped.
+func (o *OrdersCache) Snapshot(n int) []SnapshotEntry {
+	var entries []SnapshotEntry
+	o.cache.RangeFrequencies(func(_ KeyOrder, entry ordersEntry, freq int) bool {
+		if entry.param != nil {
+			entries = append(entries, SnapshotEntry{Param: *entry.param, Frequency: freq})
+		}
+		return true
+	})
+
+	slices.SortFunc(entries, func(a, b SnapshotEntry) int {
+		return b.Frequency - a.Frequency
+	})
+	return entries[:min(n, len(entries))]
+}
+
+// Seed restores the frequencies of the snapshot when the queries are put, so the warmed up results aren't
+// the first to be evicted.
+func (o *OrdersCache) Seed(entries []SnapshotEntry) {
+	for _, entry := range entries {
+		o.seeds.Store(entry.Param.String(), entry.Frequency)
+	}
+}
+
+// WriteSnapshot writes the entries one JSON object per line.
+func WriteSnapshot(w io.Writer, entries []SnapshotEntry) error {
+	buf := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buf)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+	}
+	return buf.Flush()
+}
+
+// ReadSnapshot reads the entries written by WriteSnapshot.
+func ReadSnapshot(r io.Reader) ([]SnapshotEntry, error) {
+	var entries []SnapshotEntry
+	decoder := json.NewDecoder(r)
+	for decoder.More() {
+		var entry SnapshotEntry
+		if err := decoder.Decode(&entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
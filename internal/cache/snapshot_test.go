@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/require"
+	"homework/internal/dto"
+	"testing"
+	"time"
+)
+
+func TestCacheOrders_Snapshot(t *testing.T) {
+	cache := NewOrdersCacheWithConfig(OrdersCacheConfig{Capacity: 10, TTL: time.Hour, Shards: 2})
+	hot := dto.GetParam{RecipientId: "hot", Limit: 10}
+	cold := dto.GetParam{RecipientId: "cold", Limit: 10}
+	cache.PutSince(hot, value, cache.Version())
+	cache.PutSince(cold, value, cache.Version())
+	cache.Put(key, value)
+
+	for i := 0; i < 3; i++ {
+		cache.Get(hot.String())
+	}
+	cache.Get(cold.String())
+	cache.Get(key)
+
+	require.Equal(t, []SnapshotEntry{{Param: hot, Frequency: 4}, {Param: cold, Frequency: 2}}, cache.Snapshot(10))
+	require.Equal(t, []SnapshotEntry{{Param: hot, Frequency: 4}}, cache.Snapshot(1))
+
+	// a refresh doesn't start the frequency over
+	cache.PutSince(hot, value, cache.Version())
+	require.Equal(t, []SnapshotEntry{{Param: hot, Frequency: 5}}, cache.Snapshot(1))
+}
+
+func TestCacheOrders_Seed(t *testing.T) {
+	cache := NewOrdersCacheWithConfig(OrdersCacheConfig{Capacity: 2, TTL: time.Hour})
+	hot := dto.GetParam{RecipientId: "hot", Limit: 10}
+	cold := dto.GetParam{RecipientId: "cold", Limit: 10}
+	cache.Seed([]SnapshotEntry{{Param: hot, Frequency: 5}})
+
+	cache.PutSince(hot, value, cache.Version())
+	cache.PutSince(cold, value, cache.Version())
+	cache.Get(cold.String())
+	cache.Put(key, value)
+
+	require.Equal(t, []SnapshotEntry{{Param: hot, Frequency: 5}}, cache.Snapshot(10))
+}
+
+func TestSnapshot_WriteRead(t *testing.T) {
+	entries := []SnapshotEntry{
+		{Param: dto.GetParam{Ids: []string{"1", "2"}}, Frequency: 5},
+		{Param: dto.GetParam{RecipientId: "user", Status: "delivered", Order: "DESC", Limit: 10, Offset: 20}, Frequency: 1},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteSnapshot(&buf, entries))
+
+	read, err := ReadSnapshot(&buf)
+	require.NoError(t, err)
+	require.Equal(t, entries, read)
+}
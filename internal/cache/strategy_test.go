@@ -0,0 +1,118 @@
+package cache
+
+import (
+	"fmt"
+	"homework/internal/dto"
+	"homework/internal/model"
+	"homework/pkg/ds"
+	"math/rand"
+	"os"
+	"testing"
+	"time"
+)
+
+const (
+	traceLength   = 100_000
+	traceUsers    = 5_000
+	traceCapacity = 500
+)
+
+// listOrdersTrace generates ListOrders keys: users and pages are zipf distributed,
+// with shift the popular users change in the middle of the trace.
+func listOrdersTrace(shift bool) []string {
+	r := rand.New(rand.NewSource(1))
+	users := rand.NewZipf(r, 1.1, 1, traceUsers-1)
+	pages := rand.NewZipf(r, 2, 1, 20)
+
+	keys := make([]string, 0, traceLength)
+	for i := 0; i < traceLength; i++ {
+		user := users.Uint64()
+		if shift && i > traceLength/2 {
+			user = traceUsers - 1 - user
+		}
+		page := uint(pages.Uint64()) + 1
+		param := dto.GetParam{
+			RecipientId: fmt.Sprint(user),
+			Limit:       20,
+			Offset:      20 * (page - 1),
+			Order:       "DESC",
+		}
+		keys = append(keys, param.String())
+	}
+	return keys
+}
+
+// traces returns the generated traces and the one recorded with cache.yml trace_path if ORDERS_CACHE_TRACE is set.
+func traces(b *testing.B) map[string][]string {
+	traces := map[string][]string{
+		"zipf":     listOrdersTrace(false),
+		"shifting": listOrdersTrace(true),
+	}
+
+	path := os.Getenv("ORDERS_CACHE_TRACE")
+	if path == "" {
+		return traces
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer file.Close()
+
+	recorded, err := ReadTrace(file)
+	if err != nil {
+		b.Fatal(err)
+	}
+	traces["recorded"] = recorded
+	return traces
+}
+
+// BenchmarkOrdersCache_Parallel reads hot ListOrders pages from every goroutine,
+// every tenth call puts a page and every hundredth invalidates the orders of one user.
+func BenchmarkOrdersCache_Parallel(b *testing.B) {
+	trace := listOrdersTrace(false)
+
+	for _, shards := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			cache := NewOrdersCacheWithConfig(OrdersCacheConfig{Capacity: traceCapacity, TTL: time.Hour, Shards: shards})
+			defer cache.Close()
+
+			b.RunParallel(func(pb *testing.PB) {
+				r := rand.New(rand.NewSource(rand.Int63()))
+				for i := 0; pb.Next(); i++ {
+					key := trace[r.Intn(len(trace))]
+					switch {
+					case i%100 == 0:
+						cache.Invalidate(nil, []dto.OrderFilter{{RecipientId: fmt.Sprint(r.Intn(traceUsers))}})
+					case i%10 == 0:
+						cache.Put(key, nil)
+					default:
+						cache.Get(key)
+					}
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkStrategy_HitRate(b *testing.B) {
+	for name, trace := range traces(b) {
+		for _, strategy := range []Strategy{LFU, LRU, TinyLFU} {
+			b.Run(fmt.Sprintf("%s/%s", name, strategy), func(b *testing.B) {
+				var hits int
+				for i := 0; i < b.N; i++ {
+					cache := newCache[KeyOrder](strategy, 1, traceCapacity, time.Hour, 0, ds.EntrySizer[[]model.Order], 1, nil)
+					hits = 0
+					for _, key := range trace {
+						if _, ok := cache.Get(key); ok {
+							hits++
+							continue
+						}
+						cache.Put(key, nil)
+					}
+				}
+				b.ReportMetric(100*float64(hits)/float64(len(trace)), "hit%")
+			})
+		}
+	}
+}
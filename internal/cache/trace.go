@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"bufio"
+	"io"
+	"log"
+	"sync"
+)
+
+const traceBufferSize = 1024
+
+// traceRecorder writes the looked up keys one per line in the background,
+// keys are dropped if the writer falls behind, so lookups never wait for it.
+// keys is never closed, so a lookup after close only drops its key.
+type traceRecorder struct {
+	w         io.WriteCloser
+	keys      chan string
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newTraceRecorder(w io.WriteCloser) *traceRecorder {
+	r := &traceRecorder{
+		w:    w,
+		keys: make(chan string, traceBufferSize),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+func (r *traceRecorder) run() {
+	defer close(r.done)
+
+	buf := bufio.NewWriter(r.w)
+	write := func(key string) {
+		if _, err := buf.WriteString(key + "\n"); err != nil {
+			log.Printf("[cache.traceRecorder] error: %v", err)
+		}
+	}
+	for {
+		select {
+		case key := <-r.keys:
+			write(key)
+		case <-r.stop:
+			// the keys recorded before close are still written
+			for {
+				select {
+				case key := <-r.keys:
+					write(key)
+				default:
+					if err := buf.Flush(); err != nil {
+						log.Printf("[cache.traceRecorder] error: %v", err)
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+func (r *traceRecorder) record(key string) {
+	select {
+	case <-r.stop:
+	case r.keys <- key:
+	default:
+	}
+}
+
+func (r *traceRecorder) close() error {
+	var err error
+	r.closeOnce.Do(func() {
+		close(r.stop)
+		<-r.done
+		err = r.w.Close()
+	})
+	return err
+}
+
+// ReadTrace reads the keys recorded with OrdersCacheConfig.Trace.
+func ReadTrace(r io.Reader) ([]string, error) {
+	var keys []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		keys = append(keys, scanner.Text())
+	}
+	return keys, scanner.Err()
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+type cacheExecutor struct {
+	cache ordersCache
+}
+
+func newCacheExecutor(cache ordersCache) cacheExecutor {
+	return cacheExecutor{cache: cache}
+}
+
+func (e cacheExecutor) handle(_ context.Context, args []string) (string, error) {
+	if len(args) == 0 {
+		return "", ErrUnknownSubcommand
+	}
+
+	switch args[0] {
+	case statsSubcommand:
+		stats := e.cache.Stats()
+		return fmt.Sprintf("hits=%d misses=%d evictions=%d size=%d",
+			stats.Hits, stats.Misses, stats.Evictions, stats.Size), nil
+	case keysSubcommand:
+		return strings.Join(e.cache.Keys(), "\n"), nil
+	case flushSubcommand:
+		return "", e.flush(args[1:])
+	default:
+		return "", ErrUnknownSubcommand
+	}
+}
+
+func (e cacheExecutor) flush(args []string) error {
+	var ids []string
+
+	fs := flag.NewFlagSet(cacheCommand, flag.ContinueOnError)
+	fs.Func(orderIdParam, orderIdParamUsage, func(id string) error {
+		ids = append(ids, id)
+		return nil
+	})
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		e.cache.Clear()
+	} else {
+		e.cache.RemoveByIds(ids)
+	}
+	return nil
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"flag"
 	"homework/internal/dto"
+	"homework/internal/infrastructure/kafka"
 	"homework/internal/model"
+	"homework/pkg/ds"
 	"homework/pkg/output"
 	"slices"
 )
@@ -17,10 +19,26 @@ type (
 		ReturnOrder(ctx context.Context, id string) error
 		IssueOrders(ctx context.Context, ids []string) error
 		RefundOrder(ctx context.Context, param dto.RefundOrderParam) error
+		ExportOrders(ctx context.Context, param dto.ExportOrdersParam, write func([]dto.ExportedOrder) error) error
+		DeliverOrders(ctx context.Context, params []dto.DeliverOrderParam) ([]error, error)
+		CheckDeliverOrders(ctx context.Context, params []dto.DeliverOrderParam) ([]error, error)
+	}
+
+	kafkaAdmin interface {
+		Topics() ([]kafka.TopicConfig, error)
+	}
+
+	ordersCache interface {
+		Stats() ds.Stats
+		Keys() []string
+		Clear()
+		RemoveByIds(ids []string)
 	}
 
 	Deps struct {
 		Service orderService
+		Admin   kafkaAdmin
+		Cache   ordersCache
 	}
 
 	CLI struct {
@@ -35,30 +53,29 @@ type (
 func NewCLI(d Deps) *CLI {
 	return &CLI{
 		service:                 d.Service,
-		commandList:             newCommandList(d.Service),
+		commandList:             newCommandList(d),
 		changeNumberWorkersChan: output.NewController[int](),
 		out:                     output.NewController[string](),
 		exit:                    make(chan struct{}, 1),
 	}
 }
 
-func (c CLI) Run(ctx context.Context, args []string) {
+func (c CLI) Run(ctx context.Context, args []string) error {
 	if len(args) == 0 {
-		c.out.Push("command isn't set")
-		return
+		c.out.Push(ErrCommandIsNotSet.Error())
+		return ErrCommandIsNotSet
 	}
 
 	commandName := args[0]
 	switch commandName {
 	case help:
 		c.help()
-		return
+		return nil
 	case workers:
-		c.changeNumberWorkers(args[1:])
-		return
+		return c.push("", c.changeNumberWorkers(args[1:]))
 	case exit:
 		close(c.exit)
-		return
+		return nil
 	default:
 		handlerIndex := slices.IndexFunc(c.commandList, func(h command) bool {
 			return h.name == commandName
@@ -66,15 +83,22 @@ func (c CLI) Run(ctx context.Context, args []string) {
 		if handlerIndex == -1 {
 			break
 		}
-		out := c.commandList[handlerIndex].handler(ctx, args[1:])
-		if out != "" {
-			c.out.Push(out)
-		}
-		return
+		return c.push(c.commandList[handlerIndex].handler(ctx, args[1:]))
 	}
 
-	c.out.Push("command isn't set")
-	return
+	c.out.Push(ErrCommandIsNotSet.Error())
+	return ErrCommandIsNotSet
+}
+
+func (c CLI) push(out string, err error) error {
+	if err != nil {
+		c.out.Push(err.Error())
+		return err
+	}
+	if out != "" {
+		c.out.Push(out)
+	}
+	return nil
 }
 
 func (c CLI) GetChangeNumberWorkers() <-chan int {
@@ -85,20 +109,20 @@ func (c CLI) GetOutput() <-chan string {
 	return c.out.Subscribe()
 }
 
-func (c CLI) changeNumberWorkers(args []string) string {
+func (c CLI) changeNumberWorkers(args []string) error {
 	var n int
 
 	fs := flag.NewFlagSet(workers, flag.ContinueOnError)
 	fs.IntVar(&n, "n", -1, workersUsage)
 	if err := fs.Parse(args); err != nil {
-		return err.Error()
+		return err
 	}
 	if n <= 0 {
-		return "N isn`t set"
+		return ErrNIsNotSet
 	}
 
 	c.changeNumberWorkersChan.Push(n)
-	return ""
+	return nil
 }
 
 func (c CLI) help() {
@@ -2,7 +2,18 @@ package cli
 
 import (
 	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"homework/internal/cache"
+	"homework/internal/dto"
+	"homework/internal/infrastructure/kafka"
+	"homework/internal/model"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestCli_RunExit(t *testing.T) {
@@ -16,3 +27,108 @@ func TestCli_RunExit(t *testing.T) {
 
 	<-cli.Exit()
 }
+
+type fakeKafkaAdmin struct {
+	topics []kafka.TopicConfig
+}
+
+func (a fakeKafkaAdmin) Topics() ([]kafka.TopicConfig, error) {
+	return a.topics, nil
+}
+
+func TestCli_RunKafkaTopics(t *testing.T) {
+	t.Parallel()
+
+	mocks := newMocks(t)
+	topic := kafka.TopicConfig{Name: "call", Partitions: 3, ReplicationFactor: 3, Retention: time.Hour}
+	cli := NewCLI(Deps{Service: mocks.mockOrderService, Admin: fakeKafkaAdmin{topics: []kafka.TopicConfig{topic}}})
+	out := cli.GetOutput()
+	ctx := context.Background()
+
+	require.NoError(t, cli.Run(ctx, []string{kafkaCommand, topicsSubcommand}))
+	require.ErrorIs(t, cli.Run(ctx, []string{kafkaCommand}), ErrUnknownSubcommand)
+
+	require.ElementsMatch(t, []string{topic.String(), ErrUnknownSubcommand.Error()}, []string{<-out, <-out})
+}
+
+func TestCli_RunWithoutKafkaAdmin(t *testing.T) {
+	t.Parallel()
+
+	mocks := newMocks(t)
+	cli := NewCLI(Deps{Service: mocks.mockOrderService})
+	out := cli.GetOutput()
+
+	err := cli.Run(context.Background(), []string{kafkaCommand, topicsSubcommand})
+
+	require.ErrorIs(t, err, ErrCommandIsNotSet)
+	require.Equal(t, ErrCommandIsNotSet.Error(), <-out)
+}
+
+func TestCli_RunCache(t *testing.T) {
+	t.Parallel()
+
+	mocks := newMocks(t)
+	ordersCache := cache.NewOrdersCache(10, time.Hour)
+	ordersCache.Put("key1", []model.Order{{ID: "1"}})
+	ordersCache.Put("key2", []model.Order{{ID: "2"}})
+	cli := NewCLI(Deps{Service: mocks.mockOrderService, Cache: ordersCache})
+	ctx := context.Background()
+
+	require.NoError(t, cli.Run(ctx, []string{cacheCommand, flushSubcommand, "--id=1"}))
+	require.Equal(t, []string{"key2"}, ordersCache.Keys())
+
+	require.NoError(t, cli.Run(ctx, []string{cacheCommand, flushSubcommand}))
+	require.Empty(t, ordersCache.Keys())
+
+	require.ErrorIs(t, cli.Run(ctx, []string{cacheCommand}), ErrUnknownSubcommand)
+}
+
+func TestCli_RunExport(t *testing.T) {
+	t.Parallel()
+
+	mocks := newMocks(t)
+	cli := NewCLI(Deps{Service: mocks.mockOrderService})
+	ctx := context.Background()
+	file := filepath.Join(t.TempDir(), "orders.ndjson")
+	param := dto.ExportOrdersParam{Status: model.StatusIssued, From: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)}
+
+	mocks.mockOrderService.EXPECT().ExportOrders(gomock.Any(), param, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ dto.ExportOrdersParam, write func([]dto.ExportedOrder) error) error {
+			return write([]dto.ExportedOrder{{Order: model.Order{ID: "1"}}, {Order: model.Order{ID: "2"}}})
+		}).Times(1)
+	require.NoError(t, cli.Run(ctx, []string{exportCommand, "--file=" + file, "--format=ndjson", "--status=issued", "--from=2024-06-01"}))
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	require.Len(t, strings.Split(strings.TrimSpace(string(content)), "\n"), 2)
+
+	// the cut export is removed
+	mocks.mockOrderService.EXPECT().ExportOrders(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ dto.ExportOrdersParam, write func([]dto.ExportedOrder) error) error {
+			require.NoError(t, write([]dto.ExportedOrder{{Order: model.Order{ID: "1"}}}))
+			return errors.New("connection is lost")
+		}).Times(1)
+	require.Error(t, cli.Run(ctx, []string{exportCommand, "--file=" + file}))
+	_, err = os.Stat(file)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestCli_RunImport(t *testing.T) {
+	t.Parallel()
+
+	mocks := newMocks(t)
+	cli := NewCLI(Deps{Service: mocks.mockOrderService})
+	out := cli.GetOutput()
+	ctx := context.Background()
+	file := filepath.Join(t.TempDir(), "manifest.csv")
+	exp := time.Now().Add(time.Hour).Format(model.TimeFormat)
+	manifest := "id,user,exp,weight_in_kg,price_in_rub\n1,1," + exp + ",1,10\n2,," + exp + ",1,10\n"
+	require.NoError(t, os.WriteFile(file, []byte(manifest), 0o600))
+
+	mocks.mockOrderService.EXPECT().CheckDeliverOrders(gomock.Any(), gomock.Len(1)).Return([]error{nil}, nil).Times(1)
+	require.NoError(t, cli.Run(ctx, []string{importCommand, "--file=" + file, dryRunParamUsage}))
+	require.Equal(t, "row 1: id=1: ok\nrow 2: id=2: user is empty\naccepted 1, failed 1 (dry run)", <-out)
+
+	mocks.mockOrderService.EXPECT().DeliverOrders(gomock.Any(), gomock.Len(1)).Return([]error{errors.New("duplicate")}, nil).Times(1)
+	require.NoError(t, cli.Run(ctx, []string{importCommand, "--file=" + file}))
+	require.Equal(t, "row 1: id=1: duplicate\nrow 2: id=2: user is empty\naccepted 0, failed 2", <-out)
+}
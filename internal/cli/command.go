@@ -8,19 +8,23 @@ import (
 const (
 	help = "help"
 
-	deliverOrder = "deliver"
-	returnOrder  = "return"
-	issueOrders  = "issue"
-	listOrders   = "list"
-	refundOrder  = "refund"
-	listRefunded = "refunded"
-	workers      = "workers"
+	deliverOrder  = "deliver"
+	returnOrder   = "return"
+	issueOrders   = "issue"
+	listOrders    = "list"
+	refundOrder   = "refund"
+	listRefunded  = "refunded"
+	workers       = "workers"
+	kafkaCommand  = "kafka"
+	cacheCommand  = "cache"
+	exportCommand = "export"
+	importCommand = "import"
 
 	exit = "exit"
 )
 
 type (
-	commandHandler func(context.Context, []string) string
+	commandHandler func(context.Context, []string) (string, error)
 
 	command struct {
 		name        string
@@ -34,10 +38,10 @@ func (c command) String() string {
 	return fmt.Sprintf("%s\n   %s\n   %s", c.name, c.description, c.usage)
 }
 
-func newCommandList(service orderService) []command {
-	handlers := newHandlers(service)
+func newCommandList(d Deps) []command {
+	handlers := newHandlers(d)
 
-	return []command{
+	commands := []command{
 		{
 			name:        help,
 			usage:       help,
@@ -79,6 +83,18 @@ func newCommandList(service orderService) []command {
 			description: deliverOrderDescription,
 			handler:     handlers.mustFind(deliverOrder).handle,
 		},
+		{
+			name:        exportCommand,
+			usage:       exportUsage,
+			description: exportDescription,
+			handler:     handlers.mustFind(exportCommand).handle,
+		},
+		{
+			name:        importCommand,
+			usage:       importUsage,
+			description: importDescription,
+			handler:     handlers.mustFind(importCommand).handle,
+		},
 		{
 			name:        workers,
 			usage:       workersUsage,
@@ -90,4 +106,22 @@ func newCommandList(service orderService) []command {
 			description: exitDescription,
 		},
 	}
+
+	if d.Admin != nil {
+		commands = append(commands, command{
+			name:        kafkaCommand,
+			usage:       kafkaUsage,
+			description: kafkaDescription,
+			handler:     handlers.mustFind(kafkaCommand).handle,
+		})
+	}
+	if d.Cache != nil {
+		commands = append(commands, command{
+			name:        cacheCommand,
+			usage:       cacheUsage,
+			description: cacheDescription,
+			handler:     handlers.mustFind(cacheCommand).handle,
+		})
+	}
+	return commands
 }
@@ -2,15 +2,21 @@ package cli
 
 import (
 	"errors"
+	"homework/internal/manifest"
 )
 
 var (
-	ErrIdIsEmpty            = errors.New("id is empty")
-	ErrUserIsEmpty          = errors.New("user is empty")
-	ErrExpIsEmpty           = errors.New("exp is empty")
+	ErrIdIsEmpty            = manifest.ErrIdIsEmpty
+	ErrUserIsEmpty          = manifest.ErrUserIsEmpty
+	ErrExpIsEmpty           = manifest.ErrExpIsEmpty
 	ErrPageIsNotValid       = errors.New("page is not valid")
 	ErrSizeIsNotValid       = errors.New("size is not valid")
-	ErrWrapperIsNotValid    = errors.New("wrapper is not valid")
-	ErrWeightInKgInNotValid = errors.New("weight_in_kg is not valid")
-	ErrPriceInRubIsNotValid = errors.New("price_in_rub is not valid")
+	ErrWrapperIsNotValid    = manifest.ErrWrapperIsNotValid
+	ErrWeightInKgInNotValid = manifest.ErrWeightInKgInNotValid
+	ErrPriceInRubIsNotValid = manifest.ErrPriceInRubIsNotValid
+	ErrCommandIsNotSet      = errors.New("command isn't set")
+	ErrNIsNotSet            = errors.New("N isn`t set")
+	ErrUnknownSubcommand    = errors.New("unknown subcommand")
+	ErrFileIsEmpty          = errors.New("file is empty")
+	ErrStatusIsNotValid     = errors.New("status is not valid")
 )
@@ -3,14 +3,11 @@ package cli
 import (
 	"context"
 	"flag"
-	"github.com/shopspring/decimal"
 	"homework/internal/dto"
+	"homework/internal/manifest"
 	"homework/internal/model"
-	"homework/internal/model/wrapper"
 	"math"
-	"slices"
 	"strings"
-	"time"
 )
 
 type executor struct {
@@ -21,17 +18,13 @@ func newExecutor(service orderService) executor {
 	return executor{service: service}
 }
 
-func (e executor) refundOrder(ctx context.Context, args []string) string {
+func (e executor) refundOrder(ctx context.Context, args []string) (string, error) {
 	param, err := e.parseRefundOrder(args)
 	if err != nil {
-		return err.Error()
+		return "", err
 	}
 
-	err = e.service.RefundOrder(ctx, param)
-	if err == nil {
-		return ""
-	}
-	return err.Error()
+	return "", e.service.RefundOrder(ctx, param)
 }
 
 func (e executor) parseRefundOrder(args []string) (dto.RefundOrderParam, error) {
@@ -54,25 +47,17 @@ func (e executor) parseRefundOrder(args []string) (dto.RefundOrderParam, error)
 	return param, nil
 }
 
-func (e executor) issueOrders(ctx context.Context, args []string) string {
-	err := e.service.IssueOrders(ctx, args)
-	if err == nil {
-		return ""
-	}
-	return err.Error()
+func (e executor) issueOrders(ctx context.Context, args []string) (string, error) {
+	return "", e.service.IssueOrders(ctx, args)
 }
 
-func (e executor) returnOrder(ctx context.Context, args []string) string {
+func (e executor) returnOrder(ctx context.Context, args []string) (string, error) {
 	id, err := e.parseReturnOrder(args)
 	if err != nil {
-		return err.Error()
+		return "", err
 	}
 
-	err = e.service.ReturnOrder(ctx, id)
-	if err == nil {
-		return ""
-	}
-	return err.Error()
+	return "", e.service.ReturnOrder(ctx, id)
 }
 
 func (e executor) parseReturnOrder(args []string) (string, error) {
@@ -89,93 +74,45 @@ func (e executor) parseReturnOrder(args []string) (string, error) {
 	return ID, err
 }
 
-func (e executor) deliverOrder(ctx context.Context, args []string) string {
+func (e executor) deliverOrder(ctx context.Context, args []string) (string, error) {
 	param, err := e.parseDeliverOrder(args)
 	if err != nil {
-		return err.Error()
+		return "", err
 	}
 
-	err = e.service.Deliver(ctx, param)
-	if err == nil {
-		return ""
-	}
-	return err.Error()
+	return "", e.service.Deliver(ctx, param)
 }
 
+// parseDeliverOrder checks the order like the rows of the manifests imported by the import command.
 func (e executor) parseDeliverOrder(args []string) (dto.DeliverOrderParam, error) {
-	var (
-		ID, userID        string
-		expString         string
-		wrapperType       string
-		weightInKg        float64
-		priceInRubFloat64 float64
-	)
+	var fields manifest.Fields
 
 	fs := flag.NewFlagSet(deliverOrder, flag.ContinueOnError)
-	fs.StringVar(&expString, expParam, "", expParamUsage)
-	fs.StringVar(&userID, userIdParam, "", userIdParamUsage)
-	fs.StringVar(&ID, orderIdParam, "", orderIdParamUsage)
-	fs.StringVar(&wrapperType, wrapperParam, "", wrapperParamUsage)
-	fs.Float64Var(&weightInKg, weightInKgParam, 0, weightInKgUsage)
-	fs.Float64Var(&priceInRubFloat64, priceInRubParam, 0, priceInRubParamUsage)
+	fs.StringVar(&fields.Exp, expParam, "", expParamUsage)
+	fs.StringVar(&fields.User, userIdParam, "", userIdParamUsage)
+	fs.StringVar(&fields.ID, orderIdParam, "", orderIdParamUsage)
+	fs.StringVar(&fields.Wrapper, wrapperParam, "", wrapperParamUsage)
+	fs.Float64Var(&fields.WeightInKg, weightInKgParam, 0, weightInKgUsage)
+	fs.Float64Var(&fields.PriceInRub, priceInRubParam, 0, priceInRubParamUsage)
 	if err := fs.Parse(args); err != nil {
 		return dto.DeliverOrderParam{}, err
 	}
 
-	if expString == "" {
-		return dto.DeliverOrderParam{}, ErrExpIsEmpty
-	}
-	if ID == "" {
-		return dto.DeliverOrderParam{}, ErrIdIsEmpty
-	}
-	if userID == "" {
-		return dto.DeliverOrderParam{}, ErrUserIsEmpty
-	}
-	if weightInKg <= 0 {
-		return dto.DeliverOrderParam{}, ErrWeightInKgInNotValid
-	}
-	if priceInRubFloat64 < 0 {
-		return dto.DeliverOrderParam{}, ErrPriceInRubIsNotValid
-	}
-
-	priceInRub := wrapper.PriceInRub(decimal.NewFromFloat(priceInRubFloat64))
-	wrapperIsEmpty := wrapperType == ""
-	if !wrapperIsEmpty && !slices.Contains(wrapper.GetAllWrapperTypes(), wrapper.WrapperType(wrapperType)) {
-		return dto.DeliverOrderParam{}, ErrWrapperIsNotValid
-	}
-
-	exp, err := time.Parse(model.TimeFormat, expString)
-	if err != nil {
-		return dto.DeliverOrderParam{}, err
-	}
-
-	wrapper, err := wrapper.NewDefaultWrapper(wrapper.WrapperType(wrapperType))
-	if !wrapperIsEmpty && err != nil {
-		return dto.DeliverOrderParam{}, err
-	}
-
-	return dto.DeliverOrderParam{
-		ID:             ID,
-		RecipientID:    userID,
-		ExpirationDate: exp,
-		WeightInGram:   weightInKg * 1000,
-		Wrapper:        wrapper,
-		PriceInRub:     priceInRub,
-	}, nil
+	return fields.Param()
 }
 
-func (e executor) listOrders(ctx context.Context, args []string) string {
+func (e executor) listOrders(ctx context.Context, args []string) (string, error) {
 	param, err := e.parseListOrders(args)
 	if err != nil {
-		return err.Error()
+		return "", err
 	}
 
 	list, err := e.service.ListUserOrders(ctx, param)
 	if err != nil {
-		return err.Error()
+		return "", err
 	}
 
-	return e.stringOrders(list)
+	return e.stringOrders(list), nil
 }
 
 func (e executor) parseListOrders(args []string) (dto.ListUserOrdersParam, error) {
@@ -199,17 +136,17 @@ func (e executor) parseListOrders(args []string) (dto.ListUserOrdersParam, error
 	return param, nil
 }
 
-func (e executor) listRefunded(ctx context.Context, args []string) string {
+func (e executor) listRefunded(ctx context.Context, args []string) (string, error) {
 	param, err := e.parseListRefunded(args)
 	if err != nil {
-		return err.Error()
+		return "", err
 	}
 
 	list, err := e.service.RefundedOrders(ctx, param)
 	if err != nil {
-		return err.Error()
+		return "", err
 	}
-	return e.stringOrders(list)
+	return e.stringOrders(list), nil
 }
 
 func (e executor) parseListRefunded(args []string) (dto.PageParam, error) {
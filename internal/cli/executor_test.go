@@ -3,6 +3,8 @@ package cli
 import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
+	"homework/internal/export"
+	"homework/internal/manifest"
 	mock_service "homework/internal/service/mocks"
 	"testing"
 )
@@ -248,3 +250,105 @@ func TestExecutor_parseListRefunded(t *testing.T) {
 		})
 	}
 }
+
+func TestExecutor_parseExportOrders(t *testing.T) {
+	t.Parallel()
+
+	type test struct {
+		name  string
+		input []string
+		err   error
+	}
+
+	tests := []test{
+		{
+			name:  "ok",
+			input: []string{fileParamUsage, "--format=ndjson", "--status=issued", userIdParamUsage, fromParamUsage, toParamUsage},
+		},
+		{
+			name:  "ok without filters",
+			input: []string{fileParamUsage},
+		},
+		{
+			name:  ErrFileIsEmpty.Error(),
+			input: []string{"--format=csv"},
+			err:   ErrFileIsEmpty,
+		},
+		{
+			name:  export.ErrUnknownFormat.Error(),
+			input: []string{fileParamUsage, "--format=xml"},
+			err:   export.ErrUnknownFormat,
+		},
+		{
+			name:  ErrStatusIsNotValid.Error(),
+			input: []string{fileParamUsage, "--status=returned"},
+			err:   ErrStatusIsNotValid,
+		},
+		{
+			name:  export.ErrTimeIsNotValid.Error(),
+			input: []string{fileParamUsage, "--from=01.06.2024"},
+			err:   export.ErrTimeIsNotValid,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mocks := newMocks(t)
+
+			orderService := newExecutor(mocks.mockOrderService)
+
+			_, err := orderService.parseExportOrders(tt.input)
+
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestExecutor_parseImportOrders(t *testing.T) {
+	t.Parallel()
+
+	type test struct {
+		name  string
+		input []string
+		err   error
+	}
+
+	tests := []test{
+		{
+			name:  "ok",
+			input: []string{manifestFileParamUsage, "--format=json", dryRunParamUsage},
+		},
+		{
+			name:  "ok with csv by default",
+			input: []string{manifestFileParamUsage},
+		},
+		{
+			name:  ErrFileIsEmpty.Error(),
+			input: []string{dryRunParamUsage},
+			err:   ErrFileIsEmpty,
+		},
+		{
+			name:  manifest.ErrUnknownFormat.Error(),
+			input: []string{manifestFileParamUsage, "--format=ndjson"},
+			err:   manifest.ErrUnknownFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mocks := newMocks(t)
+
+			orderService := newExecutor(mocks.mockOrderService)
+
+			_, err := orderService.parseImportOrders(tt.input)
+
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
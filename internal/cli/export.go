@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"homework/internal/dto"
+	"homework/internal/export"
+	"homework/internal/model"
+	"os"
+	"slices"
+)
+
+type exportParam struct {
+	file   string
+	format export.Format
+	dto.ExportOrdersParam
+}
+
+// exportOrders removes the file if the export fails, so a cut export isn't taken for a whole one.
+func (e executor) exportOrders(ctx context.Context, args []string) (string, error) {
+	param, err := e.parseExportOrders(args)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.Create(param.file)
+	if err != nil {
+		return "", err
+	}
+
+	buffered := bufio.NewWriter(file)
+	exported := 0
+	writer, err := export.NewWriter(buffered, param.format)
+	if err == nil {
+		err = e.service.ExportOrders(ctx, param.ExportOrdersParam, func(orders []dto.ExportedOrder) error {
+			exported += len(orders)
+			return writer.Write(orders)
+		})
+	}
+	if err == nil {
+		err = buffered.Flush()
+	}
+	if err = errors.Join(err, file.Close()); err != nil {
+		return "", errors.Join(err, os.Remove(param.file))
+	}
+
+	return fmt.Sprintf("%d orders are exported to %s", exported, param.file), nil
+}
+
+func (e executor) parseExportOrders(args []string) (exportParam, error) {
+	var (
+		param                    exportParam
+		format, status, from, to string
+	)
+
+	fs := flag.NewFlagSet(exportCommand, flag.ContinueOnError)
+	fs.StringVar(&param.file, fileParam, "", fileParamUsage)
+	fs.StringVar(&format, formatParam, string(export.CSV), formatParamUsage)
+	fs.StringVar(&status, statusParam, "", statusParamUsage)
+	fs.StringVar(&param.RecipientID, userIdParam, "", userIdParamUsage)
+	fs.StringVar(&from, fromParam, "", fromParamUsage)
+	fs.StringVar(&to, toParam, "", toParamUsage)
+	if err := fs.Parse(args); err != nil {
+		return exportParam{}, err
+	}
+
+	if param.file == "" {
+		return exportParam{}, ErrFileIsEmpty
+	}
+
+	var err error
+	if param.format, err = export.ParseFormat(format); err != nil {
+		return exportParam{}, err
+	}
+
+	param.Status = model.Status(status)
+	if !slices.Contains([]model.Status{model.StatusNone, model.StatusDelivered, model.StatusIssued, model.StatusRefunded}, param.Status) {
+		return exportParam{}, ErrStatusIsNotValid
+	}
+
+	if param.From, err = export.ParseTime(from); err != nil {
+		return exportParam{}, err
+	}
+	if param.To, err = export.ParseTime(to); err != nil {
+		return exportParam{}, err
+	}
+
+	return param, nil
+}
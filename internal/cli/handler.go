@@ -14,17 +14,27 @@ func newHandler(name string, handle commandHandler) handler {
 	return handler{name: name, handle: handle}
 }
 
-func newHandlers(service orderService) handlers {
-	executor := newExecutor(service)
+func newHandlers(d Deps) handlers {
+	executor := newExecutor(d.Service)
 
-	return []handler{
+	handlers := []handler{
 		newHandler(refundOrder, executor.refundOrder),
 		newHandler(issueOrders, executor.issueOrders),
 		newHandler(returnOrder, executor.returnOrder),
 		newHandler(deliverOrder, executor.deliverOrder),
 		newHandler(listOrders, executor.listOrders),
 		newHandler(listRefunded, executor.listRefunded),
+		newHandler(exportCommand, executor.exportOrders),
+		newHandler(importCommand, executor.importOrders),
 	}
+
+	if d.Admin != nil {
+		handlers = append(handlers, newHandler(kafkaCommand, newKafkaExecutor(d.Admin).topics))
+	}
+	if d.Cache != nil {
+		handlers = append(handlers, newHandler(cacheCommand, newCacheExecutor(d.Cache).handle))
+	}
+	return handlers
 }
 
 func (h handlers) mustFind(name string) handler {
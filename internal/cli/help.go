@@ -2,6 +2,8 @@ package cli
 
 import (
 	"fmt"
+	"homework/internal/export"
+	"homework/internal/manifest"
 	"homework/internal/model"
 	"homework/internal/model/wrapper"
 	"time"
@@ -16,6 +18,12 @@ var (
 	refundOrderUsage  = fmt.Sprintf("%s %s %s", refundOrder, orderIdParamUsage, userIdParamUsage)
 	listRefundedUsage = fmt.Sprintf("%s %s %s", listRefunded, sizeParamUsage, pageParamUsage)
 	workersUsage      = fmt.Sprintf("%s %s", workers, nParamUsage)
+	kafkaUsage        = fmt.Sprintf("%s %s", kafkaCommand, topicsSubcommand)
+	cacheUsage        = fmt.Sprintf("%s <%s|%s|%s [%s ...]>", cacheCommand, statsSubcommand, keysSubcommand,
+		flushSubcommand, orderIdParamUsage)
+	exportUsage = fmt.Sprintf("%s %s %s %s %s %s %s", exportCommand, fileParamUsage, formatParamUsage,
+		statusParamUsage, userIdParamUsage, fromParamUsage, toParamUsage)
+	importUsage = fmt.Sprintf("%s %s %s %s", importCommand, manifestFileParamUsage, manifestFormatParamUsage, dryRunParamUsage)
 
 	priceInRubParamUsage = fmt.Sprintf("--%s=10.3", priceInRubParam)
 	wrapperParamUsage    = fmt.Sprintf("--%s=<%s>", wrapperParam, wrapper.GetAllWrapperTypes())
@@ -27,6 +35,15 @@ var (
 	nParamUsage          = fmt.Sprintf("--%s=10", nParam)
 	weightInKgUsage      = fmt.Sprintf("--%s=10.3", weightInKgParam)
 	ordersIdsParamUsage  = "<id заказа 1> ... <id заказа N>"
+	fileParamUsage       = fmt.Sprintf("--%s=orders.csv", fileParam)
+	formatParamUsage     = fmt.Sprintf("--%s=<%s|%s>", formatParam, export.CSV, export.NDJSON)
+	statusParamUsage     = fmt.Sprintf("--%s=<%s|%s|%s>", statusParam, model.StatusDelivered, model.StatusIssued, model.StatusRefunded)
+	fromParamUsage       = fmt.Sprintf("--%s=2024-06-01", fromParam)
+	toParamUsage         = fmt.Sprintf("--%s=2024-07-01", toParam)
+
+	manifestFileParamUsage   = fmt.Sprintf("--%s=manifest.csv", fileParam)
+	manifestFormatParamUsage = fmt.Sprintf("--%s=<%s|%s>", formatParam, manifest.CSV, manifest.JSON)
+	dryRunParamUsage         = fmt.Sprintf("--%s", dryRunParam)
 )
 
 const (
@@ -39,6 +56,17 @@ const (
 	userIdParam     = "user"
 	expParam        = "exp"
 	orderIdParam    = "id"
+	fileParam       = "file"
+	formatParam     = "format"
+	statusParam     = "status"
+	fromParam       = "from"
+	toParam         = "to"
+	dryRunParam     = "dry_run"
+
+	topicsSubcommand = "topics"
+	statsSubcommand  = "stats"
+	keysSubcommand   = "keys"
+	flushSubcommand  = "flush"
 
 	helpDescription = "Cправка"
 
@@ -56,5 +84,13 @@ const (
 
 	workersDescription = "Изменить максимальное количество горутин"
 
+	kafkaDescription = `Показать топики kafka: количество партиций, фактор репликации и время хранения.`
+
+	cacheDescription = `Кэш заказов: stats - попадания, промахи, вытеснения и размер; keys - закэшированные ключи; flush - очистить весь кэш или только значения с заказами --id.`
+
+	exportDescription = `Выгрузить заказы в файл в формате csv или ndjson. Фильтры необязательны: статус, получатель и период приёмки от --from включительно до --to, даты в UTC или RFC3339.`
+
+	importDescription = `Принять заказы от курьера по манифесту в формате csv (заголовок с колонками id, user, exp, wrapper, weight_in_kg, price_in_rub) или json (массив объектов с теми же полями). Строки проверяются как в deliver, по каждой выводится результат. С --dry_run заказы только проверяются и не принимаются.`
+
 	exitDescription = `Завершить выполнение`
 )
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"homework/internal/manifest"
+	"os"
+	"strings"
+)
+
+type importParam struct {
+	file   string
+	format manifest.Format
+	dryRun bool
+}
+
+// importOrders reports every row of the manifest, a wrong row doesn't stop the others.
+func (e executor) importOrders(ctx context.Context, args []string) (string, error) {
+	param, err := e.parseImportOrders(args)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.Open(param.file)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	rows, err := manifest.Read(file, param.format)
+	if err != nil {
+		return "", err
+	}
+	report := manifest.Import(ctx, e.service, rows, param.dryRun, manifest.DefaultChunkSize)
+
+	var out strings.Builder
+	for _, row := range report.Rows {
+		result := "ok"
+		if row.Err != nil {
+			result = row.Err.Error()
+		}
+		fmt.Fprintf(&out, "row %d: id=%s: %s\n", row.Number, row.ID, result)
+	}
+	fmt.Fprintf(&out, "accepted %d, failed %d", report.Accepted, report.Failed)
+	if report.DryRun {
+		out.WriteString(" (dry run)")
+	}
+	return out.String(), nil
+}
+
+func (e executor) parseImportOrders(args []string) (importParam, error) {
+	var (
+		param  importParam
+		format string
+	)
+
+	fs := flag.NewFlagSet(importCommand, flag.ContinueOnError)
+	fs.StringVar(&param.file, fileParam, "", manifestFileParamUsage)
+	fs.StringVar(&format, formatParam, string(manifest.CSV), manifestFormatParamUsage)
+	fs.BoolVar(&param.dryRun, dryRunParam, false, dryRunParamUsage)
+	if err := fs.Parse(args); err != nil {
+		return importParam{}, err
+	}
+
+	if param.file == "" {
+		return importParam{}, ErrFileIsEmpty
+	}
+
+	var err error
+	if param.format, err = manifest.ParseFormat(format); err != nil {
+		return importParam{}, err
+	}
+	return param, nil
+}
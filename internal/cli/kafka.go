@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"context"
+	"strings"
+)
+
+type kafkaExecutor struct {
+	admin kafkaAdmin
+}
+
+func newKafkaExecutor(admin kafkaAdmin) kafkaExecutor {
+	return kafkaExecutor{admin: admin}
+}
+
+func (e kafkaExecutor) topics(_ context.Context, args []string) (string, error) {
+	if len(args) != 1 || args[0] != topicsSubcommand {
+		return "", ErrUnknownSubcommand
+	}
+
+	topics, err := e.admin.Topics()
+	if err != nil {
+		return "", err
+	}
+
+	lines := make([]string, 0, len(topics))
+	for _, topic := range topics {
+		lines = append(lines, topic.String())
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -6,10 +6,24 @@ import (
 	"time"
 )
 
+// OnCallCodeOK and OnCallCodeUnknown mirror the gRPC code names so that
+// CLI and gRPC calls can be filtered the same way in the audit stream.
+const (
+	OnCallCodeOK      = "OK"
+	OnCallCodeUnknown = "Unknown"
+	OnCallPeerCLI     = "cli"
+)
+
 type OnCallMessage struct {
 	Args     string
 	Method   string
 	CalledAt time.Time
+	Code     string
+	Error    string
+	Duration time.Duration
+	Peer     string
+	// Client is the authenticated caller, empty for the CLI and public methods.
+	Client string
 }
 
 func (c *OnCallMessage) Marshal() ([]byte, error) {
@@ -21,5 +35,6 @@ func (c *OnCallMessage) Unmarshal(bytes []byte) error {
 }
 
 func (c *OnCallMessage) String() string {
-	return fmt.Sprintf("Call(args=%s, method=%s, created_at=%s)", c.Args, c.Method, c.CalledAt)
+	return fmt.Sprintf("Call(args=%s, method=%s, created_at=%s, code=%s, error=%s, duration=%s, peer=%s, client=%s)",
+		c.Args, c.Method, c.CalledAt, c.Code, c.Error, c.Duration, c.Peer, c.Client)
 }
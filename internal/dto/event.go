@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"encoding/json"
+	"homework/internal/model"
+	"time"
+)
+
+// OrderEvent is a change of the order status.
+// ID is given by the bus of the instance the event is watched on, it isn't shared between instances.
+type OrderEvent struct {
+	ID          string `json:"-"`
+	OrderID     string
+	RecipientID string
+	Status      model.Status
+	PickupPoint string
+	// Source is the instance the order has been changed on.
+	Source     string
+	OccurredAt time.Time
+}
+
+func (e *OrderEvent) Marshal() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *OrderEvent) Unmarshal(bytes []byte) error {
+	return json.Unmarshal(bytes, e)
+}
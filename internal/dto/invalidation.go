@@ -0,0 +1,19 @@
+package dto
+
+import "encoding/json"
+
+// InvalidationMessage tells every instance to drop cached lists with the given orders
+// and lists whose filter the changed orders match.
+// It's published after the commit, so every result read before the instance receives it is stale.
+type InvalidationMessage struct {
+	Ids     []string
+	Filters []OrderFilter
+}
+
+func (m *InvalidationMessage) Marshal() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+func (m *InvalidationMessage) Unmarshal(bytes []byte) error {
+	return json.Unmarshal(bytes, m)
+}
@@ -42,12 +42,31 @@ type (
 	}
 
 	GetParam struct {
-		Ids         []string
+		Ids         []string     `json:"ids,omitempty"`
+		Status      model.Status `json:"status,omitempty"`
+		Order       string       `json:"order,omitempty"`
+		Limit       uint         `json:"limit,omitempty"`
+		RecipientId string       `json:"recipient_id,omitempty"`
+		Offset      uint         `json:"offset,omitempty"`
+	}
+
+	// ExportOrdersParam filters the exported orders, empty fields match any value.
+	// From and To bound the time the order has been delivered at, To is exclusive.
+	ExportOrdersParam struct {
 		Status      model.Status
-		Order       string
-		Limit       uint
+		RecipientID string
+		From, To    time.Time
+	}
+
+	ExportedOrder struct {
+		model.Order
+		DeliveredAt time.Time
+	}
+
+	// OrderFilter is the part of GetParam a changed order can match, empty fields match any value.
+	OrderFilter struct {
 		RecipientId string
-		Offset      uint
+		Status      model.Status
 	}
 )
 
@@ -55,6 +74,20 @@ func (p ListOrdersParam) String() string {
 	return fmt.Sprintf("[ListOrdersParam]: page=%v userID=%v size=%v status=%v ", p.Page, p.UserId, p.Size, string(p.Status))
 }
 
+func (p GetParam) Filter() OrderFilter {
+	return OrderFilter{RecipientId: p.RecipientId, Status: p.Status}
+}
+
+// Matching returns every filter of a list the order with the filter's recipient and status can appear in.
+func (f OrderFilter) Matching() []OrderFilter {
+	return []OrderFilter{
+		f,
+		{RecipientId: f.RecipientId},
+		{Status: f.Status},
+		{},
+	}
+}
+
 func (p GetParam) String() string {
 	return fmt.Sprintf("[GetParam]: ids=%v status=%v order=%v limit=%v RecipientId=%v Offset=%v",
 		strings.Join(p.Ids, ", "), string(p.Status), p.Order, p.Limit, p.RecipientId, p.Offset)
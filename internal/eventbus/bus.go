@@ -0,0 +1,208 @@
+// Package eventbus delivers the order events of the instance to its watchers.
+//
+// Every published event gets the id <epoch>-<seq>, where epoch is the start of the bus
+// and seq grows by one. The last events are kept in a ring, so a watcher that has lost
+// the stream resumes after the last id it has received, as long as the event is still
+// in the ring and the bus hasn't been restarted.
+package eventbus
+
+import (
+	"fmt"
+	"homework/internal/dto"
+	"homework/internal/model"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultHistory = 10000
+	defaultBuffer  = 256
+)
+
+type (
+	// Filter matches the events, empty fields match any value.
+	Filter struct {
+		RecipientID string
+		Status      model.Status
+		PickupPoint string
+	}
+
+	Bus struct {
+		epoch  string
+		buffer int
+
+		lock    sync.Mutex
+		seq     uint64
+		history []dto.OrderEvent
+		// subscriptions are closed by the bus when they fall behind or the bus is closed.
+		subscriptions map[*Subscription]struct{}
+		closed        bool
+	}
+
+	Subscription struct {
+		bus    *Bus
+		filter Filter
+		events chan dto.OrderEvent
+		err    error
+	}
+)
+
+// NewBus keeps history last events, every subscription buffers buffer events.
+func NewBus(history, buffer int) *Bus {
+	if history <= 0 {
+		history = defaultHistory
+	}
+	if buffer <= 0 {
+		buffer = defaultBuffer
+	}
+	return &Bus{
+		epoch:         strconv.FormatInt(time.Now().UnixNano(), 36),
+		buffer:        buffer,
+		history:       make([]dto.OrderEvent, history),
+		subscriptions: make(map[*Subscription]struct{}),
+	}
+}
+
+func (f Filter) Match(event dto.OrderEvent) bool {
+	return (f.RecipientID == "" || f.RecipientID == event.RecipientID) &&
+		(f.Status == model.StatusNone || f.Status == event.Status) &&
+		(f.PickupPoint == "" || f.PickupPoint == event.PickupPoint)
+}
+
+// Publish gives the events their ids and sends them to the subscriptions.
+// A subscription that hasn't received the previous events yet is closed with ErrSubscriptionIsLagging.
+func (b *Bus) Publish(events ...dto.OrderEvent) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.closed {
+		return
+	}
+	for _, event := range events {
+		b.seq++
+		event.ID = b.id(b.seq)
+		b.history[(b.seq-1)%uint64(len(b.history))] = event
+
+		for subscription := range b.subscriptions {
+			if !subscription.filter.Match(event) {
+				continue
+			}
+			select {
+			case subscription.events <- event:
+			default:
+				b.unsubscribe(subscription, ErrSubscriptionIsLagging)
+			}
+		}
+	}
+}
+
+// Subscribe sends the events matching the filter, the kept ones published after the event
+// with the id after go first. Empty after subscribes to the new events only.
+func (b *Bus) Subscribe(filter Filter, after string) (*Subscription, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.closed {
+		return nil, ErrBusIsClosed
+	}
+
+	var missed []dto.OrderEvent
+	if after != "" {
+		seq, err := b.parseID(after)
+		if err != nil {
+			return nil, err
+		}
+		missed, err = b.since(seq, filter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	subscription := &Subscription{
+		bus:    b,
+		filter: filter,
+		events: make(chan dto.OrderEvent, len(missed)+b.buffer),
+	}
+	for _, event := range missed {
+		subscription.events <- event
+	}
+	b.subscriptions[subscription] = struct{}{}
+	return subscription, nil
+}
+
+// Close ends the subscriptions with ErrBusIsClosed, so the streams of the watchers don't hold the server.
+func (b *Bus) Close() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.closed = true
+	for subscription := range b.subscriptions {
+		b.unsubscribe(subscription, ErrBusIsClosed)
+	}
+}
+
+// Events is closed when the subscription has ended, Err tells why.
+func (s *Subscription) Events() <-chan dto.OrderEvent {
+	return s.events
+}
+
+func (s *Subscription) Err() error {
+	s.bus.lock.Lock()
+	defer s.bus.lock.Unlock()
+
+	return s.err
+}
+
+func (s *Subscription) Close() {
+	s.bus.lock.Lock()
+	defer s.bus.lock.Unlock()
+
+	s.bus.unsubscribe(s, nil)
+}
+
+func (b *Bus) unsubscribe(subscription *Subscription, err error) {
+	if _, ok := b.subscriptions[subscription]; !ok {
+		return
+	}
+	delete(b.subscriptions, subscription)
+	subscription.err = err
+	close(subscription.events)
+}
+
+// since returns the events after seq, it fails if some of them are no longer kept.
+func (b *Bus) since(seq uint64, filter Filter) ([]dto.OrderEvent, error) {
+	if seq > b.seq {
+		return nil, ErrEventNotFound
+	}
+	if b.seq-seq > uint64(len(b.history)) {
+		return nil, ErrEventIsExpired
+	}
+
+	var events []dto.OrderEvent
+	for next := seq + 1; next <= b.seq; next++ {
+		event := b.history[(next-1)%uint64(len(b.history))]
+		if filter.Match(event) {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (b *Bus) id(seq uint64) string {
+	return fmt.Sprintf("%s-%d", b.epoch, seq)
+}
+
+// parseID fails the ids of the other buses, e.g. of the instance before a restart.
+func (b *Bus) parseID(id string) (uint64, error) {
+	epoch, seq, ok := strings.Cut(id, "-")
+	if !ok || epoch != b.epoch {
+		return 0, ErrEventNotFound
+	}
+	parsed, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil {
+		return 0, ErrEventNotFound
+	}
+	return parsed, nil
+}
@@ -0,0 +1,96 @@
+package eventbus
+
+import (
+	"github.com/stretchr/testify/require"
+	"homework/internal/dto"
+	"homework/internal/model"
+	"testing"
+)
+
+func receive(t *testing.T, subscription *Subscription, n int) []string {
+	var ids []string
+	for i := 0; i < n; i++ {
+		select {
+		case event := <-subscription.Events():
+			ids = append(ids, event.OrderID)
+		default:
+			require.Fail(t, "no event", "received %v of %v", i, n)
+		}
+	}
+	return ids
+}
+
+func TestBus_Filter(t *testing.T) {
+	t.Parallel()
+
+	bus := NewBus(10, 10)
+	subscription, err := bus.Subscribe(Filter{RecipientID: "1", Status: model.StatusIssued}, "")
+	require.NoError(t, err)
+
+	bus.Publish(
+		dto.OrderEvent{OrderID: "1", RecipientID: "1", Status: model.StatusIssued},
+		dto.OrderEvent{OrderID: "2", RecipientID: "2", Status: model.StatusIssued},
+		dto.OrderEvent{OrderID: "3", RecipientID: "1", Status: model.StatusRefunded},
+		dto.OrderEvent{OrderID: "4", RecipientID: "1", Status: model.StatusIssued, PickupPoint: "msk-1"},
+	)
+
+	require.Equal(t, []string{"1", "4"}, receive(t, subscription, 2))
+	require.Empty(t, subscription.Events())
+}
+
+func TestBus_Resume(t *testing.T) {
+	t.Parallel()
+
+	bus := NewBus(3, 10)
+	subscription, err := bus.Subscribe(Filter{}, "")
+	require.NoError(t, err)
+	bus.Publish(dto.OrderEvent{OrderID: "1"}, dto.OrderEvent{OrderID: "2"})
+	first := <-subscription.Events()
+	subscription.Close()
+
+	bus.Publish(dto.OrderEvent{OrderID: "3"}, dto.OrderEvent{OrderID: "4"})
+	resumed, err := bus.Subscribe(Filter{}, first.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"2", "3", "4"}, receive(t, resumed, 3))
+
+	bus.Publish(dto.OrderEvent{OrderID: "5"})
+	_, err = bus.Subscribe(Filter{}, first.ID)
+	require.ErrorIs(t, err, ErrEventIsExpired)
+
+	_, err = bus.Subscribe(Filter{}, NewBus(3, 10).id(1))
+	require.ErrorIs(t, err, ErrEventNotFound)
+	_, err = bus.Subscribe(Filter{}, bus.id(6))
+	require.ErrorIs(t, err, ErrEventNotFound)
+}
+
+func TestBus_Lagging(t *testing.T) {
+	t.Parallel()
+
+	bus := NewBus(10, 1)
+	subscription, err := bus.Subscribe(Filter{}, "")
+	require.NoError(t, err)
+
+	bus.Publish(dto.OrderEvent{OrderID: "1"}, dto.OrderEvent{OrderID: "2"})
+
+	require.Equal(t, []string{"1"}, receive(t, subscription, 1))
+	_, ok := <-subscription.Events()
+	require.False(t, ok)
+	require.ErrorIs(t, subscription.Err(), ErrSubscriptionIsLagging)
+}
+
+func TestBus_Close(t *testing.T) {
+	t.Parallel()
+
+	bus := NewBus(10, 10)
+	subscription, err := bus.Subscribe(Filter{}, "")
+	require.NoError(t, err)
+
+	bus.Close()
+
+	_, ok := <-subscription.Events()
+	require.False(t, ok)
+	require.ErrorIs(t, subscription.Err(), ErrBusIsClosed)
+	_, err = bus.Subscribe(Filter{}, "")
+	require.ErrorIs(t, err, ErrBusIsClosed)
+	subscription.Close()
+}
@@ -0,0 +1,10 @@
+package eventbus
+
+import "errors"
+
+var (
+	ErrBusIsClosed           = errors.New("event bus is closed")
+	ErrSubscriptionIsLagging = errors.New("subscription is lagging behind the events")
+	ErrEventNotFound         = errors.New("event is not found, it's from another run of the server")
+	ErrEventIsExpired        = errors.New("event is no longer kept")
+)
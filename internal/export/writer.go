@@ -0,0 +1,143 @@
+// Package export writes the orders as CSV or NDJSON for the HTTP export and the export command.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"github.com/shopspring/decimal"
+	"homework/internal/dto"
+	"homework/internal/model"
+	"io"
+	"strconv"
+	"time"
+)
+
+const (
+	CSV    Format = "csv"
+	NDJSON Format = "ndjson"
+)
+
+var (
+	ErrUnknownFormat  = errors.New("unknown export format")
+	ErrTimeIsNotValid = errors.New("time is neither a date nor RFC3339")
+
+	header = []string{
+		"order_id", "recipient_id", "status", "delivered_at", "status_updated_at", "expiration_date",
+		"weight_in_gram", "price_in_rub", "wrapper", "wrapper_price_in_rub",
+	}
+)
+
+type (
+	Format string
+
+	// Writer writes the header of CSV when it's created, every Write is flushed to the underlying writer.
+	Writer struct {
+		csv  *csv.Writer
+		json *json.Encoder
+	}
+
+	record struct {
+		OrderID           string      `json:"order_id"`
+		RecipientID       string      `json:"recipient_id"`
+		Status            string      `json:"status"`
+		DeliveredAt       string      `json:"delivered_at"`
+		StatusUpdatedAt   string      `json:"status_updated_at"`
+		ExpirationDate    string      `json:"expiration_date"`
+		WeightInGram      json.Number `json:"weight_in_gram"`
+		PriceInRub        json.Number `json:"price_in_rub"`
+		Wrapper           string      `json:"wrapper,omitempty"`
+		WrapperPriceInRub json.Number `json:"wrapper_price_in_rub,omitempty"`
+	}
+)
+
+// ParseFormat takes csv by default.
+func ParseFormat(value string) (Format, error) {
+	switch Format(value) {
+	case "", CSV:
+		return CSV, nil
+	case NDJSON:
+		return NDJSON, nil
+	default:
+		return "", ErrUnknownFormat
+	}
+}
+
+func (f Format) ContentType() string {
+	if f == NDJSON {
+		return "application/x-ndjson"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// ParseTime takes a date in UTC like 2024-06-01 or a RFC3339 time, empty value is the zero time.
+func ParseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(model.TimeFormat, value)
+	if err != nil {
+		return time.Time{}, ErrTimeIsNotValid
+	}
+	return t, nil
+}
+
+func NewWriter(w io.Writer, format Format) (*Writer, error) {
+	switch format {
+	case CSV:
+		writer := &Writer{csv: csv.NewWriter(w)}
+		return writer, writer.writeCSV(header)
+	case NDJSON:
+		return &Writer{json: json.NewEncoder(w)}, nil
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+func (w *Writer) Write(orders []dto.ExportedOrder) error {
+	for _, order := range orders {
+		r := newRecord(order)
+		if w.json != nil {
+			if err := w.json.Encode(r); err != nil {
+				return err
+			}
+			continue
+		}
+		w.csv.Write([]string{
+			r.OrderID, r.RecipientID, r.Status, r.DeliveredAt, r.StatusUpdatedAt, r.ExpirationDate,
+			r.WeightInGram.String(), r.PriceInRub.String(), r.Wrapper, r.WrapperPriceInRub.String(),
+		})
+	}
+	if w.csv != nil {
+		w.csv.Flush()
+		return w.csv.Error()
+	}
+	return nil
+}
+
+func (w *Writer) writeCSV(row []string) error {
+	w.csv.Write(row)
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+func newRecord(order dto.ExportedOrder) record {
+	r := record{
+		OrderID:         order.ID,
+		RecipientID:     order.RecipientID,
+		Status:          string(order.Status),
+		DeliveredAt:     order.DeliveredAt.UTC().Format(model.TimeFormat),
+		StatusUpdatedAt: order.StatusUpdatedAt.UTC().Format(model.TimeFormat),
+		ExpirationDate:  order.ExpirationDate.UTC().Format(model.TimeFormat),
+		WeightInGram:    json.Number(strconv.FormatFloat(order.WeightInGram, 'f', -1, 64)),
+		PriceInRub:      json.Number(decimal.Decimal(order.PriceInRub).String()),
+	}
+	if order.Wrapper != nil {
+		r.Wrapper = string(order.Wrapper.GetType())
+		r.WrapperPriceInRub = json.Number(decimal.Decimal(order.Wrapper.GetPriceInRub()).String())
+	}
+	return r
+}
@@ -0,0 +1,112 @@
+package export
+
+import (
+	"bytes"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"homework/internal/dto"
+	"homework/internal/model"
+	"homework/internal/model/wrapper"
+	"testing"
+	"time"
+)
+
+func newExportedOrders() []dto.ExportedOrder {
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	return []dto.ExportedOrder{
+		{
+			Order: model.Order{
+				ID:              "1",
+				RecipientID:     "2",
+				Status:          model.StatusIssued,
+				StatusUpdatedAt: at,
+				ExpirationDate:  at,
+				WeightInGram:    1500,
+				PriceInRub:      wrapper.PriceInRub(decimal.NewFromFloat(10.5)),
+				Wrapper:         wrapper.NewWrapper(wrapper.BoxWrapper, 30000, wrapper.PriceInRub(decimal.NewFromInt(20))),
+			},
+			DeliveredAt: at,
+		},
+		{
+			Order: model.Order{
+				ID:              "3",
+				RecipientID:     "4, 5",
+				Status:          model.StatusDelivered,
+				StatusUpdatedAt: at,
+				ExpirationDate:  at,
+				WeightInGram:    1,
+				PriceInRub:      wrapper.PriceInRub(decimal.Zero),
+			},
+			DeliveredAt: at,
+		},
+	}
+}
+
+func TestWriter_CSV(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	writer, err := NewWriter(&out, CSV)
+	require.NoError(t, err)
+	require.NoError(t, writer.Write(newExportedOrders()))
+
+	require.Equal(t, "order_id,recipient_id,status,delivered_at,status_updated_at,expiration_date,weight_in_gram,price_in_rub,wrapper,wrapper_price_in_rub\n"+
+		"1,2,issued,2024-06-01T12:00:00Z,2024-06-01T12:00:00Z,2024-06-01T12:00:00Z,1500,10.5,box,20\n"+
+		"3,\"4, 5\",delivered,2024-06-01T12:00:00Z,2024-06-01T12:00:00Z,2024-06-01T12:00:00Z,1,0,,\n", out.String())
+}
+
+func TestWriter_NDJSON(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	writer, err := NewWriter(&out, NDJSON)
+	require.NoError(t, err)
+	require.NoError(t, writer.Write(newExportedOrders()[1:]))
+
+	require.Equal(t, `{"order_id":"3","recipient_id":"4, 5","status":"delivered","delivered_at":"2024-06-01T12:00:00Z",`+
+		`"status_updated_at":"2024-06-01T12:00:00Z","expiration_date":"2024-06-01T12:00:00Z","weight_in_gram":1,"price_in_rub":0}`+"\n", out.String())
+}
+
+func TestParseTime(t *testing.T) {
+	t.Parallel()
+
+	type test struct {
+		name  string
+		input string
+		want  time.Time
+		err   error
+	}
+
+	tests := []test{
+		{
+			name: "empty",
+		},
+		{
+			name:  "date",
+			input: "2024-06-01",
+			want:  time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "rfc3339",
+			input: "2024-06-01T10:00:00+03:00",
+			want:  time.Date(2024, 6, 1, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  ErrTimeIsNotValid.Error(),
+			input: "01.06.2024",
+			err:   ErrTimeIsNotValid,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseTime(tt.input)
+
+			require.ErrorIs(t, err, tt.err)
+			require.True(t, tt.want.Equal(got))
+		})
+	}
+}
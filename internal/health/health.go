@@ -0,0 +1,208 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+type (
+	Check func(ctx context.Context) error
+
+	// Result is the outcome of the last run of a check.
+	Result struct {
+		Name   string `json:"name"`
+		Status string `json:"status"`
+		Error  string `json:"error,omitempty"`
+	}
+
+	Report struct {
+		Status string   `json:"status"`
+		Checks []Result `json:"checks"`
+	}
+
+	namedCheck struct {
+		name  string
+		check Check
+	}
+
+	// Checker runs the checks of the dependencies and publishes their status through
+	// the grpc health service: every check under its own name, all of them under the
+	// services of the server and the empty name.
+	Checker struct {
+		server   *grpchealth.Server
+		services []string
+		timeout  time.Duration
+		checks   []namedCheck
+
+		lock     sync.RWMutex
+		results  []Result
+		shutdown atomic.Bool
+	}
+)
+
+// NewChecker fails a check that takes longer than timeout.
+func NewChecker(timeout time.Duration, services ...string) *Checker {
+	server := grpchealth.NewServer()
+	// nothing is checked yet
+	server.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	for _, service := range services {
+		server.SetServingStatus(service, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+
+	return &Checker{
+		server:   server,
+		services: services,
+		timeout:  timeout,
+	}
+}
+
+// Add must be called before Run.
+func (c *Checker) Add(name string, check Check) {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+	c.server.SetServingStatus(name, healthpb.HealthCheckResponse_NOT_SERVING)
+}
+
+func (c *Checker) Server() healthpb.HealthServer {
+	return c.server
+}
+
+// Run checks the dependencies every interval until the context is done.
+func (c *Checker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		c.CheckAll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckAll runs the checks concurrently and updates the statuses.
+func (c *Checker) CheckAll(ctx context.Context) Report {
+	results := make([]Result, len(c.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func(i int, check namedCheck) {
+			defer wg.Done()
+			results[i] = c.run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	c.lock.Lock()
+	c.results = results
+	c.lock.Unlock()
+
+	report := c.Report()
+	if c.shutdown.Load() {
+		return report
+	}
+	for _, result := range results {
+		c.server.SetServingStatus(result.Name, servingStatus(result.Status))
+	}
+	c.server.SetServingStatus("", servingStatus(report.Status))
+	for _, service := range c.services {
+		c.server.SetServingStatus(service, servingStatus(report.Status))
+	}
+	return report
+}
+
+// run gives up on the check after the timeout, the check itself may ignore the context.
+func (c *Checker) run(ctx context.Context, check namedCheck) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- check.check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	if err != nil {
+		log.Printf("[health] %s is down: %v", check.name, err)
+		return Result{Name: check.name, Status: StatusDown, Error: err.Error()}
+	}
+	return Result{Name: check.name, Status: StatusUp}
+}
+
+// Report is the result of the last run, it's down until the first run and after Shutdown.
+func (c *Checker) Report() Report {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	status := StatusUp
+	if c.results == nil || c.shutdown.Load() {
+		status = StatusDown
+	}
+	for _, result := range c.results {
+		if result.Status != StatusUp {
+			status = StatusDown
+		}
+	}
+	return Report{Status: status, Checks: c.results}
+}
+
+// Shutdown makes every service not serving, so the clients stop sending new calls.
+func (c *Checker) Shutdown() {
+	c.shutdown.Store(true)
+	c.server.Shutdown()
+}
+
+// Healthz tells that the process is alive, it fails only after Shutdown.
+// The body has the results of the checks, but a dependency being down doesn't fail it,
+// restarting the service won't bring Postgres back.
+func (c *Checker) Healthz(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	code := http.StatusOK
+	if c.shutdown.Load() {
+		code = http.StatusServiceUnavailable
+	}
+	writeReport(w, code, c.Report())
+}
+
+// Readyz fails while any dependency is down, so no calls are routed to the service.
+func (c *Checker) Readyz(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	report := c.Report()
+	code := http.StatusOK
+	if report.Status != StatusUp {
+		code = http.StatusServiceUnavailable
+	}
+	writeReport(w, code, report)
+}
+
+func writeReport(w http.ResponseWriter, code int, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+func servingStatus(status string) healthpb.HealthCheckResponse_ServingStatus {
+	if status == StatusUp {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
@@ -0,0 +1,109 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/require"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const service = "order.Order"
+
+func servingStatusOf(t *testing.T, checker *Checker, service string) healthpb.HealthCheckResponse_ServingStatus {
+	response, err := checker.Server().Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	require.NoError(t, err)
+	return response.Status
+}
+
+func get(checker *Checker, handler func(*Checker) func(http.ResponseWriter, *http.Request, map[string]string)) (int, Report) {
+	recorder := httptest.NewRecorder()
+	handler(checker)(recorder, httptest.NewRequest(http.MethodGet, "/", nil), nil)
+
+	var report Report
+	_ = json.NewDecoder(recorder.Body).Decode(&report)
+	return recorder.Code, report
+}
+
+func healthz(c *Checker) func(http.ResponseWriter, *http.Request, map[string]string) {
+	return c.Healthz
+}
+
+func readyz(c *Checker) func(http.ResponseWriter, *http.Request, map[string]string) {
+	return c.Readyz
+}
+
+func TestChecker(t *testing.T) {
+	t.Parallel()
+
+	var postgresDown atomic.Bool
+	checker := NewChecker(time.Second, service)
+	checker.Add("postgres", func(ctx context.Context) error {
+		if postgresDown.Load() {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	checker.Add("kafka", func(ctx context.Context) error {
+		return nil
+	})
+
+	// nothing is checked yet
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatusOf(t, checker, service))
+	code, _ := get(checker, readyz)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+
+	checker.CheckAll(context.Background())
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, servingStatusOf(t, checker, ""))
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, servingStatusOf(t, checker, service))
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, servingStatusOf(t, checker, "postgres"))
+	code, report := get(checker, readyz)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, StatusUp, report.Status)
+
+	postgresDown.Store(true)
+	checker.CheckAll(context.Background())
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatusOf(t, checker, service))
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatusOf(t, checker, "postgres"))
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, servingStatusOf(t, checker, "kafka"))
+
+	code, report = get(checker, readyz)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, Report{Status: StatusDown, Checks: []Result{
+		{Name: "postgres", Status: StatusDown, Error: "connection refused"},
+		{Name: "kafka", Status: StatusUp},
+	}}, report)
+
+	// the process is alive even though postgres is down
+	code, _ = get(checker, healthz)
+	require.Equal(t, http.StatusOK, code)
+
+	checker.Shutdown()
+	checker.CheckAll(context.Background())
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatusOf(t, checker, "kafka"))
+	code, _ = get(checker, healthz)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+}
+
+func TestChecker_Timeout(t *testing.T) {
+	t.Parallel()
+
+	checker := NewChecker(10 * time.Millisecond)
+	// the check ignores the context like sarama does
+	checker.Add("kafka", func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+
+	start := time.Now()
+	report := checker.CheckAll(context.Background())
+
+	require.Less(t, time.Since(start), time.Second)
+	require.Equal(t, StatusDown, report.Status)
+	require.Equal(t, context.DeadlineExceeded.Error(), report.Checks[0].Error)
+}
@@ -0,0 +1,25 @@
+package events
+
+import (
+	"context"
+	"github.com/IBM/sarama"
+	"homework/internal/dto"
+	"homework/internal/infrastructure/app/oncall"
+	"log"
+)
+
+// NewHandler gives the events of the other instances to the bus, the events of the source are already there.
+func NewHandler(bus bus, source string) oncall.HandleFunc {
+	return func(ctx context.Context, message *sarama.ConsumerMessage) {
+		var event dto.OrderEvent
+		if err := event.Unmarshal(message.Value); err != nil {
+			log.Printf("[events.Handler] error: %v", err)
+			return
+		}
+		if event.Source == source {
+			return
+		}
+
+		bus.Publish(event)
+	}
+}
@@ -0,0 +1,66 @@
+package events
+
+import (
+	"context"
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/require"
+	"homework/internal/dto"
+	"homework/internal/eventbus"
+	"homework/internal/model"
+	"testing"
+	"time"
+)
+
+type publisherFunc func(ctx context.Context, event dto.OrderEvent) error
+
+func (f publisherFunc) Publish(ctx context.Context, event dto.OrderEvent) error {
+	return f(ctx, event)
+}
+
+func TestHandler(t *testing.T) {
+	bus := eventbus.NewBus(10, 10)
+	subscription, err := bus.Subscribe(eventbus.Filter{PickupPoint: "msk-2"}, "")
+	require.NoError(t, err)
+
+	// the events of this instance come back from kafka and are skipped
+	var messages []*sarama.ConsumerMessage
+	notifier := NewNotifier(bus, publisherFunc(func(ctx context.Context, event dto.OrderEvent) error {
+		raw, err := event.Marshal()
+		messages = append(messages, &sarama.ConsumerMessage{Value: raw})
+		return err
+	}), "msk-2", "instance-1")
+	notifier.Publish(context.Background(), []dto.OrderEvent{{OrderID: "1", Status: model.StatusIssued, OccurredAt: time.Now()}})
+	require.Len(t, messages, 1)
+
+	other := dto.OrderEvent{OrderID: "2", Status: model.StatusIssued, PickupPoint: "msk-2", Source: "instance-2"}
+	raw, err := other.Marshal()
+	require.NoError(t, err)
+	messages = append(messages, &sarama.ConsumerMessage{Value: raw})
+
+	handler := NewHandler(bus, "instance-1")
+	for _, message := range messages {
+		handler(context.Background(), message)
+	}
+
+	var ids []string
+	for len(subscription.Events()) != 0 {
+		event := <-subscription.Events()
+		require.NotEmpty(t, event.ID)
+		ids = append(ids, event.OrderID)
+	}
+	require.Equal(t, []string{"1", "2"}, ids)
+}
+
+func TestNotifier_WithoutBus(t *testing.T) {
+	var published []dto.OrderEvent
+	notifier := NewNotifier(nil, publisherFunc(func(ctx context.Context, event dto.OrderEvent) error {
+		published = append(published, event)
+		return nil
+	}), "msk-2", "cli")
+
+	notifier.Publish(context.Background(), []dto.OrderEvent{{OrderID: "1", Status: model.StatusIssued}})
+
+	require.Len(t, published, 1)
+	require.Equal(t, "msk-2", published[0].PickupPoint)
+	require.Equal(t, "cli", published[0].Source)
+}
@@ -0,0 +1,55 @@
+package events
+
+import (
+	"context"
+	"homework/internal/dto"
+	"log"
+)
+
+type (
+	bus interface {
+		Publish(events ...dto.OrderEvent)
+	}
+
+	publisher interface {
+		Publish(ctx context.Context, event dto.OrderEvent) error
+	}
+
+	// Notifier gives the events of the service to the watchers of the instance, if bus is set,
+	// and to the other instances, if publisher is set.
+	Notifier struct {
+		bus         bus
+		publisher   publisher
+		pickupPoint string
+		source      string
+	}
+)
+
+// NewNotifier stamps the events with the pickup point and the source of the instance, bus or publisher may be nil.
+func NewNotifier(bus bus, publisher publisher, pickupPoint, source string) *Notifier {
+	return &Notifier{
+		bus:         bus,
+		publisher:   publisher,
+		pickupPoint: pickupPoint,
+		source:      source,
+	}
+}
+
+func (n *Notifier) Publish(ctx context.Context, events []dto.OrderEvent) {
+	for i := range events {
+		events[i].PickupPoint = n.pickupPoint
+		events[i].Source = n.source
+	}
+	if n.bus != nil {
+		n.bus.Publish(events...)
+	}
+
+	if n.publisher == nil {
+		return
+	}
+	for _, event := range events {
+		if err := n.publisher.Publish(ctx, event); err != nil {
+			log.Printf("[events.Notifier] error: %v", err)
+		}
+	}
+}
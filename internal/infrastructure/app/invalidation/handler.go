@@ -0,0 +1,25 @@
+package invalidation
+
+import (
+	"context"
+	"github.com/IBM/sarama"
+	"homework/internal/dto"
+	"homework/internal/infrastructure/app/oncall"
+	"log"
+)
+
+type ordersCache interface {
+	Invalidate(ids []string, filters []dto.OrderFilter)
+}
+
+func NewHandler(cache ordersCache) oncall.HandleFunc {
+	return func(ctx context.Context, message *sarama.ConsumerMessage) {
+		var invalidation dto.InvalidationMessage
+		if err := invalidation.Unmarshal(message.Value); err != nil {
+			log.Printf("[invalidation.Handler] error: %v", err)
+			return
+		}
+
+		cache.Invalidate(invalidation.Ids, invalidation.Filters)
+	}
+}
@@ -0,0 +1,26 @@
+package invalidation
+
+import (
+	"context"
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/require"
+	"homework/internal/cache"
+	"homework/internal/dto"
+	"homework/internal/model"
+	"testing"
+	"time"
+)
+
+func TestHandler(t *testing.T) {
+	ordersCache := cache.NewOrdersCache(10, time.Hour)
+	ordersCache.Put("key", []model.Order{{ID: "1"}})
+
+	message := dto.InvalidationMessage{Ids: []string{"1"}}
+	raw, err := message.Marshal()
+	require.NoError(t, err)
+
+	NewHandler(ordersCache)(context.Background(), &sarama.ConsumerMessage{Value: raw})
+
+	_, ok := ordersCache.Get("key")
+	require.False(t, ok)
+}
@@ -0,0 +1,55 @@
+package invalidation
+
+import (
+	"context"
+	"github.com/IBM/sarama"
+	"github.com/opentracing/opentracing-go"
+	"homework/internal/dto"
+	"homework/internal/infrastructure/kafka"
+	"homework/internal/tracer"
+)
+
+type KafkaPublisher struct {
+	producer *kafka.Producer
+	topic    kafka.Topic
+}
+
+func NewKafkaPublisher(producer *kafka.Producer, topic kafka.Topic) *KafkaPublisher {
+	return &KafkaPublisher{
+		producer: producer,
+		topic:    topic,
+	}
+}
+
+func (p *KafkaPublisher) Publish(ctx context.Context, message dto.InvalidationMessage) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "invalidation.KafkaPublisher.Publish")
+	defer span.Finish()
+
+	raw, err := message.Marshal()
+	if err != nil {
+		return err
+	}
+
+	kafkaMsg := &sarama.ProducerMessage{
+		Topic:     string(p.topic),
+		Value:     sarama.ByteEncoder(raw),
+		Partition: -1,
+	}
+	if err := tracer.InjectKafka(ctx, kafkaMsg); err != nil {
+		return err
+	}
+
+	return p.producer.SendAsyncMessage(kafkaMsg)
+}
+
+func (p *KafkaPublisher) Flush(ctx context.Context) error {
+	return p.producer.Flush(ctx)
+}
+
+func (p *KafkaPublisher) Ping(ctx context.Context) error {
+	return p.producer.Ping(ctx)
+}
+
+func (p *KafkaPublisher) Close() error {
+	return p.producer.Close()
+}
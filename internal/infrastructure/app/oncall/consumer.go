@@ -1,12 +1,14 @@
 package oncall
 
 import (
+	"context"
 	"github.com/IBM/sarama"
 	"homework/internal/infrastructure/kafka"
+	"homework/internal/tracer"
 	"sync"
 )
 
-type HandleFunc func(message *sarama.ConsumerMessage)
+type HandleFunc func(ctx context.Context, message *sarama.ConsumerMessage)
 
 type KafkaConsumer struct {
 	consumer *kafka.Consumer
@@ -44,7 +46,7 @@ func (r *KafkaConsumer) Subscribe(topic kafka.Topic, handler HandleFunc) error {
 
 		go func(pc sarama.PartitionConsumer, partition int32) {
 			for message := range pc.Messages() {
-				handler(message)
+				r.handle(handler, message)
 			}
 		}(pc, partition)
 	}
@@ -52,8 +54,19 @@ func (r *KafkaConsumer) Subscribe(topic kafka.Topic, handler HandleFunc) error {
 	return nil
 }
 
+func (r *KafkaConsumer) handle(handler HandleFunc, message *sarama.ConsumerMessage) {
+	span, ctx := tracer.StartSpanFromKafka(context.Background(), message, "oncall.KafkaConsumer.Handle")
+	defer span.Finish()
+
+	handler(ctx, message)
+}
+
+func (r *KafkaConsumer) Ping(ctx context.Context) error {
+	return r.consumer.Ping(ctx)
+}
+
 func (r *KafkaConsumer) Close() error {
 	close(r.closeNotify)
 	r.closeWG.Wait()
-	return r.consumer.SingleConsumer.Close()
+	return r.consumer.Close()
 }
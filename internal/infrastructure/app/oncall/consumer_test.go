@@ -20,7 +20,7 @@ func TestKafkaConsumer_Subscribe(t *testing.T) {
 	consumer.ExpectConsumePartition(topic, 1, -1).YieldMessage(&sarama.ConsumerMessage{})
 	ctx, cancel := context.WithCancel(context.Background())
 
-	err := receiver.Subscribe("call", func(message *sarama.ConsumerMessage) {
+	err := receiver.Subscribe("call", func(ctx context.Context, message *sarama.ConsumerMessage) {
 		cancel()
 	})
 
@@ -1,6 +1,7 @@
 package oncall
 
 import (
+	"context"
 	"fmt"
 	"github.com/IBM/sarama"
 	"homework/internal/dto"
@@ -8,7 +9,7 @@ import (
 
 func NewTopicHandler() (<-chan string, HandleFunc) {
 	out := make(chan string)
-	return out, func(message *sarama.ConsumerMessage) {
+	return out, func(ctx context.Context, message *sarama.ConsumerMessage) {
 		var callMessage dto.OnCallMessage
 		err := callMessage.Unmarshal(message.Value)
 		if err != nil {
@@ -1,9 +1,12 @@
 package oncall
 
 import (
+	"context"
 	"github.com/IBM/sarama"
+	"github.com/opentracing/opentracing-go"
 	"homework/internal/dto"
 	"homework/internal/infrastructure/kafka"
+	"homework/internal/tracer"
 )
 
 type KafkaProducer struct {
@@ -18,14 +21,27 @@ func NewKafkaProducer(producer *kafka.Producer, topic kafka.Topic) *KafkaProduce
 	}
 }
 
-func (p *KafkaProducer) SendAsyncMessage(message dto.OnCallMessage) error {
+func (p *KafkaProducer) SendAsyncMessage(ctx context.Context, message dto.OnCallMessage) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "oncall.KafkaProducer.SendAsyncMessage")
+	defer span.Finish()
+
 	kafkaMsg, err := p.buildMessage(message)
 	if err != nil {
 		return err
 	}
+	if err := tracer.InjectKafka(ctx, kafkaMsg); err != nil {
+		return err
+	}
+
+	return p.producer.SendAsyncMessage(kafkaMsg)
+}
+
+func (p *KafkaProducer) Flush(ctx context.Context) error {
+	return p.producer.Flush(ctx)
+}
 
-	p.producer.SendAsyncMessage(kafkaMsg)
-	return nil
+func (p *KafkaProducer) Ping(ctx context.Context) error {
+	return p.producer.Ping(ctx)
 }
 
 func (p *KafkaProducer) buildMessage(message dto.OnCallMessage) (*sarama.ProducerMessage, error) {
@@ -0,0 +1,174 @@
+package kafka
+
+import (
+	"fmt"
+	"github.com/IBM/sarama"
+	"github.com/pkg/errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const retentionConfig = "retention.ms"
+
+type (
+	TopicConfig struct {
+		Name              Topic
+		Partitions        int32
+		ReplicationFactor int16
+		Retention         time.Duration
+	}
+
+	Admin struct {
+		clusterAdmin sarama.ClusterAdmin
+	}
+
+	TopicMismatchError struct {
+		mismatches []string
+	}
+)
+
+func NewAdmin(brokers Brokers) (*Admin, error) {
+	clusterAdmin, err := sarama.NewClusterAdmin(brokers, sarama.NewConfig())
+	if err != nil {
+		return nil, errors.Wrap(err, "error with kafka cluster admin")
+	}
+
+	return newAdmin(clusterAdmin), nil
+}
+
+func newAdmin(clusterAdmin sarama.ClusterAdmin) *Admin {
+	return &Admin{clusterAdmin: clusterAdmin}
+}
+
+// EnsureTopics creates missing topics and reports the existing ones whose
+// partitions, replication factor or effective retention differ from the config.
+func (a *Admin) EnsureTopics(topics []TopicConfig) error {
+	existing, err := a.clusterAdmin.ListTopics()
+	if err != nil {
+		return errors.Wrap(err, "kafka.Admin.EnsureTopics")
+	}
+
+	var mismatches []string
+	for _, topic := range topics {
+		detail, ok := existing[string(topic.Name)]
+		if !ok {
+			err := a.clusterAdmin.CreateTopic(string(topic.Name), topic.detail(), false)
+			if err != nil && !errors.Is(err, sarama.ErrTopicAlreadyExists) {
+				return errors.Wrapf(err, "kafka.Admin.EnsureTopics: create %s", topic.Name)
+			}
+			continue
+		}
+
+		effective := retention(detail)
+		if topic.Retention != 0 {
+			effective, err = a.retention(topic.Name)
+			if err != nil {
+				return errors.Wrapf(err, "kafka.Admin.EnsureTopics: describe %s", topic.Name)
+			}
+		}
+		mismatches = append(mismatches, topic.mismatches(detail, effective)...)
+	}
+
+	if len(mismatches) != 0 {
+		return TopicMismatchError{mismatches: mismatches}
+	}
+	return nil
+}
+
+func (a *Admin) Topics() ([]TopicConfig, error) {
+	existing, err := a.clusterAdmin.ListTopics()
+	if err != nil {
+		return nil, errors.Wrap(err, "kafka.Admin.Topics")
+	}
+
+	topics := make([]TopicConfig, 0, len(existing))
+	for name, detail := range existing {
+		topics = append(topics, TopicConfig{
+			Name:              Topic(name),
+			Partitions:        detail.NumPartitions,
+			ReplicationFactor: detail.ReplicationFactor,
+			Retention:         retention(detail),
+		})
+	}
+
+	sort.Slice(topics, func(i, j int) bool {
+		return topics[i].Name < topics[j].Name
+	})
+	return topics, nil
+}
+
+// retention is the effective retention of the topic, the broker default if the topic doesn't override it.
+func (a *Admin) retention(topic Topic) (time.Duration, error) {
+	entries, err := a.clusterAdmin.DescribeConfig(sarama.ConfigResource{
+		Type:        sarama.TopicResource,
+		Name:        string(topic),
+		ConfigNames: []string{retentionConfig},
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, entry := range entries {
+		if entry.Name == retentionConfig {
+			return parseRetention(entry.Value), nil
+		}
+	}
+	return 0, nil
+}
+
+func (a *Admin) Close() error {
+	return a.clusterAdmin.Close()
+}
+
+func (t TopicConfig) detail() *sarama.TopicDetail {
+	detail := &sarama.TopicDetail{
+		NumPartitions:     t.Partitions,
+		ReplicationFactor: t.ReplicationFactor,
+	}
+	if t.Retention != 0 {
+		retention := strconv.FormatInt(t.Retention.Milliseconds(), 10)
+		detail.ConfigEntries = map[string]*string{retentionConfig: &retention}
+	}
+	return detail
+}
+
+func (t TopicConfig) mismatches(detail sarama.TopicDetail, retention time.Duration) []string {
+	var mismatches []string
+	if t.Partitions != 0 && t.Partitions != detail.NumPartitions {
+		mismatches = append(mismatches, fmt.Sprintf("%s: partitions=%d, want %d", t.Name, detail.NumPartitions, t.Partitions))
+	}
+	if t.ReplicationFactor != 0 && t.ReplicationFactor != detail.ReplicationFactor {
+		mismatches = append(mismatches, fmt.Sprintf("%s: replication_factor=%d, want %d", t.Name, detail.ReplicationFactor, t.ReplicationFactor))
+	}
+	if t.Retention != 0 && t.Retention != retention {
+		mismatches = append(mismatches, fmt.Sprintf("%s: retention=%s, want %s", t.Name, retention, t.Retention))
+	}
+	return mismatches
+}
+
+func (t TopicConfig) String() string {
+	return fmt.Sprintf("Topic(name=%s partitions=%d replication_factor=%d retention=%s)",
+		t.Name, t.Partitions, t.ReplicationFactor, t.Retention)
+}
+
+// retention returns zero when the topic uses the broker default.
+func retention(detail sarama.TopicDetail) time.Duration {
+	value, ok := detail.ConfigEntries[retentionConfig]
+	if !ok || value == nil {
+		return 0
+	}
+	return parseRetention(*value)
+}
+
+func parseRetention(value string) time.Duration {
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+func (e TopicMismatchError) Error() string {
+	return "topics do not match config: " + strings.Join(e.mismatches, "; ")
+}
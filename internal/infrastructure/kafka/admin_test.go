@@ -0,0 +1,148 @@
+package kafka
+
+import (
+	"errors"
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type clusterAdmin struct {
+	sarama.ClusterAdmin
+	topics  map[string]sarama.TopicDetail
+	created map[string]*sarama.TopicDetail
+	// retention is the effective retention.ms of every topic
+	retention map[string]string
+}
+
+func newClusterAdmin(topics map[string]sarama.TopicDetail) *clusterAdmin {
+	return &clusterAdmin{
+		topics:    topics,
+		created:   make(map[string]*sarama.TopicDetail),
+		retention: make(map[string]string),
+	}
+}
+
+func (c *clusterAdmin) ListTopics() (map[string]sarama.TopicDetail, error) {
+	return c.topics, nil
+}
+
+func (c *clusterAdmin) CreateTopic(topic string, detail *sarama.TopicDetail, validateOnly bool) error {
+	c.created[topic] = detail
+	return nil
+}
+
+func (c *clusterAdmin) DescribeConfig(resource sarama.ConfigResource) ([]sarama.ConfigEntry, error) {
+	value, ok := c.retention[resource.Name]
+	if !ok {
+		return nil, nil
+	}
+	return []sarama.ConfigEntry{{Name: retentionConfig, Value: value}}, nil
+}
+
+func TestAdmin_EnsureTopics(t *testing.T) {
+	t.Parallel()
+
+	retention := "3600000"
+	type test struct {
+		name      string
+		existing  map[string]sarama.TopicDetail
+		effective string
+		topic     TopicConfig
+		created   bool
+		mismatch  bool
+	}
+
+	topic := TopicConfig{Name: "call", Partitions: 3, ReplicationFactor: 2, Retention: time.Hour}
+	tests := []test{
+		{
+			name:     "create",
+			existing: map[string]sarama.TopicDetail{},
+			topic:    topic,
+			created:  true,
+		},
+		{
+			name: "matches",
+			existing: map[string]sarama.TopicDetail{"call": {
+				NumPartitions:     3,
+				ReplicationFactor: 2,
+				ConfigEntries:     map[string]*string{retentionConfig: &retention},
+			}},
+			effective: retention,
+			topic:     topic,
+		},
+		{
+			name: "partitions mismatch",
+			existing: map[string]sarama.TopicDetail{"call": {
+				NumPartitions:     1,
+				ReplicationFactor: 2,
+				ConfigEntries:     map[string]*string{retentionConfig: &retention},
+			}},
+			effective: retention,
+			topic:     topic,
+			mismatch:  true,
+		},
+		{
+			name: "default retention matches",
+			existing: map[string]sarama.TopicDetail{"call": {
+				NumPartitions:     3,
+				ReplicationFactor: 2,
+			}},
+			effective: retention,
+			topic:     topic,
+		},
+		{
+			name: "default retention mismatch",
+			existing: map[string]sarama.TopicDetail{"call": {
+				NumPartitions:     3,
+				ReplicationFactor: 2,
+			}},
+			effective: "604800000",
+			topic:     topic,
+			mismatch:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			clusterAdmin := newClusterAdmin(tt.existing)
+			if tt.effective != "" {
+				clusterAdmin.retention[string(tt.topic.Name)] = tt.effective
+			}
+			err := newAdmin(clusterAdmin).EnsureTopics([]TopicConfig{tt.topic})
+
+			var mismatchErr TopicMismatchError
+			require.Equal(t, tt.mismatch, err != nil)
+			require.Equal(t, tt.mismatch, errors.As(err, &mismatchErr))
+
+			detail, created := clusterAdmin.created[string(tt.topic.Name)]
+			require.Equal(t, tt.created, created)
+			if created {
+				require.Equal(t, tt.topic.Partitions, detail.NumPartitions)
+				require.Equal(t, tt.topic.ReplicationFactor, detail.ReplicationFactor)
+				require.Equal(t, retention, *detail.ConfigEntries[retentionConfig])
+			}
+		})
+	}
+}
+
+func TestAdmin_Topics(t *testing.T) {
+	t.Parallel()
+
+	clusterAdmin := newClusterAdmin(map[string]sarama.TopicDetail{
+		"b": {NumPartitions: 1, ReplicationFactor: 1},
+		"a": {NumPartitions: 2, ReplicationFactor: 3},
+	})
+
+	topics, err := newAdmin(clusterAdmin).Topics()
+
+	require.NoError(t, err)
+	require.Equal(t, []TopicConfig{
+		{Name: "a", Partitions: 2, ReplicationFactor: 3},
+		{Name: "b", Partitions: 1, ReplicationFactor: 1},
+	}, topics)
+}
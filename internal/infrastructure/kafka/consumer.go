@@ -1,12 +1,18 @@
 package kafka
 
 import (
+	"context"
 	"github.com/IBM/sarama"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 type Consumer struct {
 	SingleConsumer sarama.Consumer
+	// client is nil when the consumer is created without one, Ping is a no-op then.
+	client sarama.Client
+	pinger pinger
 }
 
 func NewConsumer(brokers Brokers) (*Consumer, error) {
@@ -15,8 +21,36 @@ func NewConsumer(brokers Brokers) (*Consumer, error) {
 	config.Consumer.Offsets.AutoCommit.Enable = true
 	config.Consumer.Offsets.AutoCommit.Interval = 5 * time.Second
 
-	consumer, err := sarama.NewConsumer(brokers, config)
+	client, err := sarama.NewClient(brokers, config)
+	if err != nil {
+		return nil, errors.Wrap(err, "error with kafka client")
+	}
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		_ = client.Close()
+		return nil, errors.Wrap(err, "error with kafka consumer")
+	}
 	return &Consumer{
 		SingleConsumer: consumer,
-	}, err
+		client:         client,
+	}, nil
+}
+
+// Ping refreshes the cluster metadata, so it fails when no broker answers before the context is done.
+func (c *Consumer) Ping(ctx context.Context) error {
+	if err := c.pinger.ping(ctx, c.client); err != nil {
+		return errors.Wrap(err, "kafka.Consumer.Ping")
+	}
+	return nil
+}
+
+// Close closes the client too, the consumer created from the client doesn't close it.
+func (c *Consumer) Close() error {
+	err := c.SingleConsumer.Close()
+	if c.client != nil {
+		if clientErr := c.client.Close(); err == nil {
+			err = clientErr
+		}
+	}
+	return err
 }
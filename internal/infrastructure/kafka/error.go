@@ -0,0 +1,10 @@
+package kafka
+
+import "errors"
+
+var (
+	ErrBufferIsFull     = errors.New("producer buffer is full")
+	ErrProducerIsClosed = errors.New("producer is closed")
+	ErrClientIsClosed   = errors.New("kafka client is closed")
+	ErrNoBrokers        = errors.New("no kafka brokers are available")
+)
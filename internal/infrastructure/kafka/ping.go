@@ -0,0 +1,59 @@
+package kafka
+
+import (
+	"context"
+	"github.com/IBM/sarama"
+	"sync"
+)
+
+type (
+	// pinger refreshes the cluster metadata of a client. The refresh doesn't take a context and may outlive the ping,
+	// so the pings made while it's running wait for it instead of starting their own.
+	pinger struct {
+		lock    sync.Mutex
+		running *refresh
+	}
+
+	refresh struct {
+		done chan struct{}
+		err  error
+	}
+)
+
+// ping fails when no broker answers before the context is done, a nil client is always up.
+func (p *pinger) ping(ctx context.Context, client sarama.Client) error {
+	if client == nil {
+		return nil
+	}
+	if client.Closed() {
+		return ErrClientIsClosed
+	}
+
+	p.lock.Lock()
+	r := p.running
+	if r == nil {
+		r = &refresh{done: make(chan struct{})}
+		p.running = r
+		go p.refresh(client, r)
+	}
+	p.lock.Unlock()
+
+	select {
+	case <-r.done:
+		return r.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *pinger) refresh(client sarama.Client, r *refresh) {
+	r.err = client.RefreshMetadata()
+	if r.err == nil && len(client.Brokers()) == 0 {
+		r.err = ErrNoBrokers
+	}
+
+	p.lock.Lock()
+	p.running = nil
+	p.lock.Unlock()
+	close(r.done)
+}
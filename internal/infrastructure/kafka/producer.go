@@ -2,78 +2,309 @@ package kafka
 
 import (
 	"context"
-	"fmt"
 	"github.com/IBM/sarama"
+	"homework/internal/metrics"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/pkg/errors"
 )
 
-type Producer struct {
-	asyncProducer sarama.AsyncProducer
-}
+const (
+	OverflowBlock Overflow = "block"
+	OverflowDrop  Overflow = "drop"
+	OverflowSpill Overflow = "spill"
+
+	flushInterval = 10 * time.Millisecond
+)
+
+type (
+	// Overflow tells the producer what to do with a message when the local buffer is full.
+	Overflow string
+
+	ProducerConfig struct {
+		BufferSize int
+		Overflow   Overflow
+		SpillPath  string
+	}
 
-func newAsyncProducer(ctx context.Context, brokers Brokers) (sarama.AsyncProducer, error) {
+	Producer struct {
+		// client is nil when the producer is created without one, Ping is a no-op then.
+		client        sarama.Client
+		pinger        pinger
+		asyncProducer sarama.AsyncProducer
+		overflow      Overflow
+		spill         *spill
+
+		buffer   chan *sarama.ProducerMessage
+		inFlight atomic.Int64
+
+		done chan struct{}
+		// sendMu is read-held by the sends and write-held by Close, so no message gets into the buffer after it's drained
+		sendMu    sync.RWMutex
+		closeOnce sync.Once
+		forwardWG sync.WaitGroup
+		reportWG  sync.WaitGroup
+	}
+)
+
+func newAsyncProducer(brokers Brokers) (sarama.Client, sarama.AsyncProducer, error) {
 	asyncProducerConfig := sarama.NewConfig()
 
-	asyncProducerConfig.Producer.Partitioner = sarama.NewRandomPartitioner
+	asyncProducerConfig.Producer.Partitioner = sarama.NewHashPartitioner
 	asyncProducerConfig.Producer.RequiredAcks = sarama.WaitForAll
+	asyncProducerConfig.Producer.Idempotent = true
+	asyncProducerConfig.Producer.Retry.Max = 5
+	asyncProducerConfig.Net.MaxOpenRequests = 1
 
 	asyncProducerConfig.Producer.Return.Successes = true
 	asyncProducerConfig.Producer.Return.Errors = true
 
-	asyncProducer, err := sarama.NewAsyncProducer(brokers, asyncProducerConfig)
+	client, err := sarama.NewClient(brokers, asyncProducerConfig)
 	if err != nil {
-		return nil, errors.Wrap(err, "error with async kafka-producer")
+		return nil, nil, errors.Wrap(err, "error with kafka client")
+	}
+	asyncProducer, err := sarama.NewAsyncProducerFromClient(client)
+	if err != nil {
+		_ = client.Close()
+		return nil, nil, errors.Wrap(err, "error with async kafka-producer")
 	}
 
-	go func() {
-		successesChan := asyncProducer.Successes()
-		errorsChan := asyncProducer.Errors()
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case _, ok := <-successesChan:
-				if !ok {
-					return
-				}
-			case e, ok := <-errorsChan:
-				if !ok {
-					return
-				}
-				if e == nil {
-					continue
-				}
-				fmt.Println("Async error with key", e.Error())
-			}
-		}
-	}()
-
-	return asyncProducer, nil
+	return client, asyncProducer, nil
 }
 
-func NewProducer(ctx context.Context, brokers Brokers) (*Producer, error) {
-	asyncProducer, err := newAsyncProducer(ctx, brokers)
+func NewProducer(ctx context.Context, brokers Brokers, cfg ProducerConfig) (*Producer, error) {
+	client, asyncProducer, err := newAsyncProducer(brokers)
 	if err != nil {
 		return nil, errors.Wrap(err, "error with async kafka-producer")
 	}
 
+	producer := newProducer(ctx, asyncProducer, cfg)
+	producer.client = client
+	return producer, nil
+}
+
+func newProducer(ctx context.Context, asyncProducer sarama.AsyncProducer, cfg ProducerConfig) *Producer {
 	producer := &Producer{
 		asyncProducer: asyncProducer,
+		overflow:      cfg.Overflow,
+		buffer:        make(chan *sarama.ProducerMessage, cfg.BufferSize),
+		done:          make(chan struct{}),
+	}
+	if cfg.Overflow == OverflowSpill {
+		producer.spill = newSpill(cfg.SpillPath)
 	}
 
-	return producer, nil
+	producer.forwardWG.Add(1)
+	go producer.forward()
+
+	producer.reportWG.Add(1)
+	go producer.report()
+
+	if producer.spill != nil {
+		go producer.replay(ctx)
+	}
+
+	return producer
+}
+
+// SendAsyncMessage puts the message into the local buffer. When the buffer is full
+// the call blocks, drops the message or spills it to disk depending on the overflow mode.
+func (k *Producer) SendAsyncMessage(message *sarama.ProducerMessage) error {
+	if k.overflow != OverflowDrop && k.overflow != OverflowSpill {
+		return k.push(context.Background(), message)
+	}
+
+	k.sendMu.RLock()
+	defer k.sendMu.RUnlock()
+	select {
+	case <-k.done:
+		return ErrProducerIsClosed
+	default:
+	}
+
+	k.inFlight.Add(1)
+	select {
+	case k.buffer <- message:
+		return nil
+	default:
+		k.inFlight.Add(-1)
+		return k.handleOverflow(message)
+	}
 }
 
-func (k *Producer) SendAsyncMessage(message *sarama.ProducerMessage) {
-	k.asyncProducer.Input() <- message
+// Flush waits until every accepted message is either acknowledged or failed.
+func (k *Producer) Flush(ctx context.Context) error {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for k.inFlight.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
 }
 
 func (k *Producer) Close() error {
-	err := k.asyncProducer.Close()
+	var err error
+	k.closeOnce.Do(func() {
+		// the blocked sends return on done, then the lock waits for the sends that got past the done check
+		close(k.done)
+		k.sendMu.Lock()
+		defer k.sendMu.Unlock()
+
+		k.forwardWG.Wait()
+		k.drain()
+
+		err = k.asyncProducer.Close()
+		k.reportWG.Wait()
+		// the producer created from the client doesn't close it
+		if k.client != nil {
+			if clientErr := k.client.Close(); err == nil {
+				err = clientErr
+			}
+		}
+	})
 	if err != nil {
 		return errors.Wrap(err, "kafka.Connector.Close")
 	}
 
 	return nil
 }
+
+// Ping refreshes the cluster metadata, so it fails when no broker answers before the context is done.
+func (k *Producer) Ping(ctx context.Context) error {
+	if err := k.pinger.ping(ctx, k.client); err != nil {
+		return errors.Wrap(err, "kafka.Producer.Ping")
+	}
+	return nil
+}
+
+func (k *Producer) push(ctx context.Context, message *sarama.ProducerMessage) error {
+	k.sendMu.RLock()
+	defer k.sendMu.RUnlock()
+	return k.pushLocked(ctx, message)
+}
+
+func (k *Producer) pushLocked(ctx context.Context, message *sarama.ProducerMessage) error {
+	select {
+	case <-k.done:
+		return ErrProducerIsClosed
+	default:
+	}
+
+	k.inFlight.Add(1)
+	select {
+	case k.buffer <- message:
+		return nil
+	case <-ctx.Done():
+		k.inFlight.Add(-1)
+		return ctx.Err()
+	case <-k.done:
+		k.inFlight.Add(-1)
+		return ErrProducerIsClosed
+	}
+}
+
+func (k *Producer) handleOverflow(message *sarama.ProducerMessage) error {
+	if k.spill == nil {
+		metrics.AddKafkaMessage(message.Topic, metrics.KafkaMessageDropped)
+		return ErrBufferIsFull
+	}
+
+	if err := k.spill.Write(message); err != nil {
+		metrics.AddKafkaMessage(message.Topic, metrics.KafkaMessageDropped)
+		return err
+	}
+	metrics.AddKafkaMessage(message.Topic, metrics.KafkaMessageSpilled)
+	return nil
+}
+
+func (k *Producer) forward() {
+	defer k.forwardWG.Done()
+
+	for {
+		select {
+		case <-k.done:
+			return
+		case message := <-k.buffer:
+			select {
+			case k.asyncProducer.Input() <- message:
+			case <-k.done:
+				k.inFlight.Add(-1)
+				_ = k.handleOverflow(message)
+				return
+			}
+		}
+	}
+}
+
+func (k *Producer) report() {
+	defer k.reportWG.Done()
+
+	successes := k.asyncProducer.Successes()
+	errs := k.asyncProducer.Errors()
+	for successes != nil || errs != nil {
+		select {
+		case message, ok := <-successes:
+			if !ok {
+				successes = nil
+				continue
+			}
+			metrics.AddKafkaMessage(message.Topic, metrics.KafkaMessageSucceeded)
+			k.inFlight.Add(-1)
+		case e, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			log.Printf("[kafka.Producer] error: %v", e.Err)
+			metrics.AddKafkaMessage(e.Msg.Topic, metrics.KafkaMessageFailed)
+			k.inFlight.Add(-1)
+		}
+	}
+}
+
+// drain moves messages left in the buffer after close to the spill file or drops them.
+func (k *Producer) drain() {
+	for {
+		select {
+		case message := <-k.buffer:
+			k.inFlight.Add(-1)
+			_ = k.handleOverflow(message)
+		default:
+			return
+		}
+	}
+}
+
+// replay sends the messages spilled by the previous run, Close waits for the unsent ones to be spilled back.
+func (k *Producer) replay(ctx context.Context) {
+	k.sendMu.RLock()
+	defer k.sendMu.RUnlock()
+	select {
+	case <-k.done:
+		return
+	default:
+	}
+
+	messages, err := k.spill.ReadAll()
+	if err != nil {
+		log.Printf("[kafka.Producer] replay error: %v", err)
+		return
+	}
+
+	for i, message := range messages {
+		if err := k.pushLocked(ctx, message); err != nil {
+			for _, message := range messages[i:] {
+				_ = k.handleOverflow(message)
+			}
+			return
+		}
+	}
+}
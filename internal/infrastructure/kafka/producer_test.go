@@ -0,0 +1,260 @@
+package kafka
+
+import (
+	"context"
+	"github.com/IBM/sarama"
+	mock_kafka "github.com/IBM/sarama/mocks"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type stuckAsyncProducer struct {
+	sarama.AsyncProducer
+	input     chan *sarama.ProducerMessage
+	successes chan *sarama.ProducerMessage
+	errors    chan *sarama.ProducerError
+}
+
+func newStuckAsyncProducer() *stuckAsyncProducer {
+	return &stuckAsyncProducer{
+		input:     make(chan *sarama.ProducerMessage),
+		successes: make(chan *sarama.ProducerMessage),
+		errors:    make(chan *sarama.ProducerError),
+	}
+}
+
+func (p *stuckAsyncProducer) Input() chan<- *sarama.ProducerMessage {
+	return p.input
+}
+
+func (p *stuckAsyncProducer) Successes() <-chan *sarama.ProducerMessage {
+	return p.successes
+}
+
+func (p *stuckAsyncProducer) Errors() <-chan *sarama.ProducerError {
+	return p.errors
+}
+
+func (p *stuckAsyncProducer) Close() error {
+	close(p.successes)
+	close(p.errors)
+	return nil
+}
+
+func newMessage(value string) *sarama.ProducerMessage {
+	return &sarama.ProducerMessage{
+		Topic:     "call",
+		Key:       sarama.StringEncoder("key"),
+		Value:     sarama.StringEncoder(value),
+		Partition: -1,
+	}
+}
+
+func TestProducer_SendAndFlush(t *testing.T) {
+	t.Parallel()
+
+	cfg := sarama.NewConfig()
+	cfg.Producer.Return.Successes = true
+	asyncProducer := mock_kafka.NewAsyncProducer(t, cfg)
+	asyncProducer.ExpectInputAndSucceed()
+	asyncProducer.ExpectInputAndSucceed()
+	producer := newProducer(context.Background(), asyncProducer, ProducerConfig{BufferSize: 1, Overflow: OverflowBlock})
+
+	require.NoError(t, producer.SendAsyncMessage(newMessage("1")))
+	require.NoError(t, producer.SendAsyncMessage(newMessage("2")))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, producer.Flush(ctx))
+	require.NoError(t, producer.Close())
+}
+
+func TestProducer_Drop(t *testing.T) {
+	t.Parallel()
+
+	producer := newProducer(context.Background(), newStuckAsyncProducer(), ProducerConfig{BufferSize: 1, Overflow: OverflowDrop})
+
+	// the first message is taken by the forwarder and hangs on Input, the second one fills the buffer
+	require.NoError(t, producer.SendAsyncMessage(newMessage("1")))
+	require.Eventually(t, func() bool {
+		return producer.SendAsyncMessage(newMessage("2")) == ErrBufferIsFull
+	}, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, producer.Flush(ctx), context.DeadlineExceeded)
+	require.NoError(t, producer.Close())
+	require.ErrorIs(t, producer.SendAsyncMessage(newMessage("3")), ErrProducerIsClosed)
+}
+
+func TestProducer_SendWhileClosing(t *testing.T) {
+	t.Parallel()
+
+	producer := newProducer(context.Background(), newStuckAsyncProducer(), ProducerConfig{BufferSize: 100, Overflow: OverflowDrop})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = producer.SendAsyncMessage(newMessage("1"))
+			}
+		}()
+	}
+	require.NoError(t, producer.Close())
+	wg.Wait()
+
+	// a message accepted after the buffer was drained would never leave the flight
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.NoError(t, producer.Flush(ctx))
+}
+
+func TestProducer_SpillAndReplay(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "spill.jsonl")
+	producer := newProducer(context.Background(), newStuckAsyncProducer(), ProducerConfig{Overflow: OverflowSpill, SpillPath: path})
+
+	require.NoError(t, producer.SendAsyncMessage(newMessage("1")))
+	require.NoError(t, producer.SendAsyncMessage(newMessage("2")))
+	require.NoError(t, producer.Close())
+
+	messages, err := newSpill(path).ReadAll()
+	require.NoError(t, err)
+	require.NotEmpty(t, messages)
+
+	for _, message := range messages {
+		require.NoError(t, newSpill(path).Write(message))
+	}
+
+	cfg := sarama.NewConfig()
+	cfg.Producer.Return.Successes = true
+	asyncProducer := mock_kafka.NewAsyncProducer(t, cfg)
+	for range messages {
+		asyncProducer.ExpectInputAndSucceed()
+	}
+	producer = newProducer(context.Background(), asyncProducer, ProducerConfig{Overflow: OverflowSpill, SpillPath: path})
+
+	require.Eventually(t, func() bool {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		return producer.Flush(ctx) == nil
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, producer.Close())
+}
+
+type metadataClient struct {
+	sarama.Client
+	err     error
+	brokers []*sarama.Broker
+	closed  bool
+}
+
+func (c *metadataClient) RefreshMetadata(topics ...string) error {
+	return c.err
+}
+
+func (c *metadataClient) Brokers() []*sarama.Broker {
+	return c.brokers
+}
+
+func (c *metadataClient) Closed() bool {
+	return c.closed
+}
+
+func (c *metadataClient) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestProducer_Ping(t *testing.T) {
+	t.Parallel()
+
+	type test struct {
+		name   string
+		client *metadataClient
+		err    bool
+	}
+
+	tests := []test{
+		{
+			name:   "broker answers",
+			client: &metadataClient{brokers: []*sarama.Broker{sarama.NewBroker("localhost:9092")}},
+		},
+		{
+			name:   "metadata error",
+			client: &metadataClient{err: sarama.ErrOutOfBrokers},
+			err:    true,
+		},
+		{
+			name:   "no brokers",
+			client: &metadataClient{},
+			err:    true,
+		},
+		{
+			name:   "closed",
+			client: &metadataClient{closed: true},
+			err:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			producer := newProducer(context.Background(), newStuckAsyncProducer(), ProducerConfig{})
+			producer.client = tt.client
+
+			err := producer.Ping(context.Background())
+			if tt.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.NoError(t, producer.Close())
+			require.True(t, tt.client.closed)
+		})
+	}
+}
+
+type stuckClient struct {
+	metadataClient
+	refreshes atomic.Int64
+	unblock   chan struct{}
+}
+
+func (c *stuckClient) RefreshMetadata(topics ...string) error {
+	c.refreshes.Add(1)
+	<-c.unblock
+	return nil
+}
+
+func TestProducer_PingWaitsForRunningRefresh(t *testing.T) {
+	t.Parallel()
+
+	client := &stuckClient{
+		metadataClient: metadataClient{brokers: []*sarama.Broker{sarama.NewBroker("localhost:9092")}},
+		unblock:        make(chan struct{}),
+	}
+	producer := newProducer(context.Background(), newStuckAsyncProducer(), ProducerConfig{})
+	producer.client = client
+
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		require.ErrorIs(t, producer.Ping(ctx), context.DeadlineExceeded)
+		cancel()
+	}
+	require.Equal(t, int64(1), client.refreshes.Load())
+
+	close(client.unblock)
+	require.NoError(t, producer.Ping(context.Background()))
+	require.NoError(t, producer.Close())
+}
@@ -0,0 +1,104 @@
+package kafka
+
+import (
+	"bufio"
+	"encoding/json"
+	"github.com/IBM/sarama"
+	"os"
+	"sync"
+)
+
+type (
+	spill struct {
+		lock sync.Mutex
+		path string
+	}
+
+	spilledMessage struct {
+		Topic   string                `json:"topic"`
+		Key     []byte                `json:"key"`
+		Value   []byte                `json:"value"`
+		Headers []sarama.RecordHeader `json:"headers"`
+	}
+)
+
+func newSpill(path string) *spill {
+	return &spill{path: path}
+}
+
+func (s *spill) Write(message *sarama.ProducerMessage) error {
+	record := spilledMessage{
+		Topic:   message.Topic,
+		Headers: message.Headers,
+	}
+
+	var err error
+	if message.Key != nil {
+		if record.Key, err = message.Key.Encode(); err != nil {
+			return err
+		}
+	}
+	if message.Value != nil {
+		if record.Value, err = message.Value.Encode(); err != nil {
+			return err
+		}
+	}
+
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(raw, '\n'))
+	return err
+}
+
+// ReadAll returns every spilled message and removes the spill file.
+func (s *spill) ReadAll() ([]*sarama.ProducerMessage, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var messages []*sarama.ProducerMessage
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), 16*1024*1024)
+	for scanner.Scan() {
+		var record spilledMessage
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, err
+		}
+
+		message := &sarama.ProducerMessage{
+			Topic:     record.Topic,
+			Value:     sarama.ByteEncoder(record.Value),
+			Headers:   record.Headers,
+			Partition: -1,
+		}
+		if record.Key != nil {
+			message.Key = sarama.ByteEncoder(record.Key)
+		}
+		messages = append(messages, message)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return messages, os.Remove(s.path)
+}
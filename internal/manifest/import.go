@@ -0,0 +1,74 @@
+package manifest
+
+import (
+	"context"
+	"github.com/opentracing/opentracing-go"
+	"homework/internal/dto"
+)
+
+// DefaultChunkSize is the deliver.chunk_size default of config/api.yml.
+const DefaultChunkSize = 500
+
+type (
+	orderService interface {
+		DeliverOrders(ctx context.Context, params []dto.DeliverOrderParam) ([]error, error)
+		CheckDeliverOrders(ctx context.Context, params []dto.DeliverOrderParam) ([]error, error)
+	}
+
+	// Report has the rows with the results of their orders, Accepted are delivered or, in a dry run, would be.
+	Report struct {
+		Rows     []Row
+		Accepted int
+		Failed   int
+		DryRun   bool
+	}
+)
+
+// Import delivers the valid orders of the rows in a transaction per chunkSize orders,
+// a failed transaction fails every order of its chunk. A dry run only checks them.
+func Import(ctx context.Context, service orderService, rows []Row, dryRun bool, chunkSize int) Report {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "manifest.Import")
+	defer span.Finish()
+
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	deliver := service.DeliverOrders
+	if dryRun {
+		deliver = service.CheckDeliverOrders
+	}
+
+	// valid are the indexes of the rows without errors
+	var valid []int
+	for i, row := range rows {
+		if row.Err == nil {
+			valid = append(valid, i)
+		}
+	}
+	for start := 0; start < len(valid); start += chunkSize {
+		chunk := valid[start:min(start+chunkSize, len(valid))]
+		params := make([]dto.DeliverOrderParam, 0, len(chunk))
+		for _, i := range chunk {
+			params = append(params, rows[i].Param)
+		}
+
+		errs, err := deliver(ctx, params)
+		for j, i := range chunk {
+			if err != nil {
+				rows[i].Err = err
+			} else {
+				rows[i].Err = errs[j]
+			}
+		}
+	}
+
+	report := Report{Rows: rows, DryRun: dryRun}
+	for _, row := range rows {
+		if row.Err == nil {
+			report.Accepted++
+		} else {
+			report.Failed++
+		}
+	}
+	return report
+}
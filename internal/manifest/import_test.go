@@ -0,0 +1,80 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"homework/internal/dto"
+	"testing"
+)
+
+var (
+	errDuplicate = errors.New("duplicate")
+	errConn      = errors.New("connection refused")
+)
+
+type fakeService struct {
+	chunks  []int
+	checked bool
+}
+
+func (s *fakeService) DeliverOrders(_ context.Context, params []dto.DeliverOrderParam) ([]error, error) {
+	s.chunks = append(s.chunks, len(params))
+	if len(s.chunks) == 2 {
+		return nil, errConn
+	}
+	errs := make([]error, len(params))
+	for i, param := range params {
+		if param.ID == "2" {
+			errs[i] = errDuplicate
+		}
+	}
+	return errs, nil
+}
+
+func (s *fakeService) CheckDeliverOrders(ctx context.Context, params []dto.DeliverOrderParam) ([]error, error) {
+	s.checked = true
+	return s.DeliverOrders(ctx, params)
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	type test struct {
+		name   string
+		dryRun bool
+	}
+
+	tests := []test{
+		{name: "deliver"},
+		{name: "dry run", dryRun: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			errInvalid := FieldError{Field: ColumnUser, Err: ErrUserIsEmpty}
+			rows := []Row{
+				{Number: 1, ID: "1", Param: dto.DeliverOrderParam{ID: "1"}},
+				{Number: 2, ID: "x", Err: errInvalid},
+				{Number: 3, ID: "2", Param: dto.DeliverOrderParam{ID: "2"}},
+				{Number: 4, ID: "3", Param: dto.DeliverOrderParam{ID: "3"}},
+			}
+			service := &fakeService{}
+
+			report := Import(context.Background(), service, rows, tt.dryRun, 2)
+
+			require.Equal(t, []int{2, 1}, service.chunks)
+			require.Equal(t, tt.dryRun, service.checked)
+			require.Equal(t, tt.dryRun, report.DryRun)
+			require.Equal(t, 1, report.Accepted)
+			require.Equal(t, 3, report.Failed)
+			require.NoError(t, report.Rows[0].Err)
+			require.ErrorIs(t, report.Rows[1].Err, ErrUserIsEmpty)
+			require.ErrorIs(t, report.Rows[2].Err, errDuplicate)
+			require.ErrorIs(t, report.Rows[3].Err, errConn)
+		})
+	}
+}
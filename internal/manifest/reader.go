@@ -0,0 +1,198 @@
+package manifest
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"homework/internal/dto"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	CSV  Format = "csv"
+	JSON Format = "json"
+
+	byteOrderMark = "\ufeff"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown manifest format")
+	ErrUnknownColumn = errors.New("unknown column")
+	ErrNoColumnID    = errors.New("id column is missing")
+)
+
+type (
+	Format string
+
+	// Row is an order of the manifest, Number counts the orders from 1 without the header.
+	// Err is the reason the order isn't delivered, Param is valid only without it.
+	Row struct {
+		Number int
+		ID     string
+		Param  dto.DeliverOrderParam
+		Err    error
+	}
+)
+
+// ParseFormat takes csv by default.
+func ParseFormat(value string) (Format, error) {
+	switch Format(value) {
+	case "", CSV:
+		return CSV, nil
+	case JSON:
+		return JSON, nil
+	default:
+		return "", ErrUnknownFormat
+	}
+}
+
+// Read checks every order of the manifest. CSV has a header with the columns of Fields in any order,
+// JSON is an array of Fields objects or the objects one after another, e.g. one per line.
+// An error is returned only if the manifest can't be read further, a wrong order fails its row alone.
+func Read(r io.Reader, format Format) ([]Row, error) {
+	switch format {
+	case CSV:
+		return readCSV(r)
+	case JSON:
+		return readJSON(r)
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+func readCSV(r io.Reader) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		// spreadsheets start the file with the byte order mark
+		column = strings.TrimSpace(strings.TrimPrefix(column, byteOrderMark))
+		switch column {
+		case ColumnID, ColumnUser, ColumnExp, ColumnWrapper, ColumnWeightInKg, ColumnPriceInRub:
+			columns[column] = i
+		default:
+			return nil, fmt.Errorf("%w: %q", ErrUnknownColumn, column)
+		}
+	}
+	if _, ok := columns[ColumnID]; !ok {
+		return nil, ErrNoColumnID
+	}
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		value := func(column string) string {
+			i, ok := columns[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		row := Row{Number: len(rows) + 1, ID: value(ColumnID)}
+		fields, err := csvFields(value)
+		if err == nil {
+			row.Param, err = fields.Param()
+		}
+		row.Err = err
+		rows = append(rows, row)
+	}
+}
+
+func csvFields(value func(column string) string) (Fields, error) {
+	fields := Fields{
+		ID:      value(ColumnID),
+		User:    value(ColumnUser),
+		Exp:     value(ColumnExp),
+		Wrapper: value(ColumnWrapper),
+	}
+
+	var err error
+	if weight := value(ColumnWeightInKg); weight != "" {
+		if fields.WeightInKg, err = strconv.ParseFloat(weight, 64); err != nil {
+			return Fields{}, FieldError{Field: ColumnWeightInKg, Err: ErrWeightInKgInNotValid}
+		}
+	}
+	if price := value(ColumnPriceInRub); price != "" {
+		if fields.PriceInRub, err = strconv.ParseFloat(price, 64); err != nil {
+			return Fields{}, FieldError{Field: ColumnPriceInRub, Err: ErrPriceInRubIsNotValid}
+		}
+	}
+	return fields, nil
+}
+
+func readJSON(r io.Reader) ([]Row, error) {
+	buffered := bufio.NewReader(r)
+	array, err := startsWithArray(buffered)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(buffered)
+	if array {
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+	}
+
+	var rows []Row
+	for decoder.More() {
+		var fields Fields
+		err := decoder.Decode(&fields)
+		// a value of a wrong type fails the order, the decoder goes on with the next one
+		var typeError *json.UnmarshalTypeError
+		if err != nil && !errors.As(err, &typeError) {
+			return nil, err
+		}
+
+		row := Row{Number: len(rows) + 1, ID: fields.ID}
+		if typeError != nil {
+			row.Err = FieldError{Field: typeError.Field, Err: err}
+		} else {
+			row.Param, row.Err = fields.Param()
+		}
+		rows = append(rows, row)
+	}
+
+	if array {
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+	}
+	return rows, nil
+}
+
+func startsWithArray(r *bufio.Reader) (bool, error) {
+	for {
+		c, _, err := r.ReadRune()
+		if errors.Is(err, io.EOF) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if !unicode.IsSpace(c) && string(c) != byteOrderMark {
+			return c == '[', r.UnreadRune()
+		}
+	}
+}
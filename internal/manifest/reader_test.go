@@ -0,0 +1,137 @@
+package manifest
+
+import (
+	"errors"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	type (
+		result struct {
+			id    string
+			field string
+			err   error
+		}
+		test struct {
+			name    string
+			format  Format
+			content string
+			rows    []result
+			err     error
+		}
+	)
+
+	tests := []test{
+		{
+			name:   "csv",
+			format: CSV,
+			content: byteOrderMark + "weight_in_kg, id,user,exp,wrapper,price_in_rub\n" +
+				"1.5,1,2,2024-06-01T12:00:00Z,box,10.5\n" +
+				"1,2,,2024-06-01T12:00:00Z,,10\n" +
+				"one,3,2,2024-06-01T12:00:00Z,,10\n" +
+				"1,4,2,01.06.2024,,10\n" +
+				"1,5,2,2024-06-01T12:00:00Z,bag\n",
+			rows: []result{
+				{id: "1"},
+				{id: "2", field: ColumnUser, err: ErrUserIsEmpty},
+				{id: "3", field: ColumnWeightInKg, err: ErrWeightInKgInNotValid},
+				{id: "4", field: ColumnExp, err: ErrExpIsNotValid},
+				{id: "5", field: ColumnWrapper, err: ErrWrapperIsNotValid},
+			},
+		},
+		{
+			name:   "json array",
+			format: JSON,
+			content: ` [
+				{"id": "1", "user": "2", "exp": "2024-06-01T12:00:00Z", "wrapper": "box", "weight_in_kg": 1.5, "price_in_rub": 10.5},
+				{"id": "2", "user": "2", "exp": "2024-06-01T12:00:00Z", "weight_in_kg": "1"},
+				{"id": "3", "user": "2", "exp": "2024-06-01T12:00:00Z", "weight_in_kg": 1, "price_in_rub": -1}
+			]`,
+			rows: []result{
+				{id: "1"},
+				{id: "2", field: ColumnWeightInKg},
+				{id: "3", field: ColumnPriceInRub, err: ErrPriceInRubIsNotValid},
+			},
+		},
+		{
+			name:   "json lines",
+			format: JSON,
+			content: `{"id": "1", "user": "2", "exp": "2024-06-01T12:00:00Z", "weight_in_kg": 1}` + "\n" +
+				`{"user": "2", "exp": "2024-06-01T12:00:00Z", "weight_in_kg": 1}` + "\n",
+			rows: []result{
+				{id: "1"},
+				{field: ColumnID, err: ErrIdIsEmpty},
+			},
+		},
+		{
+			name:    "empty",
+			format:  CSV,
+			content: "",
+		},
+		{
+			name:    "unknown column",
+			format:  CSV,
+			content: "id,order\n1,2\n",
+			err:     ErrUnknownColumn,
+		},
+		{
+			name:    "no id column",
+			format:  CSV,
+			content: "user,exp\n1,2024-06-01T12:00:00Z\n",
+			err:     ErrNoColumnID,
+		},
+		{
+			name:    "unknown format",
+			format:  "xml",
+			content: "<orders/>",
+			err:     ErrUnknownFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rows, err := Read(strings.NewReader(tt.content), tt.format)
+			require.ErrorIs(t, err, tt.err)
+			require.Len(t, rows, len(tt.rows))
+			for i, row := range rows {
+				require.Equal(t, i+1, row.Number)
+				require.Equal(t, tt.rows[i].id, row.ID)
+				if tt.rows[i].field == "" {
+					require.NoError(t, row.Err)
+					continue
+				}
+				var fieldError FieldError
+				require.True(t, errors.As(row.Err, &fieldError))
+				require.Equal(t, tt.rows[i].field, fieldError.Field)
+				if tt.rows[i].err != nil {
+					require.ErrorIs(t, row.Err, tt.rows[i].err)
+				}
+			}
+		})
+	}
+}
+
+func TestRead_Param(t *testing.T) {
+	t.Parallel()
+
+	rows, err := Read(strings.NewReader("id,user,exp,wrapper,weight_in_kg,price_in_rub\n1,2,2024-06-01T12:00:00Z,box,1.5,10.5\n"), CSV)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+
+	param := rows[0].Param
+	require.Equal(t, "1", param.ID)
+	require.Equal(t, "2", param.RecipientID)
+	require.Equal(t, time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC), param.ExpirationDate)
+	require.Equal(t, float64(1500), param.WeightInGram)
+	require.Equal(t, "10.5", decimal.Decimal(param.PriceInRub).String())
+	require.NotNil(t, param.Wrapper)
+}
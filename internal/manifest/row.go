@@ -0,0 +1,103 @@
+// Package manifest reads the courier's manifests of the orders to deliver and delivers them.
+// A row has the fields of the deliver command and is checked by the same rules.
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"homework/internal/dto"
+	"homework/internal/model"
+	"homework/internal/model/wrapper"
+	"slices"
+	"time"
+)
+
+const (
+	ColumnID         = "id"
+	ColumnUser       = "user"
+	ColumnExp        = "exp"
+	ColumnWrapper    = "wrapper"
+	ColumnWeightInKg = "weight_in_kg"
+	ColumnPriceInRub = "price_in_rub"
+)
+
+var (
+	ErrIdIsEmpty            = errors.New("id is empty")
+	ErrUserIsEmpty          = errors.New("user is empty")
+	ErrExpIsEmpty           = errors.New("exp is empty")
+	ErrExpIsNotValid        = errors.New("exp is not RFC3339")
+	ErrWrapperIsNotValid    = errors.New("wrapper is not valid")
+	ErrWeightInKgInNotValid = errors.New("weight_in_kg is not valid")
+	ErrPriceInRubIsNotValid = errors.New("price_in_rub is not valid")
+)
+
+type (
+	// Fields are the raw values of the order, like the flags of the deliver command.
+	Fields struct {
+		ID         string  `json:"id"`
+		User       string  `json:"user"`
+		Exp        string  `json:"exp"`
+		Wrapper    string  `json:"wrapper"`
+		WeightInKg float64 `json:"weight_in_kg"`
+		PriceInRub float64 `json:"price_in_rub"`
+	}
+
+	// FieldError tells which field of the order is wrong, the message is the one of Err.
+	FieldError struct {
+		Field string
+		Err   error
+	}
+)
+
+// Param checks the fields, the rules of the orders that depend on the time or the stored orders are the service's.
+func (f Fields) Param() (dto.DeliverOrderParam, error) {
+	if f.Exp == "" {
+		return dto.DeliverOrderParam{}, FieldError{Field: ColumnExp, Err: ErrExpIsEmpty}
+	}
+	if f.ID == "" {
+		return dto.DeliverOrderParam{}, FieldError{Field: ColumnID, Err: ErrIdIsEmpty}
+	}
+	if f.User == "" {
+		return dto.DeliverOrderParam{}, FieldError{Field: ColumnUser, Err: ErrUserIsEmpty}
+	}
+	if f.WeightInKg <= 0 {
+		return dto.DeliverOrderParam{}, FieldError{Field: ColumnWeightInKg, Err: ErrWeightInKgInNotValid}
+	}
+	if f.PriceInRub < 0 {
+		return dto.DeliverOrderParam{}, FieldError{Field: ColumnPriceInRub, Err: ErrPriceInRubIsNotValid}
+	}
+
+	priceInRub := wrapper.PriceInRub(decimal.NewFromFloat(f.PriceInRub))
+	wrapperIsEmpty := f.Wrapper == ""
+	if !wrapperIsEmpty && !slices.Contains(wrapper.GetAllWrapperTypes(), wrapper.WrapperType(f.Wrapper)) {
+		return dto.DeliverOrderParam{}, FieldError{Field: ColumnWrapper, Err: ErrWrapperIsNotValid}
+	}
+
+	exp, err := time.Parse(model.TimeFormat, f.Exp)
+	if err != nil {
+		return dto.DeliverOrderParam{}, FieldError{Field: ColumnExp, Err: fmt.Errorf("%w: %v", ErrExpIsNotValid, err)}
+	}
+
+	wrapper, err := wrapper.NewDefaultWrapper(wrapper.WrapperType(f.Wrapper))
+	if !wrapperIsEmpty && err != nil {
+		return dto.DeliverOrderParam{}, FieldError{Field: ColumnWrapper, Err: err}
+	}
+
+	return dto.DeliverOrderParam{
+		ID:             f.ID,
+		RecipientID:    f.User,
+		ExpirationDate: exp,
+		WeightInGram:   f.WeightInKg * 1000,
+		Wrapper:        wrapper,
+		PriceInRub:     priceInRub,
+	}, nil
+}
+
+func (e FieldError) Error() string {
+	return e.Err.Error()
+}
+
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
@@ -3,10 +3,24 @@ package metrics
 import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"homework/pkg/ds"
 )
 
 const (
-	orderLabel = "order"
+	orderLabel  = "order"
+	topicLabel  = "topic"
+	resultLabel = "result"
+	cacheLabel  = "cache"
+	methodLabel = "method"
+	reasonLabel = "reason"
+
+	RateLimited     = "rate"
+	InFlightLimited = "in_flight"
+
+	KafkaMessageSucceeded = "success"
+	KafkaMessageFailed    = "failure"
+	KafkaMessageDropped   = "dropped"
+	KafkaMessageSpilled   = "spilled"
 )
 
 var (
@@ -16,10 +30,90 @@ var (
 	}, []string{
 		orderLabel,
 	})
+
+	kafkaMessages = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_producer_messages_total",
+		Help: "total number of messages handled by kafka producer",
+	}, []string{
+		topicLabel,
+		resultLabel,
+	})
+
+	rejectedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_rejected_requests_total",
+		Help: "total number of requests rejected by rate or in-flight limits",
+	}, []string{
+		methodLabel,
+		reasonLabel,
+	})
+
+	ordersCacheInvalidations = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "orders_cache_invalidations_total",
+		Help: "total number of results removed from orders cache by invalidation",
+	})
 )
 
+// RegisterCache exports the counters of the cache, stats is called on every scrape.
+func RegisterCache(name string, stats func() ds.Stats) {
+	labels := prometheus.Labels{cacheLabel: name}
+
+	promauto.NewCounterFunc(prometheus.CounterOpts{
+		Name:        "cache_hits_total",
+		Help:        "total number of cache hits",
+		ConstLabels: labels,
+	}, func() float64 {
+		return float64(stats().Hits)
+	})
+	promauto.NewCounterFunc(prometheus.CounterOpts{
+		Name:        "cache_misses_total",
+		Help:        "total number of cache misses",
+		ConstLabels: labels,
+	}, func() float64 {
+		return float64(stats().Misses)
+	})
+	promauto.NewCounterFunc(prometheus.CounterOpts{
+		Name:        "cache_evictions_total",
+		Help:        "total number of entries evicted from cache",
+		ConstLabels: labels,
+	}, func() float64 {
+		return float64(stats().Evictions)
+	})
+	promauto.NewCounterFunc(prometheus.CounterOpts{
+		Name:        "cache_expirations_total",
+		Help:        "total number of expired entries removed from cache",
+		ConstLabels: labels,
+	}, func() float64 {
+		return float64(stats().Expirations)
+	})
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Name:        "cache_size",
+		Help:        "number of entries in cache",
+		ConstLabels: labels,
+	}, func() float64 {
+		return float64(stats().Size)
+	})
+}
+
+func AddRejectedRequest(method string, reason string) {
+	rejectedRequests.With(prometheus.Labels{
+		methodLabel: method,
+		reasonLabel: reason,
+	}).Inc()
+}
+
+func AddOrdersCacheInvalidations(count int) {
+	ordersCacheInvalidations.Add(float64(count))
+}
+
 func AddIssuedOrders(count int) {
 	issuedOrders.With(prometheus.Labels{
 		orderLabel: "issued",
 	}).Add(float64(count))
 }
+
+func AddKafkaMessage(topic string, result string) {
+	kafkaMessages.With(prometheus.Labels{
+		topicLabel:  topic,
+		resultLabel: result,
+	}).Inc()
+}
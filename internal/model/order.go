@@ -10,8 +10,10 @@ var (
 	StatusDelivered = Status("delivered")
 	StatusIssued    = Status("issued")
 	StatusRefunded  = Status("refunded")
-	StatusNone      = Status("")
-	TimeFormat      = time.RFC3339
+	// StatusReturned is only reported in events, the order returned to the courier is deleted.
+	StatusReturned = Status("returned")
+	StatusNone     = Status("")
+	TimeFormat     = time.RFC3339
 )
 
 type (
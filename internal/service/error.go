@@ -2,29 +2,77 @@ package service
 
 import (
 	"errors"
+	"fmt"
+)
+
+const (
+	// KindInvalidArgument is a request that is wrong whatever the state of the orders is.
+	KindInvalidArgument ErrorKind = iota
+	// KindFailedPrecondition is a valid request the orders aren't in the state for.
+	KindFailedPrecondition
 )
 
 var (
-	ErrOrderInPVZ                            = newError(errors.New("заказ находится в пвз"))
-	ErrRefundPeriodHasExpired                = newError(errors.New("заказ не может быть возвращен более чем через два дня"))
-	ErrOrderHasNotExpired                    = newError(errors.New("у заказа ещё не вышел срок хранения"))
-	ErrOrderHasExpired                       = newError(errors.New("у заказа вышел срок хранения"))
-	ErrOrderHasAlreadyBeenIssued             = newError(errors.New("заказ уже выдан"))
-	ErrExtraIDsInTheRequest                  = newError(errors.New("в запросе присутствуют лишние id"))
-	ErrExpIsNotValid                         = newError(errors.New("expiration date is not valid"))
-	ErrOrdersBelongToDifferentUsers          = newError(errors.New("orders belong to different users"))
-	ErrMustBeAtLeastOneOrder                 = newError(errors.New("must be at least one order"))
-	ErrOrderWeightGreaterThanWrapperCapacity = newError(errors.New("order weight is greater than the wrapper capacity"))
+	ErrOrderInPVZ                            = newError("ORDER_NOT_ISSUED", KindFailedPrecondition, errors.New("заказ находится в пвз"))
+	ErrRefundPeriodHasExpired                = newError("REFUND_PERIOD_EXPIRED", KindFailedPrecondition, errors.New("заказ не может быть возвращен более чем через два дня"))
+	ErrOrderHasNotExpired                    = newError("ORDER_NOT_EXPIRED", KindFailedPrecondition, errors.New("у заказа ещё не вышел срок хранения"))
+	ErrOrderHasExpired                       = newError("ORDER_EXPIRED", KindFailedPrecondition, errors.New("у заказа вышел срок хранения"))
+	ErrOrderHasAlreadyBeenIssued             = newError("ORDER_ALREADY_ISSUED", KindFailedPrecondition, errors.New("заказ уже выдан"))
+	ErrExtraIDsInTheRequest                  = newError("ORDERS_NOT_DELIVERED", KindFailedPrecondition, errors.New("в запросе присутствуют лишние id"))
+	ErrExpIsNotValid                         = newError("EXPIRATION_DATE_IN_PAST", KindInvalidArgument, errors.New("expiration date is not valid"))
+	ErrOrdersBelongToDifferentUsers          = newError("DIFFERENT_RECIPIENTS", KindFailedPrecondition, errors.New("orders belong to different users"))
+	ErrMustBeAtLeastOneOrder                 = newError("NO_ORDERS", KindInvalidArgument, errors.New("must be at least one order"))
+	ErrOrderWeightGreaterThanWrapperCapacity = newError("WRAPPER_CAPACITY_EXCEEDED", KindInvalidArgument, errors.New("order weight is greater than the wrapper capacity"))
+	ErrWrongRecipient                        = newError("WRONG_RECIPIENT", KindFailedPrecondition, errors.New("заказ принадлежит другому получателю"))
+	ErrExportPeriodIsNotValid                = newError("EXPORT_PERIOD_NOT_VALID", KindInvalidArgument, errors.New("export period start is not before its end"))
 )
 
-type OrderServiceError struct {
-	err error
-}
+// errDryRun rolls back the transaction of CheckDeliverOrders.
+var errDryRun = errors.New("dry run")
+
+type (
+	ErrorKind int
 
-func newError(err error) OrderServiceError {
-	return OrderServiceError{err: err}
+	// OrderServiceError has a stable reason clients can rely on instead of the message.
+	OrderServiceError struct {
+		err    error
+		reason string
+		kind   ErrorKind
+		// orderID is the order the error is about, empty if it's about the whole request.
+		orderID string
+	}
+)
+
+func newError(reason string, kind ErrorKind, err error) OrderServiceError {
+	return OrderServiceError{err: err, reason: reason, kind: kind}
 }
 
 func (o OrderServiceError) Error() string {
+	if o.orderID != "" {
+		return fmt.Sprintf("%s: id = %s", o.err.Error(), o.orderID)
+	}
 	return o.err.Error()
 }
+
+// Is matches the error with its order id to the error without it.
+func (o OrderServiceError) Is(target error) bool {
+	t, ok := target.(OrderServiceError)
+	return ok && t.reason == o.reason
+}
+
+func (o OrderServiceError) Reason() string {
+	return o.reason
+}
+
+func (o OrderServiceError) Kind() ErrorKind {
+	return o.kind
+}
+
+func (o OrderServiceError) OrderID() string {
+	return o.orderID
+}
+
+func (o OrderServiceError) WithOrderID(id string) OrderServiceError {
+	o.orderID = id
+	return o
+}
@@ -10,11 +10,23 @@ import (
 
 // orderService ...
 type orderService interface {
-	Deliver(ctx context.Context, order dto.DeliverOrderParam) error
+	Deliver(ctx context.Context, param dto.DeliverOrderParam) error
+	// DeliverOrders delivers the orders in one transaction, errs[i] is the result of params[i].
+	// err is returned only if the transaction has failed, then none of the orders is delivered.
+	DeliverOrders(ctx context.Context, params []dto.DeliverOrderParam) ([]error, error)
+	// CheckDeliverOrders tells what DeliverOrders would return: the orders are added in a transaction
+	// that is rolled back, so the stored orders are checked too, but nothing is delivered.
+	CheckDeliverOrders(ctx context.Context, params []dto.DeliverOrderParam) ([]error, error)
 	ListUserOrders(ctx context.Context, param dto.ListUserOrdersParam) ([]model.Order, error)
 	ListOrders(ctx context.Context, param dto.ListOrdersParam) ([]model.Order, error)
+	GetOrder(ctx context.Context, id string) (model.Order, error)
+	// GetOrders returns the orders found by ids in any status, missing ids are skipped.
+	GetOrders(ctx context.Context, ids []string) ([]model.Order, error)
 	RefundedOrders(ctx context.Context, param dto.PageParam) ([]model.Order, error)
 	ReturnOrder(ctx context.Context, id string) error
 	IssueOrders(ctx context.Context, ids []string) error
 	RefundOrder(ctx context.Context, param dto.RefundOrderParam) error
+	// ExportOrders passes the orders to write by batch. The batches are read in one transaction,
+	// so the export is consistent however long the writing takes.
+	ExportOrders(ctx context.Context, param dto.ExportOrdersParam, write func([]dto.ExportedOrder) error) error
 }
@@ -11,27 +11,33 @@ import (
 	"homework/internal/model"
 	"homework/internal/model/wrapper"
 	hash2 "homework/pkg/hash"
+	"sync"
 	"time"
 )
 
 const (
 	refundPeriod = time.Hour * 24 * 2
+	// exportBatch is how many orders are fetched from the export cursor at a time.
+	exportBatch = 1000
 )
 
 type (
 	orderStorage interface {
 		ListUserOrders(ctx context.Context, id string, count uint, status model.Status) ([]model.Order, error)
 		AddOrder(ctx context.Context, order model.Order, hash string) error
+		AddOrders(ctx context.Context, orders []model.Order, hashes []string) ([]error, error)
 		ListOrdersByIds(ctx context.Context, ids []string, status model.Status) ([]model.Order, error)
 		UpdateStatus(ctx context.Context, ids dto.IdsWithHashes, status model.Status) error
 		GetOrderById(ctx context.Context, id string) (model.Order, error)
 		DeleteOrder(ctx context.Context, id string) error
 		RefundedOrders(ctx context.Context, get dto.PageParam) ([]model.Order, error)
 		ListOrders(ctx context.Context, get dto.ListOrdersParam) ([]model.Order, error)
+		ExportOrders(ctx context.Context, param dto.ExportOrdersParam, batch uint, write func([]dto.ExportedOrder) error) error
 	}
 
 	wrapperStorage interface {
 		AddWrapper(ctx context.Context, order wrapper.Wrapper, orderID string) error
+		AddWrappers(ctx context.Context, wrappers []wrapper.Wrapper, orderIDs []string) error
 		Delete(ctx context.Context, orderID string) error
 	}
 
@@ -40,16 +46,24 @@ type (
 		Unwrap(err error) error
 	}
 
+	// eventPublisher is told about the changed orders after the transaction is committed.
+	eventPublisher interface {
+		Publish(ctx context.Context, events []dto.OrderEvent)
+	}
+
 	Deps struct {
 		Storage            orderStorage
 		TransactionManager transactionManager
 		WrapperStorage     wrapperStorage
+		// Events is optional.
+		Events eventPublisher
 	}
 
 	OrderService struct {
 		orderStorage       orderStorage
 		transactionManager transactionManager
 		wrapperStorage     wrapperStorage
+		events             eventPublisher
 	}
 )
 
@@ -58,6 +72,7 @@ func NewOrder(d Deps) OrderService {
 		orderStorage:       d.Storage,
 		transactionManager: d.TransactionManager,
 		wrapperStorage:     d.WrapperStorage,
+		events:             d.Events,
 	}
 }
 
@@ -71,29 +86,13 @@ func (o *OrderService) Deliver(ctx context.Context, param dto.DeliverOrderParam)
 }
 
 func (o *OrderService) deliver(ctx context.Context, param dto.DeliverOrderParam, hash string) error {
-	if param.ExpirationDate.Before(time.Now()) {
-		return ErrExpIsNotValid
-	}
-	if param.Wrapper != nil && !param.Wrapper.WillFitGram(param.WeightInGram) {
-		message := fmt.Sprintf("capacity_in_gram = %v", param.Wrapper.GetCapacityInGram())
-		return errors.Wrap(ErrOrderWeightGreaterThanWrapperCapacity, message)
-	}
-
-	wrapperPriceInRub := wrapper.PriceInRub(decimal.NewFromInt(0))
-	if param.Wrapper != nil {
-		wrapperPriceInRub = param.Wrapper.GetPriceInRub()
+	order, err := newDeliveredOrder(param)
+	if err != nil {
+		return err
 	}
 
-	err := o.transactionManager.RunRepeatableRead(ctx, func(ctx context.Context) error {
-		err := o.orderStorage.AddOrder(ctx, model.Order{
-			ID:              param.ID,
-			RecipientID:     param.RecipientID,
-			Status:          model.StatusDelivered,
-			StatusUpdatedAt: time.Now(),
-			ExpirationDate:  param.ExpirationDate,
-			WeightInGram:    param.WeightInGram,
-			PriceInRub:      param.PriceInRub.Add(wrapperPriceInRub),
-		}, hash)
+	err = o.transactionManager.RunRepeatableRead(ctx, func(ctx context.Context) error {
+		err := o.orderStorage.AddOrder(ctx, order, hash)
 		if err != nil {
 			return err
 		}
@@ -104,8 +103,132 @@ func (o *OrderService) deliver(ctx context.Context, param dto.DeliverOrderParam,
 
 		return o.wrapperStorage.AddWrapper(ctx, *param.Wrapper, param.ID)
 	})
+	if err := o.transactionManager.Unwrap(err); err != nil {
+		return err
+	}
 
-	return o.transactionManager.Unwrap(err)
+	o.notify(ctx, model.StatusDelivered, order)
+	return nil
+}
+
+// DeliverOrders delivers the orders in one transaction, errs[i] is the result of params[i].
+// err is returned only if the transaction has failed, then none of the orders is delivered.
+func (o *OrderService) DeliverOrders(ctx context.Context, params []dto.DeliverOrderParam) ([]error, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.OrderService.DeliverOrders")
+	defer span.Finish()
+
+	return o.deliverOrders(ctx, params, generateHashes(len(params)), false)
+}
+
+// CheckDeliverOrders tells what DeliverOrders would return: the orders are added in a transaction
+// that is rolled back, so the stored orders are checked too, but nothing is delivered.
+func (o *OrderService) CheckDeliverOrders(ctx context.Context, params []dto.DeliverOrderParam) ([]error, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.OrderService.CheckDeliverOrders")
+	defer span.Finish()
+
+	// the hashes aren't stored, so they aren't generated
+	return o.deliverOrders(ctx, params, make([]string, len(params)), true)
+}
+
+// generateHashes generates the hashes concurrently, a hash takes seconds.
+func generateHashes(n int) []string {
+	hashes := make([]string, n)
+	var wg sync.WaitGroup
+	for i := range hashes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			hashes[i] = hash2.GenerateHash()
+		}(i)
+	}
+	wg.Wait()
+	return hashes
+}
+
+func (o *OrderService) deliverOrders(ctx context.Context, params []dto.DeliverOrderParam, hashes []string, dryRun bool) ([]error, error) {
+	errs := make([]error, len(params))
+	orders := make([]model.Order, 0, len(params))
+	orderHashes := make([]string, 0, len(params))
+	// indexes[j] is the index of orders[j] in params
+	indexes := make([]int, 0, len(params))
+	for i, param := range params {
+		order, err := newDeliveredOrder(param)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		orders = append(orders, order)
+		orderHashes = append(orderHashes, hashes[i])
+		indexes = append(indexes, i)
+	}
+	if len(orders) == 0 {
+		return errs, nil
+	}
+
+	var delivered []model.Order
+	err := o.transactionManager.RunRepeatableRead(ctx, func(ctx context.Context) error {
+		addErrs, err := o.orderStorage.AddOrders(ctx, orders, orderHashes)
+		if err != nil {
+			return err
+		}
+
+		var wrappers []wrapper.Wrapper
+		var wrapperOrderIDs []string
+		for j, addErr := range addErrs {
+			param := params[indexes[j]]
+			errs[indexes[j]] = addErr
+			if addErr == nil {
+				delivered = append(delivered, orders[j])
+			}
+			if addErr == nil && param.Wrapper != nil {
+				wrappers = append(wrappers, *param.Wrapper)
+				wrapperOrderIDs = append(wrapperOrderIDs, param.ID)
+			}
+		}
+		if len(wrappers) != 0 {
+			if err := o.wrapperStorage.AddWrappers(ctx, wrappers, wrapperOrderIDs); err != nil {
+				return err
+			}
+		}
+		// the rollback drops the cache invalidation of the added orders too, it's published only after a commit
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err := o.transactionManager.Unwrap(err); err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+
+	if !dryRun {
+		o.notify(ctx, model.StatusDelivered, delivered...)
+	}
+	return errs, nil
+}
+
+func newDeliveredOrder(param dto.DeliverOrderParam) (model.Order, error) {
+	if param.ExpirationDate.Before(time.Now()) {
+		return model.Order{}, ErrExpIsNotValid.WithOrderID(param.ID)
+	}
+	if param.Wrapper != nil && !param.Wrapper.WillFitGram(param.WeightInGram) {
+		message := fmt.Sprintf("capacity_in_gram = %v", param.Wrapper.GetCapacityInGram())
+		return model.Order{}, errors.Wrap(ErrOrderWeightGreaterThanWrapperCapacity.WithOrderID(param.ID), message)
+	}
+
+	wrapperPriceInRub := wrapper.PriceInRub(decimal.NewFromInt(0))
+	if param.Wrapper != nil {
+		wrapperPriceInRub = param.Wrapper.GetPriceInRub()
+	}
+
+	return model.Order{
+		ID:              param.ID,
+		RecipientID:     param.RecipientID,
+		Status:          model.StatusDelivered,
+		StatusUpdatedAt: time.Now(),
+		ExpirationDate:  param.ExpirationDate,
+		WeightInGram:    param.WeightInGram,
+		PriceInRub:      param.PriceInRub.Add(wrapperPriceInRub),
+	}, nil
 }
 
 func (o *OrderService) ListUserOrders(ctx context.Context, param dto.ListUserOrdersParam) ([]model.Order, error) {
@@ -132,6 +255,21 @@ func (o *OrderService) listOrders(ctx context.Context, param dto.ListOrdersParam
 	return o.orderStorage.ListOrders(ctx, param)
 }
 
+func (o *OrderService) GetOrder(ctx context.Context, id string) (model.Order, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.OrderService.GetOrder")
+	defer span.Finish()
+
+	return o.orderStorage.GetOrderById(ctx, id)
+}
+
+// GetOrders returns the orders found by ids in any status, missing ids are skipped.
+func (o *OrderService) GetOrders(ctx context.Context, ids []string) ([]model.Order, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.OrderService.GetOrders")
+	defer span.Finish()
+
+	return o.orderStorage.ListOrdersByIds(ctx, ids, model.StatusNone)
+}
+
 func (o *OrderService) RefundedOrders(ctx context.Context, param dto.PageParam) ([]model.Order, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.OrderService.RefundedOrders")
 	defer span.Finish()
@@ -154,17 +292,19 @@ func (o *OrderService) ReturnOrder(ctx context.Context, id string) error {
 }
 
 func (o *OrderService) returnOrder(ctx context.Context, id string) error {
+	var returned model.Order
 	err := o.transactionManager.RunRepeatableRead(ctx, func(ctx context.Context) error {
 		order, err := o.orderStorage.GetOrderById(ctx, id)
 		if err != nil {
 			return err
 		}
+		returned = order
 
 		if order.Status != model.StatusDelivered {
-			return ErrOrderHasAlreadyBeenIssued
+			return ErrOrderHasAlreadyBeenIssued.WithOrderID(id)
 		}
 		if !order.ExpirationDate.Before(time.Now()) {
-			return ErrOrderHasNotExpired
+			return ErrOrderHasNotExpired.WithOrderID(id)
 		}
 
 		err = o.wrapperStorage.Delete(ctx, id)
@@ -174,7 +314,12 @@ func (o *OrderService) returnOrder(ctx context.Context, id string) error {
 
 		return o.orderStorage.DeleteOrder(ctx, id)
 	})
-	return o.transactionManager.Unwrap(err)
+	if err := o.transactionManager.Unwrap(err); err != nil {
+		return err
+	}
+
+	o.notify(ctx, model.StatusReturned, returned)
+	return nil
 }
 
 func (o *OrderService) IssueOrders(ctx context.Context, ids []string) error {
@@ -190,11 +335,13 @@ func (o *OrderService) IssueOrders(ctx context.Context, ids []string) error {
 }
 
 func (o *OrderService) issueOrders(ctx context.Context, ids []string, hashes dto.IdsWithHashes) error {
+	var issued []model.Order
 	err := o.transactionManager.RunRepeatableRead(ctx, func(ctx context.Context) error {
 		orders, err := o.orderStorage.ListOrdersByIds(ctx, ids, model.StatusDelivered)
 		if err != nil {
 			return err
 		}
+		issued = orders
 
 		if len(orders) < len(ids) {
 			return ErrExtraIDsInTheRequest
@@ -207,17 +354,22 @@ func (o *OrderService) issueOrders(ctx context.Context, ids []string, hashes dto
 		recipientId := orders[0].RecipientID
 		for _, order := range orders {
 			if recipientId != order.RecipientID {
-				return ErrOrdersBelongToDifferentUsers
+				return ErrOrdersBelongToDifferentUsers.WithOrderID(order.ID)
 			}
 			if !order.ExpirationDate.Before(time.Now()) {
 				continue
 			}
-			return errors.Wrapf(ErrOrderHasExpired, fmt.Sprintf("id = %s", order.ID))
+			return ErrOrderHasExpired.WithOrderID(order.ID)
 		}
 
 		return o.orderStorage.UpdateStatus(ctx, hashes, model.StatusIssued)
 	})
-	return o.transactionManager.Unwrap(err)
+	if err := o.transactionManager.Unwrap(err); err != nil {
+		return err
+	}
+
+	o.notify(ctx, model.StatusIssued, issued...)
+	return nil
 }
 
 func (o *OrderService) RefundOrder(ctx context.Context, param dto.RefundOrderParam) error {
@@ -233,21 +385,68 @@ func (o *OrderService) RefundOrder(ctx context.Context, param dto.RefundOrderPar
 }
 
 func (o *OrderService) refundOrder(ctx context.Context, param dto.RefundOrderParam, hashes dto.IdsWithHashes) error {
+	var refunded model.Order
 	err := o.transactionManager.RunRepeatableRead(ctx, func(ctx context.Context) error {
 		order, err := o.orderStorage.GetOrderById(ctx, param.ID)
 		if err != nil {
 			return err
 		}
+		refunded = order
+
+		// the recipient of another order learns nothing about its status
+		if order.RecipientID != param.RecipientID {
+			return ErrWrongRecipient.WithOrderID(param.ID)
+		}
 
 		if order.Status != model.StatusIssued {
-			return ErrOrderInPVZ
+			return ErrOrderInPVZ.WithOrderID(param.ID)
 		}
 
 		if time.Now().Sub(order.StatusUpdatedAt) > refundPeriod {
-			return ErrRefundPeriodHasExpired
+			return ErrRefundPeriodHasExpired.WithOrderID(param.ID)
 		}
 
 		return o.orderStorage.UpdateStatus(ctx, hashes, model.StatusRefunded)
 	})
+	if err := o.transactionManager.Unwrap(err); err != nil {
+		return err
+	}
+
+	o.notify(ctx, model.StatusRefunded, refunded)
+	return nil
+}
+
+// ExportOrders passes the orders to write by batch. The batches are read in one transaction,
+// so the export is consistent however long the writing takes.
+func (o *OrderService) ExportOrders(ctx context.Context, param dto.ExportOrdersParam, write func([]dto.ExportedOrder) error) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.OrderService.ExportOrders")
+	defer span.Finish()
+
+	if !param.From.IsZero() && !param.To.IsZero() && !param.From.Before(param.To) {
+		return ErrExportPeriodIsNotValid
+	}
+
+	err := o.transactionManager.RunRepeatableRead(ctx, func(ctx context.Context) error {
+		return o.orderStorage.ExportOrders(ctx, param, exportBatch, write)
+	})
 	return o.transactionManager.Unwrap(err)
 }
+
+// notify doesn't fail the change, the watchers that have missed the events resume from the last one they've received.
+func (o *OrderService) notify(ctx context.Context, status model.Status, orders ...model.Order) {
+	if o.events == nil || len(orders) == 0 {
+		return
+	}
+
+	occurredAt := time.Now()
+	events := make([]dto.OrderEvent, 0, len(orders))
+	for _, order := range orders {
+		events = append(events, dto.OrderEvent{
+			OrderID:     order.ID,
+			RecipientID: order.RecipientID,
+			Status:      status,
+			OccurredAt:  occurredAt,
+		})
+	}
+	o.events.Publish(ctx, events)
+}
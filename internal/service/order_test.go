@@ -2,12 +2,14 @@ package service
 
 import (
 	"context"
+	"errors"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"homework/internal/dto"
 	"homework/internal/model"
 	"homework/internal/model/wrapper"
+	"homework/internal/storage"
 	mock_repository "homework/internal/storage/mocks"
 	mock_transactor "homework/internal/storage/transactor/mocks"
 	"testing"
@@ -130,6 +132,211 @@ func TestOrderService_Deliver(t *testing.T) {
 	}
 }
 
+func TestOrderService_DeliverOrders(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx    = context.Background()
+		exp    = time.Now().Add(time.Minute * 10)
+		box    = wrapper.NewWrapper("box", 20, wrapper.PriceInRub(decimal.NewFromInt(20)))
+		price  = wrapper.PriceInRub(decimal.NewFromInt(10))
+		errDB  = errors.New("connection refused")
+		params = []dto.DeliverOrderParam{
+			{ID: "1", RecipientID: "1", ExpirationDate: time.Now().Add(-time.Minute), PriceInRub: price},
+			{ID: "2", RecipientID: "1", ExpirationDate: exp, Wrapper: box, WeightInGram: 10, PriceInRub: price},
+			{ID: "3", RecipientID: "2", ExpirationDate: exp, WeightInGram: 10, PriceInRub: price},
+			{ID: "4", RecipientID: "2", ExpirationDate: exp, Wrapper: box, WeightInGram: 30, PriceInRub: price},
+		}
+		runInTx = func(m mocks) {
+			m.mockTransactor.EXPECT().RunRepeatableRead(gomock.Any(), gomock.Any()).Times(1).
+				DoAndReturn(func(ctx context.Context, transaction func(ctx context.Context) error) error {
+					return transaction(ctx)
+				})
+		}
+		ids = func(orders []model.Order) []string {
+			result := make([]string, 0, len(orders))
+			for _, order := range orders {
+				result = append(result, order.ID)
+			}
+			return result
+		}
+	)
+
+	type test struct {
+		name   string
+		input  []dto.DeliverOrderParam
+		errs   []error
+		err    error
+		mockFn func(m mocks)
+	}
+
+	tests := []test{
+		{
+			name:  "invalid and duplicate orders fail alone",
+			input: params,
+			errs:  []error{ErrExpIsNotValid, nil, storage.ErrDuplicateOrderID, ErrOrderWeightGreaterThanWrapperCapacity},
+			mockFn: func(m mocks) {
+				runInTx(m)
+				m.mockOrderRepository.EXPECT().AddOrders(gomock.Any(), gomock.Any(), gomock.Len(2)).Times(1).
+					DoAndReturn(func(ctx context.Context, orders []model.Order, hashes []string) ([]error, error) {
+						require.Equal(t, []string{"2", "3"}, ids(orders))
+						require.True(t, decimal.NewFromInt(30).Equal(decimal.Decimal(orders[0].PriceInRub)))
+						return []error{nil, storage.ErrDuplicateOrderID}, nil
+					})
+				m.mockWrapperRepository.EXPECT().AddWrappers(gomock.Any(), []wrapper.Wrapper{*box}, []string{"2"}).Return(nil).Times(1)
+				m.mockTransactor.EXPECT().Unwrap(nil).Times(1).Return(nil)
+			},
+		},
+		{
+			name:  "failed transaction",
+			input: params[1:3],
+			err:   errDB,
+			mockFn: func(m mocks) {
+				runInTx(m)
+				m.mockOrderRepository.EXPECT().AddOrders(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errDB).Times(1)
+				m.mockTransactor.EXPECT().Unwrap(errDB).Times(1).Return(errDB)
+			},
+		},
+		{
+			name:  "no valid orders",
+			input: params[:1],
+			errs:  []error{ErrExpIsNotValid},
+			mockFn: func(m mocks) {
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mocks := newMocks(t)
+			tt.mockFn(mocks)
+			orderService := NewOrder(Deps{
+				WrapperStorage:     mocks.mockWrapperRepository,
+				Storage:            mocks.mockOrderRepository,
+				TransactionManager: mocks.mockTransactor,
+			})
+
+			errs, err := orderService.deliverOrders(ctx, tt.input, make([]string, len(tt.input)), false)
+
+			require.ErrorIs(t, err, tt.err)
+			require.Len(t, errs, len(tt.errs))
+			for i := range tt.errs {
+				require.ErrorIs(t, errs[i], tt.errs[i])
+			}
+		})
+	}
+}
+
+func TestOrderService_CheckDeliverOrders(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mocks := newMocks(t)
+	var published events
+	orderService := NewOrder(Deps{
+		WrapperStorage:     mocks.mockWrapperRepository,
+		Storage:            mocks.mockOrderRepository,
+		TransactionManager: mocks.mockTransactor,
+		Events:             &published,
+	})
+	params := []dto.DeliverOrderParam{
+		{ID: "1", RecipientID: "1", ExpirationDate: time.Now().Add(time.Hour)},
+		{ID: "2", RecipientID: "1", ExpirationDate: time.Now().Add(time.Hour)},
+	}
+
+	var txErr error
+	mocks.mockTransactor.EXPECT().RunRepeatableRead(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(ctx context.Context, transaction func(ctx context.Context) error) error {
+			txErr = transaction(ctx)
+			return txErr
+		})
+	mocks.mockOrderRepository.EXPECT().AddOrders(gomock.Any(), gomock.Len(2), []string{"", ""}).
+		Return([]error{nil, storage.ErrDuplicateOrderID}, nil).Times(1)
+	mocks.mockTransactor.EXPECT().Unwrap(gomock.Any()).DoAndReturn(func(err error) error { return err }).Times(1)
+
+	errs, err := orderService.CheckDeliverOrders(ctx, params)
+	require.NoError(t, err)
+	require.Equal(t, []error{nil, storage.ErrDuplicateOrderID}, errs)
+	// the transaction is rolled back and nobody is told about the orders
+	require.ErrorIs(t, txErr, errDryRun)
+	require.Empty(t, published)
+}
+
+type events []dto.OrderEvent
+
+func (e *events) Publish(_ context.Context, published []dto.OrderEvent) {
+	*e = append(*e, published...)
+}
+
+func TestOrderService_Events(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mocks := newMocks(t)
+	var published events
+	orderService := NewOrder(Deps{
+		WrapperStorage:     mocks.mockWrapperRepository,
+		Storage:            mocks.mockOrderRepository,
+		TransactionManager: mocks.mockTransactor,
+		Events:             &published,
+	})
+	mocks.mockTransactor.EXPECT().RunRepeatableRead(gomock.Any(), gomock.Any()).Times(2).
+		DoAndReturn(func(ctx context.Context, transaction func(ctx context.Context) error) error {
+			return transaction(ctx)
+		})
+
+	mocks.mockOrderRepository.EXPECT().ListOrdersByIds(gomock.Any(), []string{"1", "2"}, model.StatusDelivered).Return([]model.Order{
+		{ID: "1", RecipientID: "1", ExpirationDate: time.Now().Add(time.Hour)},
+		{ID: "2", RecipientID: "1", ExpirationDate: time.Now().Add(time.Hour)},
+	}, nil).Times(1)
+	mocks.mockOrderRepository.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), model.StatusIssued).Return(nil).Times(1)
+	mocks.mockTransactor.EXPECT().Unwrap(nil).Return(nil).Times(1)
+
+	err := orderService.issueOrders(ctx, []string{"1", "2"}, dto.IdsWithHashes{})
+	require.NoError(t, err)
+	require.Len(t, published, 2)
+	require.Equal(t, "2", published[1].OrderID)
+	require.Equal(t, "1", published[1].RecipientID)
+	require.Equal(t, model.StatusIssued, published[1].Status)
+
+	mocks.mockOrderRepository.EXPECT().GetOrderById(gomock.Any(), "3").Return(model.Order{ID: "3", RecipientID: "2"}, nil).Times(1)
+	mocks.mockTransactor.EXPECT().Unwrap(gomock.Any()).DoAndReturn(func(err error) error { return err }).Times(1)
+
+	err = orderService.refundOrder(ctx, dto.RefundOrderParam{ID: "3", RecipientID: "1"}, dto.IdsWithHashes{})
+	require.ErrorIs(t, err, ErrWrongRecipient)
+	require.Len(t, published, 2)
+}
+
+func TestOrderService_ExportOrders(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mocks := newMocks(t)
+	orderService := NewOrder(Deps{
+		WrapperStorage:     mocks.mockWrapperRepository,
+		Storage:            mocks.mockOrderRepository,
+		TransactionManager: mocks.mockTransactor,
+	})
+	now := time.Now()
+	write := func([]dto.ExportedOrder) error { return nil }
+
+	err := orderService.ExportOrders(ctx, dto.ExportOrdersParam{From: now, To: now}, write)
+	require.ErrorIs(t, err, ErrExportPeriodIsNotValid)
+
+	param := dto.ExportOrdersParam{Status: model.StatusIssued, From: now, To: now.Add(time.Hour)}
+	mocks.mockTransactor.EXPECT().RunRepeatableRead(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(ctx context.Context, transaction func(ctx context.Context) error) error {
+			return transaction(ctx)
+		})
+	mocks.mockOrderRepository.EXPECT().ExportOrders(gomock.Any(), param, uint(exportBatch), gomock.Any()).Return(nil).Times(1)
+	mocks.mockTransactor.EXPECT().Unwrap(nil).Return(nil).Times(1)
+
+	require.NoError(t, orderService.ExportOrders(ctx, param, write))
+}
+
 func TestOrderService_ReturnOrder(t *testing.T) {
 	t.Parallel()
 
@@ -236,7 +443,7 @@ func TestOrderService_RefundOrder(t *testing.T) {
 			input: dto.RefundOrderParam{ID: "1", RecipientID: "1"},
 			mockFn: func(m mocks) {
 				m.mockOrderRepository.EXPECT().GetOrderById(gomock.Any(), gomock.Any()).
-					Times(1).Return(model.Order{Status: model.StatusDelivered}, nil)
+					Times(1).Return(model.Order{RecipientID: "1", Status: model.StatusDelivered}, nil)
 				m.mockTransactor.EXPECT().RunRepeatableRead(gomock.Any(), gomock.Any()).Times(1).
 					DoAndReturn(func(ctx context.Context, transaction func(ctx context.Context) error) error {
 						err := transaction(ctx)
@@ -246,12 +453,28 @@ func TestOrderService_RefundOrder(t *testing.T) {
 			},
 			err: ErrOrderInPVZ,
 		},
+		{
+			name:  "wrong recipient",
+			input: dto.RefundOrderParam{ID: "1", RecipientID: "2"},
+			mockFn: func(m mocks) {
+				m.mockOrderRepository.EXPECT().GetOrderById(gomock.Any(), gomock.Any()).
+					Times(1).Return(model.Order{RecipientID: "1", Status: model.StatusIssued, StatusUpdatedAt: time.Now()}, nil)
+				m.mockTransactor.EXPECT().RunRepeatableRead(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(ctx context.Context, transaction func(ctx context.Context) error) error {
+						err := transaction(ctx)
+						m.mockTransactor.EXPECT().Unwrap(gomock.Any()).Times(1).Return(err)
+						return err
+					})
+			},
+			err: ErrWrongRecipient,
+		},
 		{
 			name:  "refund period has expired",
 			input: dto.RefundOrderParam{ID: "1", RecipientID: "1"},
 			mockFn: func(m mocks) {
 				m.mockOrderRepository.EXPECT().GetOrderById(gomock.Any(), gomock.Any()).
 					Times(1).Return(model.Order{
+					RecipientID:     "1",
 					Status:          model.StatusIssued,
 					StatusUpdatedAt: time.Now().Add(-2 * refundPeriod),
 				}, nil)
@@ -270,6 +493,7 @@ func TestOrderService_RefundOrder(t *testing.T) {
 			mockFn: func(m mocks) {
 				m.mockOrderRepository.EXPECT().GetOrderById(gomock.Any(), gomock.Any()).
 					Times(1).Return(model.Order{
+					RecipientID:     "1",
 					Status:          model.StatusIssued,
 					StatusUpdatedAt: time.Now(),
 				}, nil)
@@ -0,0 +1,18 @@
+package storage
+
+// maxQueryParams is the limit of the bind parameters of one postgres statement.
+const maxQueryParams = 65535
+
+// insertParts splits the records, so that each part fits in one multi-row insert.
+func insertParts[T any](records []T, columns int) [][]T {
+	size := maxQueryParams / columns
+	parts := make([][]T, 0, len(records)/size+1)
+	for len(records) > size {
+		parts = append(parts, records[:size])
+		records = records[size:]
+	}
+	if len(records) != 0 {
+		parts = append(parts, records)
+	}
+	return parts
+}
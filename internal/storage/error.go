@@ -9,6 +9,8 @@ import (
 var (
 	ErrNotFound         = errors.New("not found")
 	ErrDuplicateOrderID = errors.New("duplicate order id")
+
+	ErrWrappersDifferentLength = errors.New("wrappers and order ids of different length")
 )
 
 func isDuplicateKeyError(err error) bool {
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/opentracing/opentracing-go"
+	"homework/internal/dto"
+	"homework/internal/storage/schema"
+)
+
+const exportCursor = "orders_export"
+
+// ExportOrders fetches the orders ordered by the delivery time from a server-side cursor and passes
+// them to write by batch, so the export isn't loaded into memory. The cursor lives until the end of
+// the transaction, ctx must have one.
+func (s *OrderStorage) ExportOrders(ctx context.Context, param dto.ExportOrdersParam, batch uint, write func([]dto.ExportedOrder) error) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "storage.OrderStorage.ExportOrders")
+	defer span.Finish()
+
+	columns := append(schema.Wrapper{}.SelectColumns(), schema.Order{}.SelectColumns()...)
+	query := sq.Select(columns...).
+		From(orderTable).
+		LeftJoin("ozon.wrappers on wrappers.order_id = orders.id").
+		OrderBy("created_at", "id").
+		PlaceholderFormat(sq.Dollar)
+
+	if param.Status != "" {
+		query = query.Where(sq.Eq{"status": param.Status})
+	}
+	if param.RecipientID != "" {
+		query = query.Where(sq.Eq{"recipient_id": param.RecipientID})
+	}
+	if !param.From.IsZero() {
+		query = query.Where(sq.GtOrEq{"created_at": param.From})
+	}
+	if !param.To.IsZero() {
+		query = query.Where(sq.Lt{"created_at": param.To})
+	}
+
+	rawQuery, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	db := s.QueryEngineProvider.GetQueryEngine(ctx)
+	if _, err := db.Exec(ctx, fmt.Sprintf("DECLARE %s NO SCROLL CURSOR FOR %s", exportCursor, rawQuery), args...); err != nil {
+		return err
+	}
+
+	fetch := fmt.Sprintf("FETCH FORWARD %d FROM %s", batch, exportCursor)
+	for {
+		var records []schema.WrapperOrder
+		if err := pgxscan.Select(ctx, db, &records, fetch); err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			break
+		}
+
+		orders, err := schema.ExtractOrdersFromWrapperOrder(records)
+		if err != nil {
+			return err
+		}
+		exported := make([]dto.ExportedOrder, len(orders))
+		for i, order := range orders {
+			exported[i] = dto.ExportedOrder{Order: order, DeliveredAt: records[i].CreatedAt}
+		}
+		if err := write(exported); err != nil {
+			return err
+		}
+
+		if uint(len(records)) < batch {
+			break
+		}
+	}
+
+	_, err = db.Exec(ctx, "CLOSE "+exportCursor)
+	return err
+}
@@ -14,8 +14,17 @@ type orderStorage interface {
 	ListOrders(ctx context.Context, get dto.ListOrdersParam) ([]model.Order, error)
 	ListUserOrders(ctx context.Context, userId string, count uint, status model.Status) ([]model.Order, error)
 	AddOrder(ctx context.Context, order model.Order, hash string) error
+	// AddOrders inserts the orders with multi-row inserts, errs[i] is ErrDuplicateOrderID
+	// if orders[i] is taken by a stored order or by an earlier one of the batch.
+	AddOrders(ctx context.Context, orders []model.Order, hashes []string) ([]error, error)
 	ListOrdersByIds(ctx context.Context, ids []string, status model.Status) ([]model.Order, error)
+	// WarmUp runs the queries one by one to fill the cache and returns how many of them succeeded.
+	WarmUp(ctx context.Context, params []dto.GetParam) int
 	UpdateStatus(ctx context.Context, ids dto.IdsWithHashes, status model.Status) error
 	GetOrderById(ctx context.Context, id string) (model.Order, error)
 	DeleteOrder(ctx context.Context, id string) error
+	// ExportOrders fetches the orders ordered by the delivery time from a server-side cursor and passes
+	// them to write by batch, so the export isn't loaded into memory. The cursor lives until the end of
+	// the transaction, ctx must have one.
+	ExportOrders(ctx context.Context, param dto.ExportOrdersParam, batch uint, write func([]dto.ExportedOrder) error) error
 }
@@ -10,6 +10,8 @@ import (
 // wrapperStorage ...
 type wrapperStorage interface {
 	AddWrapper(ctx context.Context, wrapper wrapper.Wrapper, orderId string) error
+	// AddWrappers inserts wrappers[i] of the order orderIds[i] with multi-row inserts.
+	AddWrappers(ctx context.Context, wrappers []wrapper.Wrapper, orderIds []string) error
 	Delete(ctx context.Context, orderId string) error
 	GetByOrderId(ctx context.Context, orderId string) (wrapper.Wrapper, error)
 }
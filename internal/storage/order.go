@@ -8,10 +8,13 @@ import (
 	"github.com/georgysavva/scany/pgxscan"
 	"github.com/lib/pq"
 	"github.com/opentracing/opentracing-go"
+	"golang.org/x/sync/singleflight"
 	"homework/internal/dto"
 	"homework/internal/model"
 	"homework/internal/storage/schema"
 	"homework/internal/storage/transactor"
+	"log"
+	"slices"
 	"strings"
 	"time"
 )
@@ -25,18 +28,34 @@ type (
 	OrderStorage struct {
 		transactor.QueryEngineProvider
 		ordersCache ordersCache
+		invalidator Invalidator
+		// queries runs one query per GetParam at a time, the waiters share its result.
+		queries singleflight.Group
+		// fetchTimeout bounds the shared and the background queries, which don't have the caller's deadline.
+		// 0 disables it.
+		fetchTimeout time.Duration
 	}
 
 	ordersCache interface {
-		Get(string) ([]model.Order, bool)
-		Put(string, []model.Order)
-		RemoveById(string)
-		RemoveByIds([]string)
+		Lookup(string) ([]model.Order, bool, bool)
+		Version() uint64
+		PutSince(dto.GetParam, []model.Order, uint64) bool
+		Invalidate([]string, []dto.OrderFilter)
+	}
+
+	// Invalidator tells the other instances which orders have been changed.
+	Invalidator interface {
+		Publish(ctx context.Context, message dto.InvalidationMessage) error
 	}
 )
 
-func NewOrderStorage(provider transactor.QueryEngineProvider, ordersCache ordersCache) *OrderStorage {
-	return &OrderStorage{provider, ordersCache}
+func NewOrderStorage(provider transactor.QueryEngineProvider, ordersCache ordersCache, invalidator Invalidator, fetchTimeout time.Duration) *OrderStorage {
+	return &OrderStorage{
+		QueryEngineProvider: provider,
+		ordersCache:         ordersCache,
+		invalidator:         invalidator,
+		fetchTimeout:        fetchTimeout,
+	}
 }
 
 func (s *OrderStorage) RefundedOrders(ctx context.Context, get dto.PageParam) ([]model.Order, error) {
@@ -90,6 +109,7 @@ func (s *OrderStorage) AddOrder(ctx context.Context, order model.Order, hash str
 
 	_, err = db.Exec(ctx, rawQuery, args...)
 	if err == nil {
+		s.invalidate(ctx, []string{order.ID}, []dto.OrderFilter{{RecipientId: order.RecipientID, Status: order.Status}})
 		return nil
 	}
 	if isDuplicateKeyError(err) {
@@ -98,16 +118,165 @@ func (s *OrderStorage) AddOrder(ctx context.Context, order model.Order, hash str
 	return err
 }
 
+// AddOrders inserts the orders with multi-row inserts, errs[i] is ErrDuplicateOrderID
+// if orders[i] is taken by a stored order or by an earlier one of the batch.
+func (s *OrderStorage) AddOrders(ctx context.Context, orders []model.Order, hashes []string) ([]error, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "storage.OrderStorage.AddOrders")
+	defer span.Finish()
+
+	if len(orders) != len(hashes) {
+		return nil, dto.ErrListWithHashesDifferentLength
+	}
+
+	errs := make([]error, len(orders))
+	records := make([]schema.Order, 0, len(orders))
+	seen := make(map[string]struct{}, len(orders))
+	for i, order := range orders {
+		if _, ok := seen[order.ID]; ok {
+			errs[i] = ErrDuplicateOrderID
+			continue
+		}
+		seen[order.ID] = struct{}{}
+		records = append(records, schema.NewOrder(order, hashes[i]))
+	}
+
+	db := s.QueryEngineProvider.GetQueryEngine(ctx)
+	columns := schema.Order{}.Columns()
+	added := make(map[string]struct{}, len(records))
+	for _, part := range insertParts(records, len(columns)) {
+		query := sq.Insert(orderTable).
+			Columns(columns...).
+			Suffix("ON CONFLICT (id) DO NOTHING RETURNING id").
+			PlaceholderFormat(sq.Dollar)
+		for _, record := range part {
+			query = query.Values(record.Values()...)
+		}
+
+		rawQuery, args, err := query.ToSql()
+		if err != nil {
+			return nil, err
+		}
+
+		var ids []string
+		if err := pgxscan.Select(ctx, db, &ids, rawQuery, args...); err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			added[id] = struct{}{}
+		}
+	}
+
+	ids := make([]string, 0, len(added))
+	var filters []dto.OrderFilter
+	for i, order := range orders {
+		if errs[i] != nil {
+			continue
+		}
+		if _, ok := added[order.ID]; !ok {
+			errs[i] = ErrDuplicateOrderID
+			continue
+		}
+		ids = append(ids, order.ID)
+		filters = append(filters, dto.OrderFilter{RecipientId: order.RecipientID, Status: order.Status})
+	}
+	if len(ids) != 0 {
+		s.invalidate(ctx, ids, filters)
+	}
+	return errs, nil
+}
+
 func (s *OrderStorage) ListOrdersByIds(ctx context.Context, ids []string, status model.Status) ([]model.Order, error) {
 	return s.get(ctx, dto.GetParam{Ids: ids, Status: status})
 }
 
+// WarmUp runs the queries one by one to fill the cache and returns how many of them succeeded.
+func (s *OrderStorage) WarmUp(ctx context.Context, params []dto.GetParam) int {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "storage.OrderStorage.WarmUp")
+	defer span.Finish()
+
+	loaded := 0
+	for _, param := range params {
+		if ctx.Err() != nil {
+			break
+		}
+		if _, err := s.get(ctx, param); err != nil {
+			log.Printf("[storage.OrderStorage] warm up error: %v", err)
+			continue
+		}
+		loaded++
+	}
+	return loaded
+}
+
 func (s *OrderStorage) get(ctx context.Context, param dto.GetParam) ([]model.Order, error) {
-	cachedOrders, ok := s.ordersCache.Get(param.String())
+	key := param.String()
+	cachedOrders, fresh, ok := s.ordersCache.Lookup(key)
+	if ok && fresh {
+		return cachedOrders, nil
+	}
 	if ok {
+		s.refresh(ctx, param)
 		return cachedOrders, nil
 	}
 
+	// the transaction reads its own snapshot that may have uncommitted changes, so it's neither shared nor cached
+	if transactor.InTransaction(ctx) {
+		return s.load(ctx, param)
+	}
+
+	// the shared query outlives the caller that started it, the others may still wait for it
+	orders, err, _ := s.queries.Do(key, func() (any, error) {
+		ctx, cancel := s.withFetchTimeout(context.WithoutCancel(ctx))
+		defer cancel()
+		return s.query(ctx, param)
+	})
+	if err != nil {
+		return []model.Order{}, err
+	}
+	return orders.([]model.Order), nil
+}
+
+// refresh updates the stale result in the background, outside the caller's transaction.
+func (s *OrderStorage) refresh(ctx context.Context, param dto.GetParam) {
+	span := opentracing.SpanFromContext(ctx)
+	s.queries.DoChan(param.String(), func() (any, error) {
+		ctx := context.Background()
+		if span != nil {
+			var refreshSpan opentracing.Span
+			refreshSpan, ctx = opentracing.StartSpanFromContext(ctx, "storage.OrderStorage.refresh",
+				opentracing.FollowsFrom(span.Context()))
+			defer refreshSpan.Finish()
+		}
+		ctx, cancel := s.withFetchTimeout(ctx)
+		defer cancel()
+
+		orders, err := s.query(ctx, param)
+		if err != nil {
+			log.Printf("[storage.OrderStorage] refresh error: %v", err)
+		}
+		return orders, err
+	})
+}
+
+func (s *OrderStorage) withFetchTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.fetchTimeout == 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, s.fetchTimeout)
+}
+
+func (s *OrderStorage) query(ctx context.Context, param dto.GetParam) ([]model.Order, error) {
+	version := s.ordersCache.Version()
+	orders, err := s.load(ctx, param)
+	if err != nil {
+		return orders, err
+	}
+
+	s.ordersCache.PutSince(param, orders, version)
+	return orders, nil
+}
+
+func (s *OrderStorage) load(ctx context.Context, param dto.GetParam) ([]model.Order, error) {
 	db := s.QueryEngineProvider.GetQueryEngine(ctx)
 	n := 1
 
@@ -149,13 +318,7 @@ func (s *OrderStorage) get(ctx context.Context, param dto.GetParam) ([]model.Ord
 		return []model.Order{}, err
 	}
 
-	orders, err := schema.ExtractOrdersFromWrapperOrder(records)
-	if err != nil {
-		return nil, err
-	}
-
-	s.ordersCache.Put(param.String(), orders)
-	return orders, nil
+	return schema.ExtractOrdersFromWrapperOrder(records)
 }
 
 func (s *OrderStorage) UpdateStatus(ctx context.Context, ids dto.IdsWithHashes, status model.Status) error {
@@ -169,6 +332,12 @@ func (s *OrderStorage) UpdateStatus(ctx context.Context, ids dto.IdsWithHashes,
 	}
 	setCases.WriteString("end;")
 
+	// lists with the old status lose the orders, so they are invalidated too
+	filters, err := s.filtersByIds(ctx, ids.Ids)
+	if err != nil {
+		return err
+	}
+
 	db := s.QueryEngineProvider.GetQueryEngine(ctx)
 	query := sq.Update(orderTable).
 		Set("status", status).
@@ -190,10 +359,37 @@ func (s *OrderStorage) UpdateStatus(ctx context.Context, ids dto.IdsWithHashes,
 		return err
 	}
 
-	s.ordersCache.RemoveByIds(ids.Ids)
+	for _, filter := range slices.Clone(filters) {
+		filters = append(filters, dto.OrderFilter{RecipientId: filter.RecipientId, Status: status})
+	}
+	s.invalidate(ctx, ids.Ids, filters)
 	return nil
 }
 
+func (s *OrderStorage) filtersByIds(ctx context.Context, ids []string) ([]dto.OrderFilter, error) {
+	db := s.QueryEngineProvider.GetQueryEngine(ctx)
+	query := sq.Select("recipient_id", "status").
+		From(orderTable).
+		Where("id = ANY($1)", pq.Array(ids)).
+		PlaceholderFormat(sq.Dollar)
+
+	rawQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var records []schema.Order
+	if err := pgxscan.Select(ctx, db, &records, rawQuery, args...); err != nil {
+		return nil, err
+	}
+
+	filters := make([]dto.OrderFilter, 0, len(records))
+	for _, record := range records {
+		filters = append(filters, dto.OrderFilter{RecipientId: record.RecipientID, Status: record.Status})
+	}
+	return filters, nil
+}
+
 func (s *OrderStorage) GetOrderById(ctx context.Context, id string) (model.Order, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "storage.OrderStorage.GetOrderById")
 	defer span.Finish()
@@ -217,6 +413,7 @@ func (s *OrderStorage) DeleteOrder(ctx context.Context, id string) error {
 	query := sq.Delete(orderTable).
 		From(orderTable).
 		Where("id = $1", id).
+		Suffix("RETURNING recipient_id, status").
 		PlaceholderFormat(sq.Dollar)
 
 	rawQuery, args, err := query.ToSql()
@@ -224,14 +421,34 @@ func (s *OrderStorage) DeleteOrder(ctx context.Context, id string) error {
 		return err
 	}
 
-	tag, err := db.Exec(ctx, rawQuery, args...)
-	if err == nil && tag.RowsAffected() == 0 {
-		return ErrNotFound
-	}
-	if err != nil {
+	var records []schema.Order
+	if err := pgxscan.Select(ctx, db, &records, rawQuery, args...); err != nil {
 		return err
 	}
+	if len(records) == 0 {
+		return ErrNotFound
+	}
 
-	s.ordersCache.RemoveById(id)
+	s.invalidate(ctx, []string{id}, []dto.OrderFilter{{RecipientId: records[0].RecipientID, Status: records[0].Status}})
 	return nil
 }
+
+// invalidate runs after the commit, so a read that started before it can't put the old rows back.
+// It doesn't fail the change: other instances drop the stale results after ttl anyway.
+func (s *OrderStorage) invalidate(ctx context.Context, ids []string, filters []dto.OrderFilter) {
+	transactor.AfterCommit(ctx, func() {
+		s.publishInvalidation(ctx, ids, filters)
+	})
+}
+
+func (s *OrderStorage) publishInvalidation(ctx context.Context, ids []string, filters []dto.OrderFilter) {
+	message := dto.InvalidationMessage{Ids: ids, Filters: filters}
+	s.ordersCache.Invalidate(message.Ids, message.Filters)
+
+	if s.invalidator == nil {
+		return
+	}
+	if err := s.invalidator.Publish(ctx, message); err != nil {
+		log.Printf("[storage.OrderStorage] invalidation error: %v", err)
+	}
+}
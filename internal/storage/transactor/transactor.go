@@ -10,7 +10,10 @@ import (
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
-const key = "tx"
+const (
+	key      = "tx"
+	hooksKey = "tx_hooks"
+)
 
 type (
 	// Transactor .
@@ -32,6 +35,10 @@ type (
 	TransactionManager struct {
 		pool *pgxpool.Pool
 	}
+
+	afterCommitHooks struct {
+		hooks []func()
+	}
 )
 
 func NewTransactionManager(pool *pgxpool.Pool) TransactionManager {
@@ -47,7 +54,9 @@ func (tm *TransactionManager) RunRepeatableRead(ctx context.Context, fx func(ctx
 	if err != nil {
 		return TransactionError{Inner: err}
 	}
-	if err := fx(context.WithValue(ctx, key, tx)); err != nil {
+	hooks := &afterCommitHooks{}
+	ctxTX := context.WithValue(context.WithValue(ctx, key, tx), hooksKey, hooks)
+	if err := fx(ctxTX); err != nil {
 		return TransactionError{Inner: err, Rollback: tx.Rollback(ctx)}
 	}
 
@@ -55,9 +64,29 @@ func (tm *TransactionManager) RunRepeatableRead(ctx context.Context, fx func(ctx
 		return TransactionError{Inner: err, Rollback: tx.Rollback(ctx)}
 	}
 
+	for _, hook := range hooks.hooks {
+		hook()
+	}
 	return nil
 }
 
+// InTransaction reports whether ctx carries a transaction of RunRepeatableRead.
+func InTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(key).(QueryEngine)
+	return ok
+}
+
+// AfterCommit runs fn after the transaction of ctx is committed and never if it's rolled back,
+// without a transaction fn runs at once.
+func AfterCommit(ctx context.Context, fn func()) {
+	hooks, ok := ctx.Value(hooksKey).(*afterCommitHooks)
+	if !ok {
+		fn()
+		return
+	}
+	hooks.hooks = append(hooks.hooks, fn)
+}
+
 func (tm *TransactionManager) Unwrap(err error) error {
 	if err == nil {
 		return nil
@@ -51,6 +51,46 @@ func (w *WrapperStorage) AddWrapper(ctx context.Context, wrapper wrapper.Wrapper
 	return err
 }
 
+// AddWrappers inserts wrappers[i] of the order orderIds[i] with multi-row inserts.
+func (w *WrapperStorage) AddWrappers(ctx context.Context, wrappers []wrapper.Wrapper, orderIds []string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "storage.WrapperStorage.AddWrappers")
+	defer span.Finish()
+
+	if len(wrappers) != len(orderIds) {
+		return ErrWrappersDifferentLength
+	}
+
+	records := make([]schema.Wrapper, 0, len(wrappers))
+	for i, wrapper := range wrappers {
+		records = append(records, schema.NewWrapper(wrapper, orderIds[i]))
+	}
+
+	db := w.QueryEngineProvider.GetQueryEngine(ctx)
+	columns := schema.Wrapper{}.Columns()
+	for _, part := range insertParts(records, len(columns)) {
+		query := sq.Insert(wrapperTable).
+			Columns(columns...).
+			PlaceholderFormat(sq.Dollar)
+		for _, record := range part {
+			query = query.Values(record.Values()...)
+		}
+
+		rawQuery, args, err := query.ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = db.Exec(ctx, rawQuery, args...)
+		if isDuplicateKeyError(err) {
+			return ErrDuplicateOrderID
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (w *WrapperStorage) Delete(ctx context.Context, orderId string) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "storage.WrapperStorage.Delete")
 	defer span.Finish()
@@ -80,7 +120,7 @@ func (w *WrapperStorage) GetByOrderId(ctx context.Context, orderId string) (wrap
 
 	db := w.QueryEngineProvider.GetQueryEngine(ctx)
 
-	columns := append(schema.Wrapper{}.SelectColumns())
+	columns := schema.Wrapper{}.SelectColumns()
 	query := sq.Select(columns...).
 		From(orderTable).
 		LeftJoin("ozon.wrappers on wrappers.order_id = orders.id").
@@ -0,0 +1,60 @@
+package tracer
+
+import (
+	"context"
+	"github.com/IBM/sarama"
+	"github.com/opentracing/opentracing-go"
+)
+
+type (
+	producerHeaders struct {
+		message *sarama.ProducerMessage
+	}
+
+	consumerHeaders struct {
+		message *sarama.ConsumerMessage
+	}
+)
+
+// InjectKafka writes the span context from ctx into the message headers.
+func InjectKafka(ctx context.Context, message *sarama.ProducerMessage) error {
+	span := opentracing.SpanFromContext(ctx)
+	if span == nil {
+		return nil
+	}
+	return span.Tracer().Inject(span.Context(), opentracing.TextMap, producerHeaders{message})
+}
+
+// StartSpanFromKafka starts a span that follows the one injected into the message headers.
+func StartSpanFromKafka(ctx context.Context, message *sarama.ConsumerMessage, operationName string) (opentracing.Span, context.Context) {
+	var opts []opentracing.StartSpanOption
+	spanContext, err := opentracing.GlobalTracer().Extract(opentracing.TextMap, consumerHeaders{message})
+	if err == nil {
+		opts = append(opts, opentracing.FollowsFrom(spanContext))
+	}
+
+	span := opentracing.GlobalTracer().StartSpan(operationName, opts...)
+	return span, opentracing.ContextWithSpan(ctx, span)
+}
+
+func (h producerHeaders) Set(key, val string) {
+	for i, header := range h.message.Headers {
+		if string(header.Key) == key {
+			h.message.Headers[i].Value = []byte(val)
+			return
+		}
+	}
+	h.message.Headers = append(h.message.Headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(val)})
+}
+
+func (h consumerHeaders) ForeachKey(handler func(key, val string) error) error {
+	for _, header := range h.message.Headers {
+		if header == nil {
+			continue
+		}
+		if err := handler(string(header.Key), string(header.Value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
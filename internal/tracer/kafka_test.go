@@ -0,0 +1,50 @@
+package tracer
+
+import (
+	"context"
+	"github.com/IBM/sarama"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestKafka_InjectExtract(t *testing.T) {
+	tracer := mocktracer.New()
+	opentracing.SetGlobalTracer(tracer)
+	defer opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+
+	producerSpan := tracer.StartSpan("producer")
+	ctx := opentracing.ContextWithSpan(context.Background(), producerSpan)
+
+	producerMessage := &sarama.ProducerMessage{}
+	require.NoError(t, InjectKafka(ctx, producerMessage))
+	producerSpan.Finish()
+
+	consumerMessage := &sarama.ConsumerMessage{}
+	for i := range producerMessage.Headers {
+		consumerMessage.Headers = append(consumerMessage.Headers, &producerMessage.Headers[i])
+	}
+
+	consumerSpan, ctx := StartSpanFromKafka(context.Background(), consumerMessage, "consumer")
+	consumerSpan.Finish()
+
+	spans := tracer.FinishedSpans()
+	require.Len(t, spans, 2)
+	require.Equal(t, spans[0].SpanContext.TraceID, spans[1].SpanContext.TraceID)
+	require.Equal(t, spans[0].SpanContext.SpanID, spans[1].ParentID)
+	require.Equal(t, consumerSpan, opentracing.SpanFromContext(ctx))
+}
+
+func TestKafka_ExtractWithoutHeaders(t *testing.T) {
+	tracer := mocktracer.New()
+	opentracing.SetGlobalTracer(tracer)
+	defer opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+
+	span, _ := StartSpanFromKafka(context.Background(), &sarama.ConsumerMessage{}, "consumer")
+	span.Finish()
+
+	spans := tracer.FinishedSpans()
+	require.Len(t, spans, 1)
+	require.Zero(t, spans[0].ParentID)
+}
@@ -0,0 +1,366 @@
+// Code generated by protoc-gen-validate. DO NOT EDIT.
+// source: admin/v1/admin.proto
+
+package admin
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ensure the imports are used
+var (
+	_ = bytes.MinRead
+	_ = errors.New("")
+	_ = fmt.Print
+	_ = utf8.UTFMax
+	_ = (*regexp.Regexp)(nil)
+	_ = (*strings.Reader)(nil)
+	_ = net.IPv4len
+	_ = time.Duration(0)
+	_ = (*url.URL)(nil)
+	_ = (*mail.Address)(nil)
+	_ = anypb.Any{}
+	_ = sort.Sort
+)
+
+// Validate checks the field values on CacheStatsResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *CacheStatsResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on CacheStatsResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// CacheStatsResponseMultiError, or nil if none found.
+func (m *CacheStatsResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *CacheStatsResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Hits
+
+	// no validation rules for Misses
+
+	// no validation rules for Evictions
+
+	// no validation rules for Size
+
+	if len(errors) > 0 {
+		return CacheStatsResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// CacheStatsResponseMultiError is an error wrapping multiple validation errors
+// returned by CacheStatsResponse.ValidateAll() if the designated constraints
+// aren't met.
+type CacheStatsResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m CacheStatsResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m CacheStatsResponseMultiError) AllErrors() []error { return m }
+
+// CacheStatsResponseValidationError is the validation error returned by
+// CacheStatsResponse.Validate if the designated constraints aren't met.
+type CacheStatsResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e CacheStatsResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CacheStatsResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CacheStatsResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CacheStatsResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CacheStatsResponseValidationError) ErrorName() string {
+	return "CacheStatsResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e CacheStatsResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sCacheStatsResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = CacheStatsResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = CacheStatsResponseValidationError{}
+
+// Validate checks the field values on CacheKeysResponse with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *CacheKeysResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on CacheKeysResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// CacheKeysResponseMultiError, or nil if none found.
+func (m *CacheKeysResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *CacheKeysResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(errors) > 0 {
+		return CacheKeysResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// CacheKeysResponseMultiError is an error wrapping multiple validation errors
+// returned by CacheKeysResponse.ValidateAll() if the designated constraints
+// aren't met.
+type CacheKeysResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m CacheKeysResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m CacheKeysResponseMultiError) AllErrors() []error { return m }
+
+// CacheKeysResponseValidationError is the validation error returned by
+// CacheKeysResponse.Validate if the designated constraints aren't met.
+type CacheKeysResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e CacheKeysResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CacheKeysResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CacheKeysResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CacheKeysResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CacheKeysResponseValidationError) ErrorName() string {
+	return "CacheKeysResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e CacheKeysResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sCacheKeysResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = CacheKeysResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = CacheKeysResponseValidationError{}
+
+// Validate checks the field values on FlushCacheRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *FlushCacheRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on FlushCacheRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// FlushCacheRequestMultiError, or nil if none found.
+func (m *FlushCacheRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *FlushCacheRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetOrderIDs() {
+		_, _ = idx, item
+
+		if utf8.RuneCountInString(item) < 1 {
+			err := FlushCacheRequestValidationError{
+				field:  fmt.Sprintf("OrderIDs[%v]", idx),
+				reason: "value length must be at least 1 runes",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return FlushCacheRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// FlushCacheRequestMultiError is an error wrapping multiple validation errors
+// returned by FlushCacheRequest.ValidateAll() if the designated constraints
+// aren't met.
+type FlushCacheRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m FlushCacheRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m FlushCacheRequestMultiError) AllErrors() []error { return m }
+
+// FlushCacheRequestValidationError is the validation error returned by
+// FlushCacheRequest.Validate if the designated constraints aren't met.
+type FlushCacheRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e FlushCacheRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e FlushCacheRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e FlushCacheRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e FlushCacheRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e FlushCacheRequestValidationError) ErrorName() string {
+	return "FlushCacheRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e FlushCacheRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sFlushCacheRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = FlushCacheRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = FlushCacheRequestValidationError{}
@@ -677,11 +677,17 @@ var file_order_v1_order_proto_rawDesc = []byte{
 	0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x2d, 0x65, 0x63, 0x6f, 0x73, 0x79, 0x73, 0x74,
 	0x65, 0x6d, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x2d, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x3f,
 	0x74, 0x61, 0x62, 0x3d, 0x72, 0x65, 0x61, 0x64, 0x6d, 0x65, 0x2d, 0x6f, 0x76, 0x2d, 0x66, 0x69,
-	0x6c, 0x65, 0x42, 0x39, 0x92, 0x41, 0x17, 0x12, 0x15, 0x0a, 0x0e, 0x6f, 0x7a, 0x6f, 0x6e, 0x20,
-	0x72, 0x6f, 0x75, 0x74, 0x65, 0x20, 0x32, 0x35, 0x36, 0x32, 0x03, 0x31, 0x2e, 0x30, 0x5a, 0x1d,
-	0x68, 0x6f, 0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x67, 0x72, 0x70,
-	0x63, 0x2f, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x3b, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x62, 0x06, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6c, 0x65, 0x42, 0x9f, 0x01, 0x92, 0x41, 0x7d, 0x12, 0x15, 0x0a, 0x0e, 0x6f, 0x7a, 0x6f, 0x6e,
+	0x20, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x20, 0x32, 0x35, 0x36, 0x32, 0x03, 0x31, 0x2e, 0x30, 0x5a,
+	0x48, 0x0a, 0x19, 0x0a, 0x06, 0x41, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x12, 0x0f, 0x08, 0x02, 0x1a,
+	0x09, 0x78, 0x2d, 0x61, 0x70, 0x69, 0x2d, 0x6b, 0x65, 0x79, 0x20, 0x02, 0x0a, 0x2b, 0x0a, 0x06,
+	0x42, 0x65, 0x61, 0x72, 0x65, 0x72, 0x12, 0x21, 0x08, 0x02, 0x12, 0x0c, 0x42, 0x65, 0x61, 0x72,
+	0x65, 0x72, 0x20, 0x3c, 0x6a, 0x77, 0x74, 0x3e, 0x1a, 0x0d, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72,
+	0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x20, 0x02, 0x62, 0x0c, 0x0a, 0x0a, 0x0a, 0x06, 0x41,
+	0x70, 0x69, 0x4b, 0x65, 0x79, 0x12, 0x00, 0x62, 0x0c, 0x0a, 0x0a, 0x0a, 0x06, 0x42, 0x65, 0x61,
+	0x72, 0x65, 0x72, 0x12, 0x00, 0x5a, 0x1d, 0x68, 0x6f, 0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x2f,
+	0x70, 0x6b, 0x67, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x2f, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x3b, 0x6f,
+	0x72, 0x64, 0x65, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -0,0 +1,2491 @@
+// Code generated by protoc-gen-validate. DO NOT EDIT.
+// source: order/v2/order.proto
+
+package order
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ensure the imports are used
+var (
+	_ = bytes.MinRead
+	_ = errors.New("")
+	_ = fmt.Print
+	_ = utf8.UTFMax
+	_ = (*regexp.Regexp)(nil)
+	_ = (*strings.Reader)(nil)
+	_ = net.IPv4len
+	_ = time.Duration(0)
+	_ = (*url.URL)(nil)
+	_ = (*mail.Address)(nil)
+	_ = anypb.Any{}
+	_ = sort.Sort
+)
+
+// Validate checks the field values on Order with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Order) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Order with the rules defined in the
+// proto definition for this message. If any rules are violated, the result is
+// a list of violation errors wrapped in OrderMultiError, or nil if none found.
+func (m *Order) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Order) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	// no validation rules for RecipientId
+
+	// no validation rules for Status
+
+	if all {
+		switch v := interface{}(m.GetStatusUpdatedAt()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, OrderValidationError{
+					field:  "StatusUpdatedAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, OrderValidationError{
+					field:  "StatusUpdatedAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetStatusUpdatedAt()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return OrderValidationError{
+				field:  "StatusUpdatedAt",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetExpiresAt()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, OrderValidationError{
+					field:  "ExpiresAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, OrderValidationError{
+					field:  "ExpiresAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetExpiresAt()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return OrderValidationError{
+				field:  "ExpiresAt",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for WeightKg
+
+	// no validation rules for WrapperType
+
+	// no validation rules for PriceRub
+
+	if len(errors) > 0 {
+		return OrderMultiError(errors)
+	}
+
+	return nil
+}
+
+// OrderMultiError is an error wrapping multiple validation errors returned by
+// Order.ValidateAll() if the designated constraints aren't met.
+type OrderMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m OrderMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m OrderMultiError) AllErrors() []error { return m }
+
+// OrderValidationError is the validation error returned by Order.Validate if
+// the designated constraints aren't met.
+type OrderValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e OrderValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e OrderValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e OrderValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e OrderValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e OrderValidationError) ErrorName() string { return "OrderValidationError" }
+
+// Error satisfies the builtin error interface
+func (e OrderValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sOrder.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = OrderValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = OrderValidationError{}
+
+// Validate checks the field values on DeliverOrderRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *DeliverOrderRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DeliverOrderRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// DeliverOrderRequestMultiError, or nil if none found.
+func (m *DeliverOrderRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DeliverOrderRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetOrderId()) < 1 {
+		err := DeliverOrderRequestValidationError{
+			field:  "OrderId",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetRecipientId()) < 1 {
+		err := DeliverOrderRequestValidationError{
+			field:  "RecipientId",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetExpiresAt() == nil {
+		err := DeliverOrderRequestValidationError{
+			field:  "ExpiresAt",
+			reason: "value is required",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if t := m.GetExpiresAt(); t != nil {
+		ts, err := t.AsTime(), t.CheckValid()
+		if err != nil {
+			err = DeliverOrderRequestValidationError{
+				field:  "ExpiresAt",
+				reason: "value is not a valid timestamp",
+				cause:  err,
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		} else {
+
+			now := time.Now()
+
+			if ts.Sub(now) <= 0 {
+				err := DeliverOrderRequestValidationError{
+					field:  "ExpiresAt",
+					reason: "value must be greater than now",
+				}
+				if !all {
+					return err
+				}
+				errors = append(errors, err)
+			}
+
+		}
+	}
+
+	if _, ok := WrapperType_name[int32(m.GetWrapperType())]; !ok {
+		err := DeliverOrderRequestValidationError{
+			field:  "WrapperType",
+			reason: "value must be one of the defined enum values",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetWeightKg() <= 0 {
+		err := DeliverOrderRequestValidationError{
+			field:  "WeightKg",
+			reason: "value must be greater than 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if !_DeliverOrderRequest_PriceRub_Pattern.MatchString(m.GetPriceRub()) {
+		err := DeliverOrderRequestValidationError{
+			field:  "PriceRub",
+			reason: "value does not match regex pattern \"^[0-9]+(\\\\.[0-9]{1,2})?$\"",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return DeliverOrderRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// DeliverOrderRequestMultiError is an error wrapping multiple validation
+// errors returned by DeliverOrderRequest.ValidateAll() if the designated
+// constraints aren't met.
+type DeliverOrderRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DeliverOrderRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DeliverOrderRequestMultiError) AllErrors() []error { return m }
+
+// DeliverOrderRequestValidationError is the validation error returned by
+// DeliverOrderRequest.Validate if the designated constraints aren't met.
+type DeliverOrderRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DeliverOrderRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DeliverOrderRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DeliverOrderRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DeliverOrderRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DeliverOrderRequestValidationError) ErrorName() string {
+	return "DeliverOrderRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e DeliverOrderRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDeliverOrderRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DeliverOrderRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DeliverOrderRequestValidationError{}
+
+var _DeliverOrderRequest_PriceRub_Pattern = regexp.MustCompile("^[0-9]+(\\.[0-9]{1,2})?$")
+
+// Validate checks the field values on DeliverOrdersRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *DeliverOrdersRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DeliverOrdersRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// DeliverOrdersRequestMultiError, or nil if none found.
+func (m *DeliverOrdersRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DeliverOrdersRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(m.GetOrders()) < 1 {
+		err := DeliverOrdersRequestValidationError{
+			field:  "Orders",
+			reason: "value must contain at least 1 item(s)",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	for idx, item := range m.GetOrders() {
+		_, _ = idx, item
+
+		// skipping validation for orders
+
+	}
+
+	if len(errors) > 0 {
+		return DeliverOrdersRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// DeliverOrdersRequestMultiError is an error wrapping multiple validation
+// errors returned by DeliverOrdersRequest.ValidateAll() if the designated
+// constraints aren't met.
+type DeliverOrdersRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DeliverOrdersRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DeliverOrdersRequestMultiError) AllErrors() []error { return m }
+
+// DeliverOrdersRequestValidationError is the validation error returned by
+// DeliverOrdersRequest.Validate if the designated constraints aren't met.
+type DeliverOrdersRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DeliverOrdersRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DeliverOrdersRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DeliverOrdersRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DeliverOrdersRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DeliverOrdersRequestValidationError) ErrorName() string {
+	return "DeliverOrdersRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e DeliverOrdersRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDeliverOrdersRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DeliverOrdersRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DeliverOrdersRequestValidationError{}
+
+// Validate checks the field values on DeliverOrderResult with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *DeliverOrderResult) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DeliverOrderResult with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// DeliverOrderResultMultiError, or nil if none found.
+func (m *DeliverOrderResult) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DeliverOrderResult) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for OrderId
+
+	if all {
+		switch v := interface{}(m.GetStatus()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, DeliverOrderResultValidationError{
+					field:  "Status",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, DeliverOrderResultValidationError{
+					field:  "Status",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetStatus()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return DeliverOrderResultValidationError{
+				field:  "Status",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return DeliverOrderResultMultiError(errors)
+	}
+
+	return nil
+}
+
+// DeliverOrderResultMultiError is an error wrapping multiple validation errors
+// returned by DeliverOrderResult.ValidateAll() if the designated constraints
+// aren't met.
+type DeliverOrderResultMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DeliverOrderResultMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DeliverOrderResultMultiError) AllErrors() []error { return m }
+
+// DeliverOrderResultValidationError is the validation error returned by
+// DeliverOrderResult.Validate if the designated constraints aren't met.
+type DeliverOrderResultValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DeliverOrderResultValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DeliverOrderResultValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DeliverOrderResultValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DeliverOrderResultValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DeliverOrderResultValidationError) ErrorName() string {
+	return "DeliverOrderResultValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e DeliverOrderResultValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDeliverOrderResult.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DeliverOrderResultValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DeliverOrderResultValidationError{}
+
+// Validate checks the field values on DeliverOrdersResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *DeliverOrdersResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DeliverOrdersResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// DeliverOrdersResponseMultiError, or nil if none found.
+func (m *DeliverOrdersResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DeliverOrdersResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetResults() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, DeliverOrdersResponseValidationError{
+						field:  fmt.Sprintf("Results[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, DeliverOrdersResponseValidationError{
+						field:  fmt.Sprintf("Results[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return DeliverOrdersResponseValidationError{
+					field:  fmt.Sprintf("Results[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	// no validation rules for Delivered
+
+	if len(errors) > 0 {
+		return DeliverOrdersResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// DeliverOrdersResponseMultiError is an error wrapping multiple validation
+// errors returned by DeliverOrdersResponse.ValidateAll() if the designated
+// constraints aren't met.
+type DeliverOrdersResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DeliverOrdersResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DeliverOrdersResponseMultiError) AllErrors() []error { return m }
+
+// DeliverOrdersResponseValidationError is the validation error returned by
+// DeliverOrdersResponse.Validate if the designated constraints aren't met.
+type DeliverOrdersResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DeliverOrdersResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DeliverOrdersResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DeliverOrdersResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DeliverOrdersResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DeliverOrdersResponseValidationError) ErrorName() string {
+	return "DeliverOrdersResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e DeliverOrdersResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDeliverOrdersResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DeliverOrdersResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DeliverOrdersResponseValidationError{}
+
+// Validate checks the field values on ImportDeliveriesRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ImportDeliveriesRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ImportDeliveriesRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ImportDeliveriesRequestMultiError, or nil if none found.
+func (m *ImportDeliveriesRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ImportDeliveriesRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(m.GetContent()) < 1 {
+		err := ImportDeliveriesRequestValidationError{
+			field:  "Content",
+			reason: "value length must be at least 1 bytes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if _, ok := ManifestFormat_name[int32(m.GetFormat())]; !ok {
+		err := ImportDeliveriesRequestValidationError{
+			field:  "Format",
+			reason: "value must be one of the defined enum values",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for DryRun
+
+	if len(errors) > 0 {
+		return ImportDeliveriesRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// ImportDeliveriesRequestMultiError is an error wrapping multiple validation
+// errors returned by ImportDeliveriesRequest.ValidateAll() if the designated
+// constraints aren't met.
+type ImportDeliveriesRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ImportDeliveriesRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ImportDeliveriesRequestMultiError) AllErrors() []error { return m }
+
+// ImportDeliveriesRequestValidationError is the validation error returned by
+// ImportDeliveriesRequest.Validate if the designated constraints aren't met.
+type ImportDeliveriesRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ImportDeliveriesRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ImportDeliveriesRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ImportDeliveriesRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ImportDeliveriesRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ImportDeliveriesRequestValidationError) ErrorName() string {
+	return "ImportDeliveriesRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ImportDeliveriesRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sImportDeliveriesRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ImportDeliveriesRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ImportDeliveriesRequestValidationError{}
+
+// Validate checks the field values on ImportRowResult with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *ImportRowResult) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ImportRowResult with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ImportRowResultMultiError, or nil if none found.
+func (m *ImportRowResult) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ImportRowResult) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Row
+
+	// no validation rules for OrderId
+
+	if all {
+		switch v := interface{}(m.GetStatus()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ImportRowResultValidationError{
+					field:  "Status",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ImportRowResultValidationError{
+					field:  "Status",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetStatus()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ImportRowResultValidationError{
+				field:  "Status",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return ImportRowResultMultiError(errors)
+	}
+
+	return nil
+}
+
+// ImportRowResultMultiError is an error wrapping multiple validation errors
+// returned by ImportRowResult.ValidateAll() if the designated constraints
+// aren't met.
+type ImportRowResultMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ImportRowResultMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ImportRowResultMultiError) AllErrors() []error { return m }
+
+// ImportRowResultValidationError is the validation error returned by
+// ImportRowResult.Validate if the designated constraints aren't met.
+type ImportRowResultValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ImportRowResultValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ImportRowResultValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ImportRowResultValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ImportRowResultValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ImportRowResultValidationError) ErrorName() string { return "ImportRowResultValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ImportRowResultValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sImportRowResult.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ImportRowResultValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ImportRowResultValidationError{}
+
+// Validate checks the field values on ImportDeliveriesResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ImportDeliveriesResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ImportDeliveriesResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ImportDeliveriesResponseMultiError, or nil if none found.
+func (m *ImportDeliveriesResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ImportDeliveriesResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetRows() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, ImportDeliveriesResponseValidationError{
+						field:  fmt.Sprintf("Rows[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, ImportDeliveriesResponseValidationError{
+						field:  fmt.Sprintf("Rows[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ImportDeliveriesResponseValidationError{
+					field:  fmt.Sprintf("Rows[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	// no validation rules for Accepted
+
+	// no validation rules for Failed
+
+	// no validation rules for DryRun
+
+	if len(errors) > 0 {
+		return ImportDeliveriesResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// ImportDeliveriesResponseMultiError is an error wrapping multiple validation
+// errors returned by ImportDeliveriesResponse.ValidateAll() if the designated
+// constraints aren't met.
+type ImportDeliveriesResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ImportDeliveriesResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ImportDeliveriesResponseMultiError) AllErrors() []error { return m }
+
+// ImportDeliveriesResponseValidationError is the validation error returned by
+// ImportDeliveriesResponse.Validate if the designated constraints aren't met.
+type ImportDeliveriesResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ImportDeliveriesResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ImportDeliveriesResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ImportDeliveriesResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ImportDeliveriesResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ImportDeliveriesResponseValidationError) ErrorName() string {
+	return "ImportDeliveriesResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ImportDeliveriesResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sImportDeliveriesResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ImportDeliveriesResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ImportDeliveriesResponseValidationError{}
+
+// Validate checks the field values on GetOrderRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *GetOrderRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on GetOrderRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// GetOrderRequestMultiError, or nil if none found.
+func (m *GetOrderRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *GetOrderRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetOrderId()) < 1 {
+		err := GetOrderRequestValidationError{
+			field:  "OrderId",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return GetOrderRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// GetOrderRequestMultiError is an error wrapping multiple validation errors
+// returned by GetOrderRequest.ValidateAll() if the designated constraints
+// aren't met.
+type GetOrderRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m GetOrderRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m GetOrderRequestMultiError) AllErrors() []error { return m }
+
+// GetOrderRequestValidationError is the validation error returned by
+// GetOrderRequest.Validate if the designated constraints aren't met.
+type GetOrderRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GetOrderRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GetOrderRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GetOrderRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GetOrderRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GetOrderRequestValidationError) ErrorName() string { return "GetOrderRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e GetOrderRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGetOrderRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GetOrderRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GetOrderRequestValidationError{}
+
+// Validate checks the field values on ListOrdersRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *ListOrdersRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ListOrdersRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ListOrdersRequestMultiError, or nil if none found.
+func (m *ListOrdersRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ListOrdersRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetRecipientId()) < 1 {
+		err := ListOrdersRequestValidationError{
+			field:  "RecipientId",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetPageSize() <= 0 {
+		err := ListOrdersRequestValidationError{
+			field:  "PageSize",
+			reason: "value must be greater than 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetPage() <= 0 {
+		err := ListOrdersRequestValidationError{
+			field:  "Page",
+			reason: "value must be greater than 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if _, ok := _ListOrdersRequest_Status_NotInLookup[m.GetStatus()]; ok {
+		err := ListOrdersRequestValidationError{
+			field:  "Status",
+			reason: "value must not be in list [ORDER_STATUS_RETURNED]",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if _, ok := OrderStatus_name[int32(m.GetStatus())]; !ok {
+		err := ListOrdersRequestValidationError{
+			field:  "Status",
+			reason: "value must be one of the defined enum values",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return ListOrdersRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// ListOrdersRequestMultiError is an error wrapping multiple validation errors
+// returned by ListOrdersRequest.ValidateAll() if the designated constraints
+// aren't met.
+type ListOrdersRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ListOrdersRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ListOrdersRequestMultiError) AllErrors() []error { return m }
+
+// ListOrdersRequestValidationError is the validation error returned by
+// ListOrdersRequest.Validate if the designated constraints aren't met.
+type ListOrdersRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ListOrdersRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ListOrdersRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ListOrdersRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ListOrdersRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ListOrdersRequestValidationError) ErrorName() string {
+	return "ListOrdersRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ListOrdersRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sListOrdersRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ListOrdersRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ListOrdersRequestValidationError{}
+
+var _ListOrdersRequest_Status_NotInLookup = map[OrderStatus]struct{}{
+	4: {},
+}
+
+// Validate checks the field values on ListOrdersResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ListOrdersResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ListOrdersResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ListOrdersResponseMultiError, or nil if none found.
+func (m *ListOrdersResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ListOrdersResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetOrders() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, ListOrdersResponseValidationError{
+						field:  fmt.Sprintf("Orders[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, ListOrdersResponseValidationError{
+						field:  fmt.Sprintf("Orders[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ListOrdersResponseValidationError{
+					field:  fmt.Sprintf("Orders[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return ListOrdersResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// ListOrdersResponseMultiError is an error wrapping multiple validation errors
+// returned by ListOrdersResponse.ValidateAll() if the designated constraints
+// aren't met.
+type ListOrdersResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ListOrdersResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ListOrdersResponseMultiError) AllErrors() []error { return m }
+
+// ListOrdersResponseValidationError is the validation error returned by
+// ListOrdersResponse.Validate if the designated constraints aren't met.
+type ListOrdersResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ListOrdersResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ListOrdersResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ListOrdersResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ListOrdersResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ListOrdersResponseValidationError) ErrorName() string {
+	return "ListOrdersResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ListOrdersResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sListOrdersResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ListOrdersResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ListOrdersResponseValidationError{}
+
+// Validate checks the field values on WatchOrdersRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *WatchOrdersRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on WatchOrdersRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// WatchOrdersRequestMultiError, or nil if none found.
+func (m *WatchOrdersRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *WatchOrdersRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for RecipientId
+
+	if _, ok := OrderStatus_name[int32(m.GetStatus())]; !ok {
+		err := WatchOrdersRequestValidationError{
+			field:  "Status",
+			reason: "value must be one of the defined enum values",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for PickupPoint
+
+	// no validation rules for AfterEventId
+
+	if len(errors) > 0 {
+		return WatchOrdersRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// WatchOrdersRequestMultiError is an error wrapping multiple validation errors
+// returned by WatchOrdersRequest.ValidateAll() if the designated constraints
+// aren't met.
+type WatchOrdersRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m WatchOrdersRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m WatchOrdersRequestMultiError) AllErrors() []error { return m }
+
+// WatchOrdersRequestValidationError is the validation error returned by
+// WatchOrdersRequest.Validate if the designated constraints aren't met.
+type WatchOrdersRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e WatchOrdersRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e WatchOrdersRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e WatchOrdersRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e WatchOrdersRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e WatchOrdersRequestValidationError) ErrorName() string {
+	return "WatchOrdersRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e WatchOrdersRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sWatchOrdersRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = WatchOrdersRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = WatchOrdersRequestValidationError{}
+
+// Validate checks the field values on OrderEvent with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *OrderEvent) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on OrderEvent with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in OrderEventMultiError, or
+// nil if none found.
+func (m *OrderEvent) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *OrderEvent) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	// no validation rules for OrderId
+
+	// no validation rules for RecipientId
+
+	// no validation rules for Status
+
+	// no validation rules for PickupPoint
+
+	if all {
+		switch v := interface{}(m.GetOccurredAt()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, OrderEventValidationError{
+					field:  "OccurredAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, OrderEventValidationError{
+					field:  "OccurredAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetOccurredAt()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return OrderEventValidationError{
+				field:  "OccurredAt",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return OrderEventMultiError(errors)
+	}
+
+	return nil
+}
+
+// OrderEventMultiError is an error wrapping multiple validation errors
+// returned by OrderEvent.ValidateAll() if the designated constraints aren't met.
+type OrderEventMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m OrderEventMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m OrderEventMultiError) AllErrors() []error { return m }
+
+// OrderEventValidationError is the validation error returned by
+// OrderEvent.Validate if the designated constraints aren't met.
+type OrderEventValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e OrderEventValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e OrderEventValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e OrderEventValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e OrderEventValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e OrderEventValidationError) ErrorName() string { return "OrderEventValidationError" }
+
+// Error satisfies the builtin error interface
+func (e OrderEventValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sOrderEvent.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = OrderEventValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = OrderEventValidationError{}
+
+// Validate checks the field values on IssueOrderRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *IssueOrderRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on IssueOrderRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// IssueOrderRequestMultiError, or nil if none found.
+func (m *IssueOrderRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *IssueOrderRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetOrderId()) < 1 {
+		err := IssueOrderRequestValidationError{
+			field:  "OrderId",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return IssueOrderRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// IssueOrderRequestMultiError is an error wrapping multiple validation errors
+// returned by IssueOrderRequest.ValidateAll() if the designated constraints
+// aren't met.
+type IssueOrderRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m IssueOrderRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m IssueOrderRequestMultiError) AllErrors() []error { return m }
+
+// IssueOrderRequestValidationError is the validation error returned by
+// IssueOrderRequest.Validate if the designated constraints aren't met.
+type IssueOrderRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e IssueOrderRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e IssueOrderRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e IssueOrderRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e IssueOrderRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e IssueOrderRequestValidationError) ErrorName() string {
+	return "IssueOrderRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e IssueOrderRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sIssueOrderRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = IssueOrderRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = IssueOrderRequestValidationError{}
+
+// Validate checks the field values on BatchIssueOrdersRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *BatchIssueOrdersRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on BatchIssueOrdersRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// BatchIssueOrdersRequestMultiError, or nil if none found.
+func (m *BatchIssueOrdersRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *BatchIssueOrdersRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(m.GetOrderIds()) < 1 {
+		err := BatchIssueOrdersRequestValidationError{
+			field:  "OrderIds",
+			reason: "value must contain at least 1 item(s)",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	_BatchIssueOrdersRequest_OrderIds_Unique := make(map[string]struct{}, len(m.GetOrderIds()))
+
+	for idx, item := range m.GetOrderIds() {
+		_, _ = idx, item
+
+		if _, exists := _BatchIssueOrdersRequest_OrderIds_Unique[item]; exists {
+			err := BatchIssueOrdersRequestValidationError{
+				field:  fmt.Sprintf("OrderIds[%v]", idx),
+				reason: "repeated value must contain unique items",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		} else {
+			_BatchIssueOrdersRequest_OrderIds_Unique[item] = struct{}{}
+		}
+
+		if utf8.RuneCountInString(item) < 1 {
+			err := BatchIssueOrdersRequestValidationError{
+				field:  fmt.Sprintf("OrderIds[%v]", idx),
+				reason: "value length must be at least 1 runes",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return BatchIssueOrdersRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// BatchIssueOrdersRequestMultiError is an error wrapping multiple validation
+// errors returned by BatchIssueOrdersRequest.ValidateAll() if the designated
+// constraints aren't met.
+type BatchIssueOrdersRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m BatchIssueOrdersRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m BatchIssueOrdersRequestMultiError) AllErrors() []error { return m }
+
+// BatchIssueOrdersRequestValidationError is the validation error returned by
+// BatchIssueOrdersRequest.Validate if the designated constraints aren't met.
+type BatchIssueOrdersRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e BatchIssueOrdersRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e BatchIssueOrdersRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e BatchIssueOrdersRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e BatchIssueOrdersRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e BatchIssueOrdersRequestValidationError) ErrorName() string {
+	return "BatchIssueOrdersRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e BatchIssueOrdersRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sBatchIssueOrdersRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = BatchIssueOrdersRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = BatchIssueOrdersRequestValidationError{}
+
+// Validate checks the field values on BatchIssueOrdersResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *BatchIssueOrdersResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on BatchIssueOrdersResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// BatchIssueOrdersResponseMultiError, or nil if none found.
+func (m *BatchIssueOrdersResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *BatchIssueOrdersResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetOrders() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, BatchIssueOrdersResponseValidationError{
+						field:  fmt.Sprintf("Orders[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, BatchIssueOrdersResponseValidationError{
+						field:  fmt.Sprintf("Orders[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return BatchIssueOrdersResponseValidationError{
+					field:  fmt.Sprintf("Orders[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return BatchIssueOrdersResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// BatchIssueOrdersResponseMultiError is an error wrapping multiple validation
+// errors returned by BatchIssueOrdersResponse.ValidateAll() if the designated
+// constraints aren't met.
+type BatchIssueOrdersResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m BatchIssueOrdersResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m BatchIssueOrdersResponseMultiError) AllErrors() []error { return m }
+
+// BatchIssueOrdersResponseValidationError is the validation error returned by
+// BatchIssueOrdersResponse.Validate if the designated constraints aren't met.
+type BatchIssueOrdersResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e BatchIssueOrdersResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e BatchIssueOrdersResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e BatchIssueOrdersResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e BatchIssueOrdersResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e BatchIssueOrdersResponseValidationError) ErrorName() string {
+	return "BatchIssueOrdersResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e BatchIssueOrdersResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sBatchIssueOrdersResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = BatchIssueOrdersResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = BatchIssueOrdersResponseValidationError{}
+
+// Validate checks the field values on RefundOrderRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *RefundOrderRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RefundOrderRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// RefundOrderRequestMultiError, or nil if none found.
+func (m *RefundOrderRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RefundOrderRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetOrderId()) < 1 {
+		err := RefundOrderRequestValidationError{
+			field:  "OrderId",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetRecipientId()) < 1 {
+		err := RefundOrderRequestValidationError{
+			field:  "RecipientId",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return RefundOrderRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// RefundOrderRequestMultiError is an error wrapping multiple validation errors
+// returned by RefundOrderRequest.ValidateAll() if the designated constraints
+// aren't met.
+type RefundOrderRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RefundOrderRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RefundOrderRequestMultiError) AllErrors() []error { return m }
+
+// RefundOrderRequestValidationError is the validation error returned by
+// RefundOrderRequest.Validate if the designated constraints aren't met.
+type RefundOrderRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RefundOrderRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RefundOrderRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RefundOrderRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RefundOrderRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RefundOrderRequestValidationError) ErrorName() string {
+	return "RefundOrderRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e RefundOrderRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRefundOrderRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RefundOrderRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RefundOrderRequestValidationError{}
+
+// Validate checks the field values on ReturnOrderRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ReturnOrderRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ReturnOrderRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ReturnOrderRequestMultiError, or nil if none found.
+func (m *ReturnOrderRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ReturnOrderRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetOrderId()) < 1 {
+		err := ReturnOrderRequestValidationError{
+			field:  "OrderId",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return ReturnOrderRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// ReturnOrderRequestMultiError is an error wrapping multiple validation errors
+// returned by ReturnOrderRequest.ValidateAll() if the designated constraints
+// aren't met.
+type ReturnOrderRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ReturnOrderRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ReturnOrderRequestMultiError) AllErrors() []error { return m }
+
+// ReturnOrderRequestValidationError is the validation error returned by
+// ReturnOrderRequest.Validate if the designated constraints aren't met.
+type ReturnOrderRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ReturnOrderRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ReturnOrderRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ReturnOrderRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ReturnOrderRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ReturnOrderRequestValidationError) ErrorName() string {
+	return "ReturnOrderRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ReturnOrderRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sReturnOrderRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ReturnOrderRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ReturnOrderRequestValidationError{}
@@ -0,0 +1,573 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             v4.25.1
+// source: order/v2/order.proto
+
+package order
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	OrderService_DeliverOrder_FullMethodName        = "/order.v2.OrderService/DeliverOrder"
+	OrderService_DeliverOrders_FullMethodName       = "/order.v2.OrderService/DeliverOrders"
+	OrderService_StreamDeliverOrders_FullMethodName = "/order.v2.OrderService/StreamDeliverOrders"
+	OrderService_ImportDeliveries_FullMethodName    = "/order.v2.OrderService/ImportDeliveries"
+	OrderService_GetOrder_FullMethodName            = "/order.v2.OrderService/GetOrder"
+	OrderService_ListOrders_FullMethodName          = "/order.v2.OrderService/ListOrders"
+	OrderService_WatchOrders_FullMethodName         = "/order.v2.OrderService/WatchOrders"
+	OrderService_IssueOrder_FullMethodName          = "/order.v2.OrderService/IssueOrder"
+	OrderService_BatchIssueOrders_FullMethodName    = "/order.v2.OrderService/BatchIssueOrders"
+	OrderService_RefundOrder_FullMethodName         = "/order.v2.OrderService/RefundOrder"
+	OrderService_ReturnOrder_FullMethodName         = "/order.v2.OrderService/ReturnOrder"
+)
+
+// OrderServiceClient is the client API for OrderService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type OrderServiceClient interface {
+	// DeliverOrder accepts the order from the courier.
+	DeliverOrder(ctx context.Context, in *DeliverOrderRequest, opts ...grpc.CallOption) (*Order, error)
+	// DeliverOrders accepts the orders the courier hands over at once, every order succeeds or fails on its own.
+	DeliverOrders(ctx context.Context, in *DeliverOrdersRequest, opts ...grpc.CallOption) (*DeliverOrdersResponse, error)
+	// StreamDeliverOrders is DeliverOrders for manifests too large for one request.
+	StreamDeliverOrders(ctx context.Context, opts ...grpc.CallOption) (OrderService_StreamDeliverOrdersClient, error)
+	// ImportDeliveries delivers the orders of a courier's manifest, the rows are checked like the deliver command of the cli.
+	ImportDeliveries(ctx context.Context, in *ImportDeliveriesRequest, opts ...grpc.CallOption) (*ImportDeliveriesResponse, error)
+	GetOrder(ctx context.Context, in *GetOrderRequest, opts ...grpc.CallOption) (*Order, error)
+	ListOrders(ctx context.Context, in *ListOrdersRequest, opts ...grpc.CallOption) (*ListOrdersResponse, error)
+	// WatchOrders streams the status changes of the orders matching the filter as they happen.
+	// A client that has lost the stream resumes with the id of the last event it has received.
+	WatchOrders(ctx context.Context, in *WatchOrdersRequest, opts ...grpc.CallOption) (OrderService_WatchOrdersClient, error)
+	// IssueOrder gives the order to the recipient.
+	IssueOrder(ctx context.Context, in *IssueOrderRequest, opts ...grpc.CallOption) (*Order, error)
+	// BatchIssueOrders gives all the orders to their recipient at once, they must belong to the same recipient.
+	BatchIssueOrders(ctx context.Context, in *BatchIssueOrdersRequest, opts ...grpc.CallOption) (*BatchIssueOrdersResponse, error)
+	// RefundOrder takes the issued order back from the recipient.
+	RefundOrder(ctx context.Context, in *RefundOrderRequest, opts ...grpc.CallOption) (*Order, error)
+	// ReturnOrder gives the expired order back to the courier, the order is deleted.
+	ReturnOrder(ctx context.Context, in *ReturnOrderRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type orderServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOrderServiceClient(cc grpc.ClientConnInterface) OrderServiceClient {
+	return &orderServiceClient{cc}
+}
+
+func (c *orderServiceClient) DeliverOrder(ctx context.Context, in *DeliverOrderRequest, opts ...grpc.CallOption) (*Order, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Order)
+	err := c.cc.Invoke(ctx, OrderService_DeliverOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) DeliverOrders(ctx context.Context, in *DeliverOrdersRequest, opts ...grpc.CallOption) (*DeliverOrdersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeliverOrdersResponse)
+	err := c.cc.Invoke(ctx, OrderService_DeliverOrders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) StreamDeliverOrders(ctx context.Context, opts ...grpc.CallOption) (OrderService_StreamDeliverOrdersClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OrderService_ServiceDesc.Streams[0], OrderService_StreamDeliverOrders_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &orderServiceStreamDeliverOrdersClient{ClientStream: stream}
+	return x, nil
+}
+
+type OrderService_StreamDeliverOrdersClient interface {
+	Send(*DeliverOrderRequest) error
+	CloseAndRecv() (*DeliverOrdersResponse, error)
+	grpc.ClientStream
+}
+
+type orderServiceStreamDeliverOrdersClient struct {
+	grpc.ClientStream
+}
+
+func (x *orderServiceStreamDeliverOrdersClient) Send(m *DeliverOrderRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *orderServiceStreamDeliverOrdersClient) CloseAndRecv() (*DeliverOrdersResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(DeliverOrdersResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *orderServiceClient) ImportDeliveries(ctx context.Context, in *ImportDeliveriesRequest, opts ...grpc.CallOption) (*ImportDeliveriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportDeliveriesResponse)
+	err := c.cc.Invoke(ctx, OrderService_ImportDeliveries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) GetOrder(ctx context.Context, in *GetOrderRequest, opts ...grpc.CallOption) (*Order, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Order)
+	err := c.cc.Invoke(ctx, OrderService_GetOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ListOrders(ctx context.Context, in *ListOrdersRequest, opts ...grpc.CallOption) (*ListOrdersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOrdersResponse)
+	err := c.cc.Invoke(ctx, OrderService_ListOrders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) WatchOrders(ctx context.Context, in *WatchOrdersRequest, opts ...grpc.CallOption) (OrderService_WatchOrdersClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OrderService_ServiceDesc.Streams[1], OrderService_WatchOrders_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &orderServiceWatchOrdersClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type OrderService_WatchOrdersClient interface {
+	Recv() (*OrderEvent, error)
+	grpc.ClientStream
+}
+
+type orderServiceWatchOrdersClient struct {
+	grpc.ClientStream
+}
+
+func (x *orderServiceWatchOrdersClient) Recv() (*OrderEvent, error) {
+	m := new(OrderEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *orderServiceClient) IssueOrder(ctx context.Context, in *IssueOrderRequest, opts ...grpc.CallOption) (*Order, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Order)
+	err := c.cc.Invoke(ctx, OrderService_IssueOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) BatchIssueOrders(ctx context.Context, in *BatchIssueOrdersRequest, opts ...grpc.CallOption) (*BatchIssueOrdersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchIssueOrdersResponse)
+	err := c.cc.Invoke(ctx, OrderService_BatchIssueOrders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) RefundOrder(ctx context.Context, in *RefundOrderRequest, opts ...grpc.CallOption) (*Order, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Order)
+	err := c.cc.Invoke(ctx, OrderService_RefundOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ReturnOrder(ctx context.Context, in *ReturnOrderRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, OrderService_ReturnOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OrderServiceServer is the server API for OrderService service.
+// All implementations must embed UnimplementedOrderServiceServer
+// for forward compatibility
+type OrderServiceServer interface {
+	// DeliverOrder accepts the order from the courier.
+	DeliverOrder(context.Context, *DeliverOrderRequest) (*Order, error)
+	// DeliverOrders accepts the orders the courier hands over at once, every order succeeds or fails on its own.
+	DeliverOrders(context.Context, *DeliverOrdersRequest) (*DeliverOrdersResponse, error)
+	// StreamDeliverOrders is DeliverOrders for manifests too large for one request.
+	StreamDeliverOrders(OrderService_StreamDeliverOrdersServer) error
+	// ImportDeliveries delivers the orders of a courier's manifest, the rows are checked like the deliver command of the cli.
+	ImportDeliveries(context.Context, *ImportDeliveriesRequest) (*ImportDeliveriesResponse, error)
+	GetOrder(context.Context, *GetOrderRequest) (*Order, error)
+	ListOrders(context.Context, *ListOrdersRequest) (*ListOrdersResponse, error)
+	// WatchOrders streams the status changes of the orders matching the filter as they happen.
+	// A client that has lost the stream resumes with the id of the last event it has received.
+	WatchOrders(*WatchOrdersRequest, OrderService_WatchOrdersServer) error
+	// IssueOrder gives the order to the recipient.
+	IssueOrder(context.Context, *IssueOrderRequest) (*Order, error)
+	// BatchIssueOrders gives all the orders to their recipient at once, they must belong to the same recipient.
+	BatchIssueOrders(context.Context, *BatchIssueOrdersRequest) (*BatchIssueOrdersResponse, error)
+	// RefundOrder takes the issued order back from the recipient.
+	RefundOrder(context.Context, *RefundOrderRequest) (*Order, error)
+	// ReturnOrder gives the expired order back to the courier, the order is deleted.
+	ReturnOrder(context.Context, *ReturnOrderRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedOrderServiceServer()
+}
+
+// UnimplementedOrderServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedOrderServiceServer struct {
+}
+
+func (UnimplementedOrderServiceServer) DeliverOrder(context.Context, *DeliverOrderRequest) (*Order, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeliverOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) DeliverOrders(context.Context, *DeliverOrdersRequest) (*DeliverOrdersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeliverOrders not implemented")
+}
+func (UnimplementedOrderServiceServer) StreamDeliverOrders(OrderService_StreamDeliverOrdersServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamDeliverOrders not implemented")
+}
+func (UnimplementedOrderServiceServer) ImportDeliveries(context.Context, *ImportDeliveriesRequest) (*ImportDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportDeliveries not implemented")
+}
+func (UnimplementedOrderServiceServer) GetOrder(context.Context, *GetOrderRequest) (*Order, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) ListOrders(context.Context, *ListOrdersRequest) (*ListOrdersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOrders not implemented")
+}
+func (UnimplementedOrderServiceServer) WatchOrders(*WatchOrdersRequest, OrderService_WatchOrdersServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchOrders not implemented")
+}
+func (UnimplementedOrderServiceServer) IssueOrder(context.Context, *IssueOrderRequest) (*Order, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IssueOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) BatchIssueOrders(context.Context, *BatchIssueOrdersRequest) (*BatchIssueOrdersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchIssueOrders not implemented")
+}
+func (UnimplementedOrderServiceServer) RefundOrder(context.Context, *RefundOrderRequest) (*Order, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefundOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) ReturnOrder(context.Context, *ReturnOrderRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReturnOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) mustEmbedUnimplementedOrderServiceServer() {}
+
+// UnsafeOrderServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OrderServiceServer will
+// result in compilation errors.
+type UnsafeOrderServiceServer interface {
+	mustEmbedUnimplementedOrderServiceServer()
+}
+
+func RegisterOrderServiceServer(s grpc.ServiceRegistrar, srv OrderServiceServer) {
+	s.RegisterService(&OrderService_ServiceDesc, srv)
+}
+
+func _OrderService_DeliverOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeliverOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).DeliverOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_DeliverOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).DeliverOrder(ctx, req.(*DeliverOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_DeliverOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeliverOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).DeliverOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_DeliverOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).DeliverOrders(ctx, req.(*DeliverOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_StreamDeliverOrders_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(OrderServiceServer).StreamDeliverOrders(&orderServiceStreamDeliverOrdersServer{ServerStream: stream})
+}
+
+type OrderService_StreamDeliverOrdersServer interface {
+	SendAndClose(*DeliverOrdersResponse) error
+	Recv() (*DeliverOrderRequest, error)
+	grpc.ServerStream
+}
+
+type orderServiceStreamDeliverOrdersServer struct {
+	grpc.ServerStream
+}
+
+func (x *orderServiceStreamDeliverOrdersServer) SendAndClose(m *DeliverOrdersResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *orderServiceStreamDeliverOrdersServer) Recv() (*DeliverOrderRequest, error) {
+	m := new(DeliverOrderRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _OrderService_ImportDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ImportDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ImportDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ImportDeliveries(ctx, req.(*ImportDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_GetOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_GetOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).GetOrder(ctx, req.(*GetOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ListOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ListOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ListOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ListOrders(ctx, req.(*ListOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_WatchOrders_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchOrdersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrderServiceServer).WatchOrders(m, &orderServiceWatchOrdersServer{ServerStream: stream})
+}
+
+type OrderService_WatchOrdersServer interface {
+	Send(*OrderEvent) error
+	grpc.ServerStream
+}
+
+type orderServiceWatchOrdersServer struct {
+	grpc.ServerStream
+}
+
+func (x *orderServiceWatchOrdersServer) Send(m *OrderEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _OrderService_IssueOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IssueOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).IssueOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_IssueOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).IssueOrder(ctx, req.(*IssueOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_BatchIssueOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchIssueOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).BatchIssueOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_BatchIssueOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).BatchIssueOrders(ctx, req.(*BatchIssueOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_RefundOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefundOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).RefundOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_RefundOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).RefundOrder(ctx, req.(*RefundOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ReturnOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReturnOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ReturnOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ReturnOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ReturnOrder(ctx, req.(*ReturnOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// OrderService_ServiceDesc is the grpc.ServiceDesc for OrderService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OrderService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "order.v2.OrderService",
+	HandlerType: (*OrderServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "DeliverOrder",
+			Handler:    _OrderService_DeliverOrder_Handler,
+		},
+		{
+			MethodName: "DeliverOrders",
+			Handler:    _OrderService_DeliverOrders_Handler,
+		},
+		{
+			MethodName: "ImportDeliveries",
+			Handler:    _OrderService_ImportDeliveries_Handler,
+		},
+		{
+			MethodName: "GetOrder",
+			Handler:    _OrderService_GetOrder_Handler,
+		},
+		{
+			MethodName: "ListOrders",
+			Handler:    _OrderService_ListOrders_Handler,
+		},
+		{
+			MethodName: "IssueOrder",
+			Handler:    _OrderService_IssueOrder_Handler,
+		},
+		{
+			MethodName: "BatchIssueOrders",
+			Handler:    _OrderService_BatchIssueOrders_Handler,
+		},
+		{
+			MethodName: "RefundOrder",
+			Handler:    _OrderService_RefundOrder_Handler,
+		},
+		{
+			MethodName: "ReturnOrder",
+			Handler:    _OrderService_ReturnOrder_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamDeliverOrders",
+			Handler:       _OrderService_StreamDeliverOrders_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "WatchOrders",
+			Handler:       _OrderService_WatchOrders_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "order/v2/order.proto",
+}
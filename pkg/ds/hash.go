@@ -0,0 +1,23 @@
+package ds
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/maphash"
+)
+
+// Hash hashes the key with the seed, keys other than strings and integers are hashed by their fmt representation.
+func Hash[K comparable](seed maphash.Seed, key K) uint64 {
+	var buf [8]byte
+	switch k := any(key).(type) {
+	case string:
+		return maphash.String(seed, k)
+	case int:
+		return maphash.Bytes(seed, binary.LittleEndian.AppendUint64(buf[:0], uint64(k)))
+	case int64:
+		return maphash.Bytes(seed, binary.LittleEndian.AppendUint64(buf[:0], uint64(k)))
+	case uint64:
+		return maphash.Bytes(seed, binary.LittleEndian.AppendUint64(buf[:0], k))
+	}
+	return maphash.String(seed, fmt.Sprint(key))
+}
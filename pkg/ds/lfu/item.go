@@ -6,12 +6,14 @@ import (
 )
 
 type item[K comparable, V any] struct {
-	expiredAt time.Time
-
 	node *linkedlist.Node[K, V]
 	freq int
+	size int
+
+	// expiry is the node in the list ordered by expiration, its value is the expiration time.
+	expiry *linkedlist.Node[K, time.Time]
 }
 
 func (i item[K, V]) Expired(now time.Time) bool {
-	return i.expiredAt.Before(now)
+	return i.expiry.GetValue().Before(now)
 }
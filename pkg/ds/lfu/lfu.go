@@ -1,12 +1,15 @@
 package lfu
 
 import (
+	"homework/pkg/ds"
 	"homework/pkg/ds/linkedlist"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type Cache[K comparable, V any] struct {
+	// lock isn't taken for reading in Get: it moves the entry to the next frequency list
 	lock sync.RWMutex
 
 	nodeMap  map[K]*item[K, V]
@@ -14,24 +17,174 @@ type Cache[K comparable, V any] struct {
 	capacity int
 	min      int
 
+	// expiryList is ordered by expiration time since ttl is the same for every entry
+	expiryList *linkedlist.List[K, time.Time]
+
+	sizer    ds.Sizer[V]
+	size     int
+	onRemove ds.OnRemove[K, V]
+
 	ttl time.Duration
+
+	janitorInterval time.Duration
+	closeOnce       sync.Once
+	done            chan struct{}
+
+	hits, misses, evictions, expirations atomic.Uint64
+}
+
+func NewLFU[K comparable, V any](capacity int, ttl time.Duration, opts ...Option[K, V]) *Cache[K, V] {
+	cache := &Cache[K, V]{
+		nodeMap:    make(map[K]*item[K, V]),
+		listMap:    make(map[int]*linkedlist.List[K, V]),
+		expiryList: linkedlist.New[K, time.Time](),
+		capacity:   capacity,
+		min:        0,
+		sizer:      ds.EntrySizer[V],
+		ttl:        ttl,
+		done:       make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(cache)
+	}
+
+	if cache.janitorInterval > 0 {
+		go cache.janitor()
+	}
+	return cache
 }
 
-func NewLFU[K comparable, V any](capacity int, ttl time.Duration) *Cache[K, V] {
-	return &Cache[K, V]{
-		nodeMap:  make(map[K]*item[K, V]),
-		listMap:  make(map[int]*linkedlist.List[K, V]),
-		capacity: capacity,
-		min:      0,
-		ttl:      ttl,
+func (l *Cache[K, V]) janitor() {
+	ticker := time.NewTicker(l.janitorInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.done:
+			return
+		case now := <-ticker.C:
+			l.lock.Lock()
+			l.removeExpired(now)
+			l.lock.Unlock()
+		}
 	}
 }
 
+// Close stops the janitor.
+func (l *Cache[K, V]) Close() {
+	l.closeOnce.Do(func() {
+		close(l.done)
+	})
+}
+
 func (l *Cache[K, V]) Get(key K) (V, bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	value, ok := l.get(key)
+	if ok {
+		l.hits.Add(1)
+	} else {
+		l.misses.Add(1)
+	}
+	return value, ok
+}
+
+func (l *Cache[K, V]) Stats() ds.Stats {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	return ds.Stats{
+		Hits:        l.hits.Load(),
+		Misses:      l.misses.Load(),
+		Evictions:   l.evictions.Load(),
+		Expirations: l.expirations.Load(),
+		Size:        len(l.nodeMap),
+		Weight:      l.size,
+	}
+}
+
+func (l *Cache[K, V]) Len() int {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	return len(l.nodeMap)
+}
+
+// Range calls f for every live entry until f returns false, the frequencies aren't changed.
+// f must not call methods of the cache.
+func (l *Cache[K, V]) Range(f func(K, V) bool) {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	now := time.Now()
+	for key, node := range l.nodeMap {
+		if node.Expired(now) {
+			continue
+		}
+		if !f(key, node.node.GetValue()) {
+			return
+		}
+	}
+}
+
+// RangeFrequencies is Range with the number of lookups of every entry since it has been put.
+func (l *Cache[K, V]) RangeFrequencies(f func(K, V, int) bool) {
 	l.lock.RLock()
 	defer l.lock.RUnlock()
 
-	return l.get(key)
+	now := time.Now()
+	for key, node := range l.nodeMap {
+		if node.Expired(now) {
+			continue
+		}
+		if !f(key, node.node.GetValue(), node.freq) {
+			return
+		}
+	}
+}
+
+// SetFrequency raises the frequency of the entry, so an entry restored after a restart isn't the first to be evicted.
+func (l *Cache[K, V]) SetFrequency(key K, freq int) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	node, ok := l.nodeMap[key]
+	if !ok || freq <= node.freq {
+		return
+	}
+
+	// min may point to the emptied list, evict looks for the next one then
+	l.unlink(node)
+	node.freq = freq
+	list, ok := l.listMap[freq]
+	if !ok {
+		list = linkedlist.New[K, V]()
+	}
+	list.PushNode(node.node)
+	l.listMap[freq] = list
+}
+
+func (l *Cache[K, V]) Keys() []K {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	keys := make([]K, 0, len(l.nodeMap))
+	for key := range l.nodeMap {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (l *Cache[K, V]) Clear() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.nodeMap = make(map[K]*item[K, V])
+	l.listMap = make(map[int]*linkedlist.List[K, V])
+	l.expiryList = linkedlist.New[K, time.Time]()
+	l.min = 0
+	l.size = 0
 }
 
 func (l *Cache[K, V]) Remove(key K) bool {
@@ -42,14 +195,41 @@ func (l *Cache[K, V]) Remove(key K) bool {
 	if !ok {
 		return false
 	}
-	delete(l.nodeMap, key)
+	l.delete(node)
+	return true
+}
+
+func (l *Cache[K, V]) delete(node *item[K, V]) {
+	delete(l.nodeMap, node.node.GetKey())
+	l.size -= node.size
+	l.expiryList.DeleteNode(node.expiry)
+	l.unlink(node)
+	l.removed(node.node.GetKey(), node.node.GetValue())
+}
+
+func (l *Cache[K, V]) removed(key K, value V) {
+	if l.onRemove != nil {
+		l.onRemove(key, value)
+	}
+}
 
+func (l *Cache[K, V]) unlink(node *item[K, V]) {
 	list, ok := l.listMap[node.freq]
-	if ok {
-		list.DeleteNode(node.node)
+	if !ok {
+		return
 	}
+	list.DeleteNode(node.node)
+	if list.Size() == 0 {
+		delete(l.listMap, node.freq)
+	}
+}
 
-	return true
+// removeExpired removes entries from the head of the expiry list while they are expired.
+func (l *Cache[K, V]) removeExpired(now time.Time) {
+	for head := l.expiryList.Head(); head != nil && head.GetValue().Before(now); head = l.expiryList.Head() {
+		l.delete(l.nodeMap[head.GetKey()])
+		l.expirations.Add(1)
+	}
 }
 
 func (l *Cache[K, V]) get(key K) (V, bool) {
@@ -58,15 +238,17 @@ func (l *Cache[K, V]) get(key K) (V, bool) {
 		return defaultValue[V](), false
 	}
 
-	list, ok := l.listMap[node.freq]
-	if ok {
-		list.DeleteNode(node.node)
-	}
 	if node.Expired(time.Now()) {
-		delete(l.nodeMap, node.node.GetKey())
+		l.delete(node)
+		l.expirations.Add(1)
 		return defaultValue[V](), false
 	}
 
+	l.unlink(node)
+	if _, ok := l.listMap[node.freq]; !ok && l.min == node.freq {
+		l.min++
+	}
+
 	node.freq++
 	nextList, nextOk := l.listMap[node.freq]
 	if !nextOk {
@@ -74,37 +256,76 @@ func (l *Cache[K, V]) get(key K) (V, bool) {
 	}
 	nextList.PushNode(node.node)
 	l.listMap[node.freq] = nextList
-	if list.Size() == 0 && l.min == node.freq-1 {
-		l.min++
-	}
 	return node.node.GetValue(), true
 }
 
+// evict removes the expired entries or the least frequently used one if nothing has expired.
+func (l *Cache[K, V]) evict() {
+	if head := l.expiryList.Head(); head != nil && head.GetValue().Before(time.Now()) {
+		l.removeExpired(time.Now())
+		return
+	}
+
+	list, ok := l.listMap[l.min]
+	if !ok {
+		l.min = 0
+		for freq := range l.listMap {
+			if l.min == 0 || freq < l.min {
+				l.min = freq
+			}
+		}
+		list, ok = l.listMap[l.min]
+		if !ok {
+			return
+		}
+	}
+
+	l.delete(l.nodeMap[list.Head().GetKey()])
+	l.evictions.Add(1)
+}
+
 func (l *Cache[K, V]) Put(key K, value V) {
 	l.lock.Lock()
 	defer l.lock.Unlock()
 
 	if l.capacity == 0 {
+		l.removed(key, value)
 		return
 	}
 
+	size := l.sizer(value)
 	node, ok := l.nodeMap[key]
+	if size > l.capacity {
+		if ok {
+			l.delete(node)
+		}
+		l.removed(key, value)
+		return
+	}
+
 	if ok {
+		l.removed(key, node.node.GetValue())
 		node.node.SetValue(value)
+		l.expiryList.DeleteNode(node.expiry)
+		node.expiry = l.expiryList.PushNode(linkedlist.NewNode[K, time.Time](key, time.Now().Add(l.ttl)))
+		l.size += size - node.size
+		node.size = size
 		l.get(key)
+		for l.size > l.capacity {
+			l.evict()
+		}
 		return
 	}
 
-	if len(l.nodeMap) == l.capacity {
-		minList := l.listMap[l.min]
-		node := minList.DeleteHead()
-		delete(l.nodeMap, node.GetKey())
+	for len(l.nodeMap) != 0 && l.size+size > l.capacity {
+		l.evict()
 	}
 
 	node = &item[K, V]{
-		node:      linkedlist.NewNode[K, V](key, value),
-		freq:      1,
-		expiredAt: time.Now().Add(l.ttl),
+		node:   linkedlist.NewNode[K, V](key, value),
+		freq:   1,
+		size:   size,
+		expiry: l.expiryList.PushNode(linkedlist.NewNode[K, time.Time](key, time.Now().Add(l.ttl))),
 	}
 
 	l.min = 1
@@ -116,6 +337,7 @@ func (l *Cache[K, V]) Put(key K, value V) {
 	list.PushNode(node.node)
 	l.listMap[node.freq] = list
 	l.nodeMap[key] = node
+	l.size += size
 }
 
 func defaultValue[V any]() V {
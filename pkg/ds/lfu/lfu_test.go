@@ -0,0 +1,148 @@
+package lfu
+
+import (
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLFU_EvictLeastFrequent(t *testing.T) {
+	cache := NewLFU[string, int](2, time.Hour)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Get("a")
+
+	cache.Put("c", 3)
+
+	_, ok := cache.Get("b")
+	require.False(t, ok)
+	require.ElementsMatch(t, []string{"a", "c"}, cache.Keys())
+	require.Equal(t, uint64(1), cache.Stats().Evictions)
+}
+
+func TestLFU_SetFrequency(t *testing.T) {
+	cache := NewLFU[string, int](2, time.Hour)
+	cache.Put("a", 1)
+	cache.SetFrequency("a", 3)
+	cache.Put("b", 2)
+	cache.Get("b")
+
+	cache.Put("c", 3)
+
+	require.ElementsMatch(t, []string{"a", "c"}, cache.Keys())
+	frequencies := make(map[string]int)
+	cache.RangeFrequencies(func(k string, _ int, freq int) bool {
+		frequencies[k] = freq
+		return true
+	})
+	require.Equal(t, map[string]int{"a": 3, "c": 1}, frequencies)
+}
+
+func TestLFU_WithSizer(t *testing.T) {
+	cache := NewLFU[string, string](10, time.Hour, WithSizer[string](func(v string) int {
+		return len(v)
+	}))
+	cache.Put("a", "12345")
+	cache.Put("b", "1234")
+	cache.Get("b")
+
+	cache.Put("c", "123")
+	require.ElementsMatch(t, []string{"b", "c"}, cache.Keys())
+	require.Equal(t, 7, cache.Stats().Weight)
+
+	cache.Put("d", "12345678901")
+	_, ok := cache.Get("d")
+	require.False(t, ok)
+}
+
+func TestLFU_RemoveThenEvict(t *testing.T) {
+	cache := NewLFU[string, int](2, time.Hour)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Get("a")
+	cache.Get("b")
+	cache.Remove("b")
+	cache.Put("c", 3)
+	cache.Get("c")
+
+	cache.Put("d", 4)
+
+	require.Equal(t, 2, cache.Len())
+	_, ok := cache.Get("d")
+	require.True(t, ok)
+}
+
+func TestLFU_EvictExpiredFirst(t *testing.T) {
+	cache := NewLFU[string, int](2, 50*time.Millisecond)
+	cache.Put("hot", 1)
+	for i := 0; i < 10; i++ {
+		cache.Get("hot")
+	}
+	cache.Put("expired", 2)
+	time.Sleep(60 * time.Millisecond)
+	cache.Put("hot", 1)
+
+	cache.Put("new", 3)
+
+	require.ElementsMatch(t, []string{"hot", "new"}, cache.Keys())
+	require.Zero(t, cache.Stats().Evictions)
+	require.Equal(t, uint64(1), cache.Stats().Expirations)
+}
+
+func TestLFU_Janitor(t *testing.T) {
+	cache := NewLFU[string, int](10, 10*time.Millisecond, WithJanitor[string, int](10*time.Millisecond))
+	defer cache.Close()
+
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+
+	require.Eventually(t, func() bool {
+		return cache.Len() == 0
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, uint64(2), cache.Stats().Expirations)
+}
+
+func TestLFU_Close(t *testing.T) {
+	cache := NewLFU[string, int](10, time.Hour, WithJanitor[string, int](time.Millisecond))
+
+	cache.Close()
+	cache.Close()
+}
+
+func TestLFU_WithOnRemove(t *testing.T) {
+	var removed []int
+	cache := NewLFU[string, int](2, time.Hour, WithOnRemove(func(_ string, v int) {
+		removed = append(removed, v)
+	}))
+	cache.Put("a", 1)
+	cache.Put("a", 2)
+	cache.Put("b", 3)
+	cache.Put("c", 4)
+	cache.Remove("c")
+	cache.Clear()
+
+	require.Equal(t, []int{1, 3, 4}, removed)
+}
+
+func TestLFU_Concurrent(t *testing.T) {
+	cache := NewLFU[int, int](10, time.Millisecond, WithJanitor[int, int](time.Millisecond))
+	defer cache.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				cache.Put(j%20, i)
+				cache.Get(j % 20)
+				cache.Remove((j + i) % 20)
+				cache.Stats()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	require.LessOrEqual(t, cache.Len(), 10)
+}
@@ -28,6 +28,22 @@ func (l *List[K, V]) DeleteNode(node *Node[K, V]) {
 	node.next = nil
 }
 
+// Head returns the oldest node without removing it, nil if the list is empty.
+func (l *List[K, V]) Head() *Node[K, V] {
+	if l.size == 0 {
+		return nil
+	}
+	return l.head.next
+}
+
+// Next returns the node after the given one, nil if it's the last.
+func (l *List[K, V]) Next(node *Node[K, V]) *Node[K, V] {
+	if node.next == l.tail {
+		return nil
+	}
+	return node.next
+}
+
 func (l *List[K, V]) DeleteHead() *Node[K, V] {
 	node := l.head.next
 	l.DeleteNode(node)
@@ -17,7 +17,7 @@ func (n Node[K, V]) GetValue() V {
 	return n.val
 }
 
-func (n Node[K, V]) SetValue(v V) {
+func (n *Node[K, V]) SetValue(v V) {
 	n.val = v
 }
 
@@ -9,6 +9,7 @@ type item[K comparable, V any] struct {
 	expiredAt time.Time
 
 	node *linkedlist.Node[K, V]
+	size int
 }
 
 func (i item[K, V]) Expired(now time.Time) bool {
@@ -1,63 +1,189 @@
 package lru
 
 import (
+	"homework/pkg/ds"
 	"homework/pkg/ds/linkedlist"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type Cache[K comparable, V any] struct {
+	// lock isn't RWMutex: Get moves the entry to the tail of the list
+	lock sync.Mutex
+
 	ttl      time.Duration
 	data     map[K]*item[K, V]
 	list     *linkedlist.List[K, V]
 	capacity int
 
-	defaultV V
+	sizer    ds.Sizer[V]
+	size     int
+	onRemove ds.OnRemove[K, V]
+
+	hits, misses, evictions, expirations atomic.Uint64
 }
 
-func NewLRUCache[K comparable, V any](capacity int) Cache[K, V] {
-	return Cache[K, V]{
+func NewLRUCache[K comparable, V any](capacity int, ttl time.Duration, opts ...Option[K, V]) *Cache[K, V] {
+	cache := &Cache[K, V]{
+		ttl:      ttl,
 		list:     linkedlist.New[K, V](),
 		data:     make(map[K]*item[K, V]),
 		capacity: capacity,
+		sizer:    ds.EntrySizer[V],
+	}
+	for _, opt := range opts {
+		opt(cache)
 	}
+	return cache
 }
 
 func (LRU *Cache[K, V]) Get(key K) (V, bool) {
+	LRU.lock.Lock()
+	defer LRU.lock.Unlock()
+
 	node, ok := LRU.data[key]
-	if !ok {
-		var res V
-		return res, false
+	if !ok || node.Expired(time.Now()) {
+		if ok {
+			LRU.delete(node)
+			LRU.expirations.Add(1)
+		}
+		LRU.misses.Add(1)
+		return defaultValue[V](), false
 	}
 
 	LRU.list.DeleteNode(node.node)
-	if node.Expired(time.Now()) {
-		delete(LRU.data, node.node.GetKey())
-		return LRU.defaultV, false
-	}
-
-	LRU.data[key] = &item[K, V]{
-		node:      LRU.list.PushNode(linkedlist.NewNode[K, V](key, node.node.GetValue())),
-		expiredAt: time.Now().Add(LRU.ttl),
-	}
+	LRU.list.PushNode(node.node)
+	node.expiredAt = time.Now().Add(LRU.ttl)
 
+	LRU.hits.Add(1)
 	return node.node.GetValue(), true
 }
 
 func (LRU *Cache[K, V]) Put(key K, value V) {
+	LRU.lock.Lock()
+	defer LRU.lock.Unlock()
+
 	if node, ok := LRU.data[key]; ok {
-		LRU.list.DeleteNode(node.node)
+		LRU.delete(node)
+	}
+
+	size := LRU.sizer(value)
+	if size > LRU.capacity {
+		LRU.removed(key, value)
+		return
+	}
+	for LRU.list.Size() != 0 && LRU.size+size > LRU.capacity {
+		LRU.delete(LRU.data[LRU.list.Head().GetKey()])
+		LRU.evictions.Add(1)
 	}
 
 	node := linkedlist.NewNode[K, V](key, value)
 	LRU.list.PushNode(node)
 
-	item := &item[K, V]{
+	LRU.data[key] = &item[K, V]{
 		expiredAt: time.Now().Add(LRU.ttl),
 		node:      node,
+		size:      size,
+	}
+	LRU.size += size
+}
+
+func (LRU *Cache[K, V]) Remove(key K) bool {
+	LRU.lock.Lock()
+	defer LRU.lock.Unlock()
+
+	node, ok := LRU.data[key]
+	if !ok {
+		return false
+	}
+	LRU.delete(node)
+	return true
+}
+
+func (LRU *Cache[K, V]) delete(node *item[K, V]) {
+	LRU.list.DeleteNode(node.node)
+	delete(LRU.data, node.node.GetKey())
+	LRU.size -= node.size
+	LRU.removed(node.node.GetKey(), node.node.GetValue())
+}
+
+func (LRU *Cache[K, V]) removed(key K, value V) {
+	if LRU.onRemove != nil {
+		LRU.onRemove(key, value)
 	}
+}
+
+func (LRU *Cache[K, V]) Len() int {
+	LRU.lock.Lock()
+	defer LRU.lock.Unlock()
 
-	if LRU.list.Size() > LRU.capacity {
-		delete(LRU.data, LRU.list.DeleteHead().GetKey())
+	return len(LRU.data)
+}
+
+// Range calls f for every live entry from the least to the most recently used until f returns false.
+// f must not call methods of the cache.
+func (LRU *Cache[K, V]) Range(f func(K, V) bool) {
+	LRU.lock.Lock()
+	defer LRU.lock.Unlock()
+
+	now := time.Now()
+	for node := LRU.list.Head(); node != nil; node = LRU.list.Next(node) {
+		if LRU.data[node.GetKey()].Expired(now) {
+			continue
+		}
+		if !f(node.GetKey(), node.GetValue()) {
+			return
+		}
 	}
-	LRU.data[key] = item
+}
+
+// RangeFrequencies is Range with the position of every entry from the least recently used one, LRU counts no
+// lookups, so the most recently used entries rank as the most frequent.
+func (LRU *Cache[K, V]) RangeFrequencies(f func(K, V, int) bool) {
+	position := 0
+	LRU.Range(func(k K, v V) bool {
+		position++
+		return f(k, v, position)
+	})
+}
+
+// SetFrequency does nothing: the recency of an entry is set by its put.
+func (LRU *Cache[K, V]) SetFrequency(K, int) {}
+
+func (LRU *Cache[K, V]) Keys() []K {
+	keys := make([]K, 0, LRU.Len())
+	LRU.Range(func(k K, _ V) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return keys
+}
+
+func (LRU *Cache[K, V]) Clear() {
+	LRU.lock.Lock()
+	defer LRU.lock.Unlock()
+
+	LRU.list = linkedlist.New[K, V]()
+	LRU.data = make(map[K]*item[K, V])
+	LRU.size = 0
+}
+
+func (LRU *Cache[K, V]) Stats() ds.Stats {
+	LRU.lock.Lock()
+	defer LRU.lock.Unlock()
+
+	return ds.Stats{
+		Hits:        LRU.hits.Load(),
+		Misses:      LRU.misses.Load(),
+		Evictions:   LRU.evictions.Load(),
+		Expirations: LRU.expirations.Load(),
+		Size:        len(LRU.data),
+		Weight:      LRU.size,
+	}
+}
+
+func defaultValue[V any]() V {
+	var v V
+	return v
 }
@@ -0,0 +1,118 @@
+package lru
+
+import (
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLRU_WithSizer(t *testing.T) {
+	cache := NewLRUCache[string, string](10, time.Hour, WithSizer[string](func(v string) int {
+		return len(v)
+	}))
+	cache.Put("a", "12345")
+	cache.Put("b", "1234")
+	cache.Put("a", "123")
+
+	cache.Put("c", "1234")
+
+	require.Equal(t, 7, cache.size)
+	require.Len(t, cache.data, 2)
+	require.Contains(t, cache.data, "a")
+	require.Contains(t, cache.data, "c")
+}
+
+func TestLRU_EvictLeastRecent(t *testing.T) {
+	cache := NewLRUCache[string, int](2, time.Hour)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Get("a")
+
+	cache.Put("c", 3)
+
+	_, ok := cache.Get("b")
+	require.False(t, ok)
+	require.Equal(t, []string{"a", "c"}, cache.Keys())
+	require.Equal(t, uint64(1), cache.Stats().Evictions)
+}
+
+func TestLRU_PutExistingKey(t *testing.T) {
+	cache := NewLRUCache[string, int](2, time.Hour)
+	cache.Put("a", 1)
+	cache.Put("a", 2)
+
+	value, ok := cache.Get("a")
+	require.True(t, ok)
+	require.Equal(t, 2, value)
+	require.Equal(t, 1, cache.Len())
+	require.Equal(t, 1, cache.list.Size())
+}
+
+func TestLRU_Expired(t *testing.T) {
+	cache := NewLRUCache[string, int](2, 0)
+	cache.Put("a", 1)
+
+	_, ok := cache.Get("a")
+	require.False(t, ok)
+	require.Zero(t, cache.Len())
+}
+
+func TestLRU_Remove(t *testing.T) {
+	cache := NewLRUCache[string, int](2, time.Hour)
+	cache.Put("a", 1)
+
+	require.True(t, cache.Remove("a"))
+	require.False(t, cache.Remove("a"))
+	require.Zero(t, cache.Len())
+	require.Zero(t, cache.Stats().Weight)
+}
+
+func TestLRU_Range(t *testing.T) {
+	cache := NewLRUCache[string, int](3, time.Hour)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+
+	var keys []string
+	cache.Range(func(k string, _ int) bool {
+		keys = append(keys, k)
+		return k != "b"
+	})
+	require.Equal(t, []string{"a", "b"}, keys)
+}
+
+func TestLRU_WithOnRemove(t *testing.T) {
+	var removed []int
+	cache := NewLRUCache[string, int](2, time.Hour, WithOnRemove(func(_ string, v int) {
+		removed = append(removed, v)
+	}))
+	cache.Put("a", 1)
+	cache.Put("a", 2)
+	cache.Put("b", 3)
+	cache.Put("c", 4)
+	cache.Remove("c")
+	cache.Clear()
+
+	require.Equal(t, []int{1, 2, 4}, removed)
+}
+
+func TestLRU_Concurrent(t *testing.T) {
+	cache := NewLRUCache[int, int](10, time.Hour)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				cache.Put(j%20, i)
+				cache.Get(j % 20)
+				cache.Remove((j + i) % 20)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	require.LessOrEqual(t, cache.Len(), 10)
+}
@@ -0,0 +1,19 @@
+package lru
+
+import "homework/pkg/ds"
+
+type Option[K comparable, V any] func(*Cache[K, V])
+
+// WithOnRemove calls f with every value that leaves the cache but by Clear.
+func WithOnRemove[K comparable, V any](f ds.OnRemove[K, V]) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.onRemove = f
+	}
+}
+
+// WithSizer makes the capacity the maximum total size of the values instead of the number of entries.
+func WithSizer[K comparable, V any](sizer ds.Sizer[V]) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.sizer = sizer
+	}
+}
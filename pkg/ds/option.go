@@ -0,0 +1,13 @@
+package ds
+
+// Sizer estimates the size of the value, the capacity of a cache is the maximum total size of its values.
+type Sizer[V any] func(V) int
+
+// OnRemove is called with every value that leaves the cache but by Clear: evicted, expired, removed, replaced
+// or not taken by Put. It's called under the lock of the cache, so it must not call the cache.
+type OnRemove[K comparable, V any] func(K, V)
+
+// EntrySizer counts every value as one, so the capacity is the number of entries.
+func EntrySizer[V any](V) int {
+	return 1
+}
@@ -0,0 +1,133 @@
+package sharded
+
+import (
+	"hash/maphash"
+	"homework/pkg/ds"
+)
+
+type Shard[K comparable, V any] interface {
+	Get(K) (V, bool)
+	Put(K, V)
+	Remove(K) bool
+	Keys() []K
+	Range(func(K, V) bool)
+	RangeFrequencies(func(K, V, int) bool)
+	SetFrequency(K, int)
+	Clear()
+	Stats() ds.Stats
+}
+
+// Cache spreads keys over independent shards, so calls for different keys mostly take different locks.
+type Cache[K comparable, V any] struct {
+	seed   maphash.Seed
+	shards []Shard[K, V]
+}
+
+// New splits the capacity between the shards, there are never more shards than the capacity.
+func New[K comparable, V any](shards, capacity int, newShard func(capacity int) Shard[K, V]) *Cache[K, V] {
+	shards = min(shards, capacity)
+	shards = max(shards, 1)
+
+	cache := &Cache[K, V]{
+		seed:   maphash.MakeSeed(),
+		shards: make([]Shard[K, V], shards),
+	}
+	for i := range cache.shards {
+		shardCapacity := capacity / shards
+		if i < capacity%shards {
+			shardCapacity++
+		}
+		cache.shards[i] = newShard(shardCapacity)
+	}
+	return cache
+}
+
+func (c *Cache[K, V]) shard(key K) Shard[K, V] {
+	return c.shards[ds.Hash(c.seed, key)%uint64(len(c.shards))]
+}
+
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	return c.shard(key).Get(key)
+}
+
+func (c *Cache[K, V]) Put(key K, value V) {
+	c.shard(key).Put(key, value)
+}
+
+func (c *Cache[K, V]) Remove(key K) bool {
+	return c.shard(key).Remove(key)
+}
+
+func (c *Cache[K, V]) Keys() []K {
+	var keys []K
+	for _, shard := range c.shards {
+		keys = append(keys, shard.Keys()...)
+	}
+	return keys
+}
+
+// Range goes over the shards one by one until f returns false.
+func (c *Cache[K, V]) Range(f func(K, V) bool) {
+	next := true
+	for _, shard := range c.shards {
+		shard.Range(func(k K, v V) bool {
+			next = f(k, v)
+			return next
+		})
+		if !next {
+			return
+		}
+	}
+}
+
+// RangeFrequencies goes over the shards one by one until f returns false.
+func (c *Cache[K, V]) RangeFrequencies(f func(K, V, int) bool) {
+	next := true
+	for _, shard := range c.shards {
+		shard.RangeFrequencies(func(k K, v V, freq int) bool {
+			next = f(k, v, freq)
+			return next
+		})
+		if !next {
+			return
+		}
+	}
+}
+
+func (c *Cache[K, V]) SetFrequency(key K, freq int) {
+	c.shard(key).SetFrequency(key, freq)
+}
+
+func (c *Cache[K, V]) Clear() {
+	for _, shard := range c.shards {
+		shard.Clear()
+	}
+}
+
+// Stats sums the counters of the shards.
+func (c *Cache[K, V]) Stats() ds.Stats {
+	var stats ds.Stats
+	for _, shard := range c.shards {
+		s := shard.Stats()
+		stats.Hits += s.Hits
+		stats.Misses += s.Misses
+		stats.Evictions += s.Evictions
+		stats.Expirations += s.Expirations
+		stats.Size += s.Size
+		stats.Weight += s.Weight
+	}
+	return stats
+}
+
+func (c *Cache[K, V]) Shards() int {
+	return len(c.shards)
+}
+
+// Close stops the background work of the shards that have it.
+func (c *Cache[K, V]) Close() {
+	for _, shard := range c.shards {
+		if closer, ok := shard.(interface{ Close() }); ok {
+			closer.Close()
+		}
+	}
+}
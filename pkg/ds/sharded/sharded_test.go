@@ -0,0 +1,124 @@
+package sharded
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"homework/pkg/ds/lfu"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newLFU(shards, capacity int) *Cache[int, int] {
+	return New[int, int](shards, capacity, func(capacity int) Shard[int, int] {
+		return lfu.NewLFU[int, int](capacity, time.Hour)
+	})
+}
+
+func TestCache_PutGetRemove(t *testing.T) {
+	cache := newLFU(4, 1000)
+	for i := 0; i < 100; i++ {
+		cache.Put(i, i)
+	}
+
+	for i := 0; i < 100; i++ {
+		value, ok := cache.Get(i)
+		require.True(t, ok)
+		require.Equal(t, i, value)
+	}
+
+	require.True(t, cache.Remove(1))
+	_, ok := cache.Get(1)
+	require.False(t, ok)
+}
+
+func TestCache_Capacity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		shards   int
+		capacity int
+		want     int
+	}{
+		{name: "split evenly", shards: 4, capacity: 100, want: 4},
+		{name: "remainder", shards: 3, capacity: 10, want: 3},
+		{name: "more shards than capacity", shards: 16, capacity: 5, want: 5},
+		{name: "zero shards", shards: 0, capacity: 5, want: 1},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cache := newLFU(tt.shards, tt.capacity)
+			for i := 0; i < 10*tt.capacity; i++ {
+				cache.Put(i, i)
+			}
+
+			require.Equal(t, tt.want, cache.Shards())
+			require.LessOrEqual(t, len(cache.Keys()), tt.capacity)
+			require.Equal(t, len(cache.Keys()), cache.Stats().Size)
+		})
+	}
+}
+
+func TestCache_StatsAndClear(t *testing.T) {
+	cache := newLFU(4, 100)
+	cache.Put(1, 1)
+	cache.Get(1)
+	cache.Get(2)
+
+	stats := cache.Stats()
+	require.Equal(t, uint64(1), stats.Hits)
+	require.Equal(t, uint64(1), stats.Misses)
+	require.Equal(t, 1, stats.Size)
+
+	cache.Clear()
+	require.Empty(t, cache.Keys())
+}
+
+func TestCache_Concurrent(t *testing.T) {
+	cache := newLFU(8, 100)
+	defer cache.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				cache.Put(j%200, i)
+				cache.Get((j + i) % 200)
+				cache.Remove((j + 2*i) % 200)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func BenchmarkCache_Parallel(b *testing.B) {
+	const capacity = 10_000
+
+	for _, shards := range []int{1, 4, 16, 64} {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			cache := newLFU(shards, capacity)
+			for i := 0; i < capacity; i++ {
+				cache.Put(i, i)
+			}
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					if i%10 == 0 {
+						cache.Put(i%(2*capacity), i)
+					} else {
+						cache.Get(i % capacity)
+					}
+					i++
+				}
+			})
+		})
+	}
+}
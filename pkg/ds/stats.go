@@ -0,0 +1,13 @@
+package ds
+
+// Stats are the counters of a cache since it has been created.
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	// Expirations are entries removed because their ttl has passed.
+	Expirations uint64
+	Size        int
+	// Weight is the total size of the values estimated by the cache's Sizer.
+	Weight int
+}
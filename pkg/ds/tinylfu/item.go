@@ -0,0 +1,27 @@
+package tinylfu
+
+import (
+	"homework/pkg/ds/linkedlist"
+	"time"
+)
+
+type segment int
+
+const (
+	window segment = iota
+	probation
+	protected
+)
+
+type item[K comparable, V any] struct {
+	expiredAt time.Time
+
+	node    *linkedlist.Node[K, V]
+	hash    uint64
+	size    int
+	segment segment
+}
+
+func (i item[K, V]) Expired(now time.Time) bool {
+	return i.expiredAt.Before(now)
+}
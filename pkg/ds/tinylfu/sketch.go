@@ -0,0 +1,81 @@
+package tinylfu
+
+const (
+	sketchDepth = 4
+	maxCounter  = 15
+)
+
+// sketch is a count-min sketch with saturating counters up to 15.
+// Counters are halved after resetAfter increments, so old popularity fades away.
+type sketch struct {
+	rows       [sketchDepth][]uint8
+	mask       uint64
+	additions  int
+	resetAfter int
+}
+
+// newSketch is sized for the number of cached entries and ages the counters after 10 increments per entry.
+func newSketch(entries int) *sketch {
+	size := nextPowerOfTwo(entries * countersPerEntry)
+	s := &sketch{
+		mask:       uint64(size - 1),
+		resetAfter: 10 * max(entries, 1),
+	}
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, size)
+	}
+	return s
+}
+
+var rowSeeds = [sketchDepth]uint64{0xc3a5c85c97cb3127, 0xb492b66fbe98f273, 0x9ae16a3b2f90404f, 0xcbf29ce484222325}
+
+// index mixes the hash with the seed of the row, so the rows collide on different keys.
+// The mix is the splitmix64 finalizer: every bit of the hash affects the low bits used as the index.
+func (s *sketch) index(hash uint64, row int) uint64 {
+	h := hash ^ rowSeeds[row]
+	h = (h ^ h>>30) * 0xbf58476d1ce4e5b9
+	h = (h ^ h>>27) * 0x94d049bb133111eb
+	h ^= h >> 31
+	return h & s.mask
+}
+
+func (s *sketch) increment(hash uint64) {
+	for i := range s.rows {
+		idx := s.index(hash, i)
+		if s.rows[i][idx] < maxCounter {
+			s.rows[i][idx]++
+		}
+	}
+
+	s.additions++
+	if s.additions >= s.resetAfter {
+		s.reset()
+	}
+}
+
+func (s *sketch) estimate(hash uint64) uint8 {
+	min := uint8(maxCounter)
+	for i := range s.rows {
+		if v := s.rows[i][s.index(hash, i)]; v < min {
+			min = v
+		}
+	}
+	return min
+}
+
+func (s *sketch) reset() {
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			s.rows[i][j] /= 2
+		}
+	}
+	s.additions /= 2
+}
+
+func nextPowerOfTwo(n int) int {
+	size := 16
+	for size < n {
+		size *= 2
+	}
+	return size
+}
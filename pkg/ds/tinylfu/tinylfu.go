@@ -0,0 +1,308 @@
+package tinylfu
+
+import (
+	"hash/maphash"
+	"homework/pkg/ds"
+	"homework/pkg/ds/linkedlist"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	windowPercent    = 1
+	protectedPercent = 80
+	// countersPerEntry keeps collisions in the sketch rare
+	countersPerEntry = 16
+	// maxSketchEntries bounds the sketch of a huge capacity
+	maxSketchEntries = 1 << 18
+)
+
+// Cache is W-TinyLFU: new entries get into the window LRU, entries leaving the window are admitted
+// into the segmented main LRU only if they are used more often than its victim.
+// Frequencies are estimated by the count-min sketch that ages, so entries that were hot long ago are evicted.
+type Cache[K comparable, V any] struct {
+	lock sync.Mutex
+
+	data     map[K]*item[K, V]
+	segments [3]*linkedlist.List[K, V]
+	weights  [3]int
+	caps     [3]int
+	capacity int
+
+	sketch *sketch
+	seed   maphash.Seed
+	sizer  ds.Sizer[V]
+	// entrySize is the average size of a value, the sketch is sized for capacity/entrySize entries.
+	entrySize int
+	onRemove  ds.OnRemove[K, V]
+
+	ttl time.Duration
+
+	hits, misses, evictions, expirations atomic.Uint64
+}
+
+func NewTinyLFU[K comparable, V any](capacity int, ttl time.Duration, opts ...Option[K, V]) *Cache[K, V] {
+	windowCap := max(1, capacity*windowPercent/100)
+	mainCap := max(0, capacity-windowCap)
+	protectedCap := mainCap * protectedPercent / 100
+
+	cache := &Cache[K, V]{
+		data:      make(map[K]*item[K, V]),
+		caps:      [3]int{window: windowCap, probation: mainCap - protectedCap, protected: protectedCap},
+		capacity:  capacity,
+		seed:      maphash.MakeSeed(),
+		sizer:     ds.EntrySizer[V],
+		entrySize: 1,
+		ttl:       ttl,
+	}
+	for i := range cache.segments {
+		cache.segments[i] = linkedlist.New[K, V]()
+	}
+	for _, opt := range opts {
+		opt(cache)
+	}
+	cache.sketch = newSketch(min(capacity/max(cache.entrySize, 1), maxSketchEntries))
+	return cache
+}
+
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	node, ok := c.data[key]
+	if !ok {
+		c.sketch.increment(ds.Hash(c.seed, key))
+		c.misses.Add(1)
+		return defaultValue[V](), false
+	}
+
+	c.sketch.increment(node.hash)
+	if node.Expired(time.Now()) {
+		c.delete(node)
+		c.expirations.Add(1)
+		c.misses.Add(1)
+		return defaultValue[V](), false
+	}
+
+	c.touch(node)
+	c.hits.Add(1)
+	return node.node.GetValue(), true
+}
+
+// touch moves the entry to the most recently used position, a second hit in probation protects it.
+func (c *Cache[K, V]) touch(node *item[K, V]) {
+	switch node.segment {
+	case window, protected:
+		c.move(node, node.segment)
+	case probation:
+		c.move(node, protected)
+		for c.weights[protected] > c.caps[protected] {
+			c.move(c.head(protected), probation)
+		}
+	}
+}
+
+func (c *Cache[K, V]) Put(key K, value V) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	size := c.sizer(value)
+	node, ok := c.data[key]
+	if ok {
+		c.delete(node)
+	}
+	if size > c.capacity {
+		c.removed(key, value)
+		return
+	}
+
+	node = &item[K, V]{
+		expiredAt: time.Now().Add(c.ttl),
+		node:      linkedlist.NewNode[K, V](key, value),
+		hash:      ds.Hash(c.seed, key),
+		size:      size,
+		segment:   window,
+	}
+	c.data[key] = node
+	c.segments[window].PushNode(node.node)
+	c.weights[window] += size
+
+	for c.weights[window] > c.caps[window] && c.segments[window].Size() > 1 {
+		candidate := c.head(window)
+		c.move(candidate, probation)
+		c.admit(candidate)
+	}
+	// the window may hold a value bigger than its capacity
+	c.admit(nil)
+}
+
+// admit evicts either the candidate that has just left the window or the victims of the main segments,
+// whichever is used less often, until the main segments fit into the space left by the window.
+func (c *Cache[K, V]) admit(candidate *item[K, V]) {
+	for c.weights[probation]+c.weights[protected] > c.capacity-c.weights[window] {
+		victim := c.head(probation)
+		if victim == nil {
+			victim = c.head(protected)
+		}
+		if victim == nil {
+			return
+		}
+
+		if victim == candidate || candidate == nil {
+			c.evict(victim)
+			candidate = nil
+			continue
+		}
+		if c.sketch.estimate(candidate.hash) > c.sketch.estimate(victim.hash) {
+			c.evict(victim)
+		} else {
+			c.evict(candidate)
+			candidate = nil
+		}
+	}
+}
+
+func (c *Cache[K, V]) head(segment segment) *item[K, V] {
+	node := c.segments[segment].Head()
+	if node == nil {
+		return nil
+	}
+	return c.data[node.GetKey()]
+}
+
+func (c *Cache[K, V]) move(node *item[K, V], to segment) {
+	c.segments[node.segment].DeleteNode(node.node)
+	c.weights[node.segment] -= node.size
+
+	node.segment = to
+	c.segments[to].PushNode(node.node)
+	c.weights[to] += node.size
+}
+
+func (c *Cache[K, V]) evict(node *item[K, V]) {
+	if node.Expired(time.Now()) {
+		c.expirations.Add(1)
+	} else {
+		c.evictions.Add(1)
+	}
+	c.delete(node)
+}
+
+func (c *Cache[K, V]) delete(node *item[K, V]) {
+	c.segments[node.segment].DeleteNode(node.node)
+	c.weights[node.segment] -= node.size
+	delete(c.data, node.node.GetKey())
+	c.removed(node.node.GetKey(), node.node.GetValue())
+}
+
+func (c *Cache[K, V]) removed(key K, value V) {
+	if c.onRemove != nil {
+		c.onRemove(key, value)
+	}
+}
+
+func (c *Cache[K, V]) Remove(key K) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	node, ok := c.data[key]
+	if !ok {
+		return false
+	}
+	c.delete(node)
+	return true
+}
+
+func (c *Cache[K, V]) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return len(c.data)
+}
+
+// Range calls f for every live entry until f returns false, the sketch isn't changed.
+// f must not call methods of the cache.
+func (c *Cache[K, V]) Range(f func(K, V) bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := time.Now()
+	for key, node := range c.data {
+		if node.Expired(now) {
+			continue
+		}
+		if !f(key, node.node.GetValue()) {
+			return
+		}
+	}
+}
+
+// RangeFrequencies is Range with the frequency of every entry estimated by the sketch.
+func (c *Cache[K, V]) RangeFrequencies(f func(K, V, int) bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := time.Now()
+	for key, node := range c.data {
+		if node.Expired(now) {
+			continue
+		}
+		if !f(key, node.node.GetValue(), int(c.sketch.estimate(node.hash))) {
+			return
+		}
+	}
+}
+
+// SetFrequency raises the estimated frequency of the key, the key doesn't have to be cached yet:
+// a key restored after a restart is admitted as if it had been looked up before.
+func (c *Cache[K, V]) SetFrequency(key K, freq int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	hash := ds.Hash(c.seed, key)
+	for i := int(c.sketch.estimate(hash)); i < min(freq, maxCounter); i++ {
+		c.sketch.increment(hash)
+	}
+}
+
+func (c *Cache[K, V]) Keys() []K {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	keys := make([]K, 0, len(c.data))
+	for key := range c.data {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (c *Cache[K, V]) Clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.data = make(map[K]*item[K, V])
+	for i := range c.segments {
+		c.segments[i] = linkedlist.New[K, V]()
+		c.weights[i] = 0
+	}
+}
+
+func (c *Cache[K, V]) Stats() ds.Stats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return ds.Stats{
+		Hits:        c.hits.Load(),
+		Misses:      c.misses.Load(),
+		Evictions:   c.evictions.Load(),
+		Expirations: c.expirations.Load(),
+		Size:        len(c.data),
+		Weight:      c.weights[window] + c.weights[probation] + c.weights[protected],
+	}
+}
+
+func defaultValue[V any]() V {
+	var v V
+	return v
+}
@@ -0,0 +1,162 @@
+package tinylfu
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTinyLFU_PutGet(t *testing.T) {
+	cache := NewTinyLFU[string, int](10, time.Hour)
+	cache.Put("a", 1)
+
+	value, ok := cache.Get("a")
+	require.True(t, ok)
+	require.Equal(t, 1, value)
+
+	cache.Put("a", 2)
+	value, ok = cache.Get("a")
+	require.True(t, ok)
+	require.Equal(t, 2, value)
+	require.Equal(t, 1, cache.Len())
+}
+
+func TestTinyLFU_ScanDoesNotEvictHotKeys(t *testing.T) {
+	cache := NewTinyLFU[string, int](100, time.Hour)
+	for i := 0; i < 50; i++ {
+		key := fmt.Sprint("hot", i)
+		cache.Put(key, i)
+		for j := 0; j < 5; j++ {
+			cache.Get(key)
+		}
+	}
+
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprint("scan", i)
+		cache.Get(key)
+		cache.Put(key, i)
+	}
+
+	// a scan key colliding with a hot key in every row of the sketch may still replace it
+	hot := 0
+	for i := 0; i < 50; i++ {
+		if _, ok := cache.Get(fmt.Sprint("hot", i)); ok {
+			hot++
+		}
+	}
+	require.GreaterOrEqual(t, hot, 48)
+	require.LessOrEqual(t, cache.Len(), 100)
+}
+
+func TestTinyLFU_Capacity(t *testing.T) {
+	cache := NewTinyLFU[int, int](10, time.Hour)
+	for i := 0; i < 100; i++ {
+		cache.Put(i, i)
+	}
+
+	require.Equal(t, 10, cache.Len())
+	require.Equal(t, uint64(90), cache.Stats().Evictions)
+}
+
+func TestTinyLFU_WithSizer(t *testing.T) {
+	cache := NewTinyLFU[string, string](10, time.Hour, WithSizer[string](func(v string) int {
+		return len(v)
+	}))
+	cache.Put("a", "12345")
+	cache.Put("b", "12345")
+	cache.Put("c", "123")
+
+	require.LessOrEqual(t, cache.Stats().Weight, 10)
+
+	cache.Put("d", "12345678901")
+	_, ok := cache.Get("d")
+	require.False(t, ok)
+}
+
+func TestTinyLFU_WithEntrySize(t *testing.T) {
+	cache := NewTinyLFU[string, string](1<<20, time.Hour, WithSizer[string](func(v string) int {
+		return len(v)
+	}), WithEntrySize[string, string](1024))
+
+	require.Len(t, cache.sketch.rows[0], 1024*countersPerEntry)
+}
+
+func TestTinyLFU_SetFrequency(t *testing.T) {
+	cache := NewTinyLFU[string, int](10, time.Hour)
+	cache.SetFrequency("a", 5)
+	cache.Put("a", 1)
+
+	cache.RangeFrequencies(func(k string, _ int, freq int) bool {
+		require.Equal(t, "a", k)
+		require.Equal(t, 5, freq)
+		return true
+	})
+}
+
+func TestTinyLFU_Expired(t *testing.T) {
+	cache := NewTinyLFU[string, int](10, 0)
+	cache.Put("a", 1)
+
+	_, ok := cache.Get("a")
+	require.False(t, ok)
+	require.Zero(t, cache.Len())
+	require.Equal(t, uint64(1), cache.Stats().Expirations)
+}
+
+func TestTinyLFU_Remove(t *testing.T) {
+	cache := NewTinyLFU[string, int](10, time.Hour)
+	cache.Put("a", 1)
+
+	require.True(t, cache.Remove("a"))
+	require.False(t, cache.Remove("a"))
+	require.Zero(t, cache.Stats().Weight)
+}
+
+func TestTinyLFU_WithOnRemove(t *testing.T) {
+	var removed []int
+	cache := NewTinyLFU[string, int](1, time.Hour, WithOnRemove(func(_ string, v int) {
+		removed = append(removed, v)
+	}))
+	cache.Put("a", 1)
+	cache.Put("a", 2)
+	cache.Put("b", 3)
+	cache.Remove("b")
+	cache.Clear()
+
+	require.Equal(t, []int{1, 2, 3}, removed)
+}
+
+func TestSketch_Aging(t *testing.T) {
+	s := newSketch(16)
+	for i := 0; i < maxCounter; i++ {
+		s.increment(42)
+	}
+	require.Equal(t, uint8(maxCounter), s.estimate(42))
+
+	for i := 0; s.additions != 0 && i < s.resetAfter; i++ {
+		s.increment(uint64(i))
+	}
+	require.Less(t, s.estimate(42), uint8(maxCounter))
+}
+
+func TestTinyLFU_Concurrent(t *testing.T) {
+	cache := NewTinyLFU[int, int](10, time.Hour)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				cache.Put(j%20, i)
+				cache.Get(j % 20)
+				cache.Remove((j + i) % 20)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	require.LessOrEqual(t, cache.Len(), 10)
+}
@@ -25,7 +25,7 @@ func NewOnCallFromEnv(ctx context.Context, topic kafka.Topic) *Kafka {
 		panic(err)
 	}
 
-	producer, err := kafka.NewProducer(ctx, []string{broker})
+	producer, err := kafka.NewProducer(ctx, []string{broker}, kafka.ProducerConfig{Overflow: kafka.OverflowBlock})
 	if err != nil {
 		panic(err)
 	}
@@ -43,7 +43,7 @@ func (s *OnCallTestSuite) TearDownTest() {
 
 func (s *OnCallTestSuite) TestAsyncSend() {
 	onCallMessage := NewOnCallMessage()
-	err := s.kafka.OnCallSender.SendAsyncMessage(onCallMessage)
+	err := s.kafka.OnCallSender.SendAsyncMessage(s.ctx, onCallMessage)
 	require.NoError(s.T(), err)
 }
 
@@ -51,7 +51,7 @@ func (s *OnCallTestSuite) TestAsyncGetMessage() {
 	onCallMessage := NewOnCallMessage()
 
 	received := false
-	err := s.kafka.OnCallReceiver.Subscribe(s.kafka.Topic, func(message *sarama.ConsumerMessage) {
+	err := s.kafka.OnCallReceiver.Subscribe(s.kafka.Topic, func(ctx context.Context, message *sarama.ConsumerMessage) {
 		received = true
 
 		var m dto.OnCallMessage
@@ -61,7 +61,7 @@ func (s *OnCallTestSuite) TestAsyncGetMessage() {
 	})
 	require.NoError(s.T(), err)
 
-	err = s.kafka.OnCallSender.SendAsyncMessage(onCallMessage)
+	err = s.kafka.OnCallSender.SendAsyncMessage(s.ctx, onCallMessage)
 	require.NoError(s.T(), err)
 
 	time.Sleep(time.Second * 3)
@@ -4,17 +4,21 @@ package postgresql
 
 import (
 	"context"
+	"errors"
 	"github.com/jackc/puddle"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"homework/internal/cache"
 	"homework/internal/dto"
 	"homework/internal/model"
+	"homework/internal/service"
 	"homework/internal/storage"
 	"homework/internal/storage/transactor"
 	"homework/tests/postgresql/ids"
 	"homework/tests/postgresql/postgresql"
 	"math"
+	"sync"
 	"testing"
 	"time"
 )
@@ -33,7 +37,7 @@ func TestOrders(t *testing.T) {
 func (s *OrderTestSuite) SetupSuite() {
 	s.T().Parallel()
 	s.transactor = transactor.NewTransactionManager(db.GetPool())
-	s.orderStorage = storage.NewOrderStorage(&s.transactor, cache.NewOrdersCache(1, 0))
+	s.orderStorage = storage.NewOrderStorage(&s.transactor, cache.NewOrdersCache(1, 0), nil, 0)
 	s.ctx = context.Background()
 }
 
@@ -56,6 +60,50 @@ func (s *OrderTestSuite) TestGet() {
 	require.EqualExportedValues(s.T(), order, response)
 }
 
+func (s *OrderTestSuite) TestAddOrders() {
+	stored := NewDeliveredOrderWithoutWrapper(ids.NextID())
+	err := db.CreateOrder(s.ctx, stored, "131")
+	require.Nil(s.T(), err)
+
+	order := NewDeliveredOrderWithoutWrapper(ids.NextID())
+	errs, err := s.orderStorage.AddOrders(s.ctx, []model.Order{order, stored, order}, []string{"1", "2", "3"})
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), []error{nil, storage.ErrDuplicateOrderID, storage.ErrDuplicateOrderID}, errs)
+
+	response, err := s.get(order.ID)
+	require.Nil(s.T(), err)
+	require.EqualExportedValues(s.T(), order, response)
+}
+
+func (s *OrderTestSuite) TestExportOrders() {
+	recipientID := ids.NextID()
+	from := time.Now()
+	var orderIDs []string
+	for i := 0; i < 3; i++ {
+		order := NewDeliveredOrderWithoutWrapper(ids.NextID())
+		order.RecipientID = recipientID
+		err := s.orderStorage.AddOrder(s.ctx, order, "131")
+		require.Nil(s.T(), err)
+		orderIDs = append(orderIDs, order.ID)
+	}
+
+	var exported []string
+	batches := 0
+	err := s.transactor.RunRepeatableRead(s.ctx, func(ctx context.Context) error {
+		param := dto.ExportOrdersParam{RecipientID: recipientID, Status: model.StatusDelivered, From: from}
+		return s.orderStorage.ExportOrders(ctx, param, 2, func(orders []dto.ExportedOrder) error {
+			batches++
+			for _, order := range orders {
+				exported = append(exported, order.ID)
+			}
+			return nil
+		})
+	})
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), orderIDs, exported)
+	require.Equal(s.T(), 2, batches)
+}
+
 func (s *OrderTestSuite) get(id string) (model.Order, error) {
 	return s.orderStorage.GetOrderById(s.ctx, id)
 }
@@ -123,9 +171,178 @@ func (s *OrderTestSuite) TestRemoveCache() {
 	require.ErrorIs(s.T(), err, puddle.ErrClosedPool)
 }
 
+func (s *OrderTestSuite) TestNewOrderInCachedList() {
+	orderStorage, db := s.getStorageWithCache()
+	defer db.Close()
+
+	order := NewDeliveredOrderWithoutWrapper(ids.NextID())
+	order.RecipientID = order.ID
+	param := dto.ListOrdersParam{UserId: order.RecipientID, Page: 1, Size: 10}
+	orders, err := orderStorage.ListOrders(s.ctx, param)
+	require.Nil(s.T(), err)
+	require.Empty(s.T(), orders)
+
+	err = orderStorage.AddOrder(s.ctx, order, "131")
+	require.Nil(s.T(), err)
+
+	orders, err = orderStorage.ListOrders(s.ctx, param)
+	require.Nil(s.T(), err)
+	require.Len(s.T(), orders, 1)
+}
+
+func (s *OrderTestSuite) TestConcurrentGet() {
+	orderStorage, db := s.getStorageWithCache()
+	defer db.Close()
+
+	order := NewDeliveredOrderWithoutWrapper(ids.NextID())
+	err := db.CreateOrder(s.ctx, order, "131")
+	require.Nil(s.T(), err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			response, err := orderStorage.GetOrderById(s.ctx, order.ID)
+			assert.Nil(s.T(), err)
+			assert.EqualExportedValues(s.T(), order, response)
+		}()
+	}
+	wg.Wait()
+}
+
+func (s *OrderTestSuite) TestStaleWhileRevalidate() {
+	db := postgresql.NewFromEnv()
+	defer db.Close()
+	transactor := transactor.NewTransactionManager(db.GetPool())
+	orderStorage := storage.NewOrderStorage(&transactor, cache.NewOrdersCacheWithConfig(cache.OrdersCacheConfig{Capacity: math.MaxInt, Stale: time.Hour}), nil, 0)
+
+	order := NewDeliveredOrderWithoutWrapper(ids.NextID())
+	err := db.CreateOrder(s.ctx, order, "131")
+	require.Nil(s.T(), err)
+
+	_, err = orderStorage.GetOrderById(s.ctx, order.ID)
+	require.Nil(s.T(), err)
+
+	staleOrder, err := orderStorage.GetOrderById(s.ctx, order.ID)
+	require.Nil(s.T(), err)
+	require.EqualExportedValues(s.T(), order, staleOrder)
+}
+
+func (s *OrderTestSuite) TestSharedQueryTimeout() {
+	orderStorage := storage.NewOrderStorage(&s.transactor, cache.NewOrdersCache(1, 0), nil, time.Nanosecond)
+
+	_, err := orderStorage.GetOrderById(context.Background(), ids.NextID())
+	require.ErrorIs(s.T(), err, context.DeadlineExceeded)
+}
+
+func (s *OrderTestSuite) TestWarmUp() {
+	orderStorage, db := s.getStorageWithCache()
+
+	order := NewDeliveredOrderWithoutWrapper(ids.NextID())
+	err := db.CreateOrder(s.ctx, order, "131")
+	require.Nil(s.T(), err)
+
+	loaded := orderStorage.WarmUp(s.ctx, []dto.GetParam{{Ids: []string{order.ID}}})
+	require.Equal(s.T(), 1, loaded)
+
+	db.Close()
+
+	cachedOrder, err := orderStorage.GetOrderById(s.ctx, order.ID)
+	require.Nil(s.T(), err)
+	require.EqualExportedValues(s.T(), order, cachedOrder)
+}
+
+type invalidations struct {
+	mu       sync.Mutex
+	messages []dto.InvalidationMessage
+}
+
+func (i *invalidations) Publish(_ context.Context, message dto.InvalidationMessage) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.messages = append(i.messages, message)
+	return nil
+}
+
+func (s *OrderTestSuite) TestInvalidateAfterCommit() {
+	published := &invalidations{}
+	orderStorage := storage.NewOrderStorage(&s.transactor, cache.NewOrdersCache(1, 0), published, 0)
+	rollback := errors.New("rollback")
+
+	err := s.transactor.RunRepeatableRead(s.ctx, func(ctx context.Context) error {
+		_, err := orderStorage.AddOrders(ctx, []model.Order{NewDeliveredOrderWithoutWrapper(ids.NextID())}, []string{"1"})
+		require.Nil(s.T(), err)
+		return rollback
+	})
+	require.ErrorIs(s.T(), s.transactor.Unwrap(err), rollback)
+	require.Empty(s.T(), published.messages)
+
+	order := NewDeliveredOrderWithoutWrapper(ids.NextID())
+	err = s.transactor.RunRepeatableRead(s.ctx, func(ctx context.Context) error {
+		_, err := orderStorage.AddOrders(ctx, []model.Order{order}, []string{"1"})
+		require.Nil(s.T(), err)
+		// nothing is published before the commit
+		require.Empty(s.T(), published.messages)
+		return nil
+	})
+	require.Nil(s.T(), err)
+	require.Len(s.T(), published.messages, 1)
+	require.Equal(s.T(), []string{order.ID}, published.messages[0].Ids)
+}
+
+func (s *OrderTestSuite) TestDryRunDoesNotInvalidate() {
+	published := &invalidations{}
+	ordersCache := cache.NewOrdersCache(math.MaxInt, time.Hour)
+	orderStorage := storage.NewOrderStorage(&s.transactor, ordersCache, published, 0)
+	orderService := service.NewOrder(service.Deps{
+		Storage:            orderStorage,
+		WrapperStorage:     storage.NewWrapperStorage(&s.transactor),
+		TransactionManager: &s.transactor,
+	})
+	recipientID := ids.NextID()
+	orders, err := orderStorage.ListUserOrders(s.ctx, recipientID, math.MaxInt, "")
+	require.Nil(s.T(), err)
+	require.Empty(s.T(), orders)
+	keys := ordersCache.Keys()
+
+	params := []dto.DeliverOrderParam{{ID: ids.NextID(), RecipientID: recipientID, ExpirationDate: time.Now().Add(time.Hour)}}
+	errs, err := orderService.CheckDeliverOrders(s.ctx, params)
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), []error{nil}, errs)
+
+	// the rolled back orders neither reach the other instances nor drop the cached results
+	require.Empty(s.T(), published.messages)
+	require.Equal(s.T(), keys, ordersCache.Keys())
+}
+
+func (s *OrderTestSuite) TestTransactionReadsAreNotCached() {
+	orderStorage, db := s.getStorageWithCache()
+	defer db.Close()
+	transactor := transactor.NewTransactionManager(db.GetPool())
+	order := NewDeliveredOrderWithoutWrapper(ids.NextID())
+	order.RecipientID = ids.NextID()
+	rollback := errors.New("rollback")
+
+	err := transactor.RunRepeatableRead(s.ctx, func(ctx context.Context) error {
+		_, err := orderStorage.AddOrders(ctx, []model.Order{order}, []string{"1"})
+		require.Nil(s.T(), err)
+
+		orders, err := orderStorage.ListUserOrders(ctx, order.RecipientID, math.MaxInt, "")
+		require.Nil(s.T(), err)
+		require.Len(s.T(), orders, 1)
+		return rollback
+	})
+	require.ErrorIs(s.T(), transactor.Unwrap(err), rollback)
+
+	orders, err := orderStorage.ListUserOrders(s.ctx, order.RecipientID, math.MaxInt, "")
+	require.Nil(s.T(), err)
+	require.Empty(s.T(), orders)
+}
+
 func (s *OrderTestSuite) getStorageWithCache() (*storage.OrderStorage, *postgresql.DBPool) {
 	db := postgresql.NewFromEnv()
 	transactor := transactor.NewTransactionManager(db.GetPool())
-	orderStorage := storage.NewOrderStorage(&transactor, cache.NewOrdersCache(math.MaxInt, time.Hour))
+	orderStorage := storage.NewOrderStorage(&transactor, cache.NewOrdersCache(math.MaxInt, time.Hour), nil, 0)
 	return orderStorage, db
 }
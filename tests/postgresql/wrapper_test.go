@@ -30,7 +30,7 @@ func TestWrapper(t *testing.T) {
 func (s *WrapperTestSuite) SetupSuite() {
 	s.T().Parallel()
 	s.transactor = transactor.NewTransactionManager(db.GetPool())
-	s.orderStorage = storage.NewOrderStorage(&s.transactor, cache.NewOrdersCache(1, 0))
+	s.orderStorage = storage.NewOrderStorage(&s.transactor, cache.NewOrdersCache(1, 0), nil, 0)
 	s.wrapperStorage = storage.NewWrapperStorage(&s.transactor)
 	s.ctx = context.Background()
 }
@@ -52,6 +52,25 @@ func (s *WrapperTestSuite) TestCreate() {
 	require.Nil(s.T(), err)
 }
 
+func (s *WrapperTestSuite) TestCreateBatch() {
+	orders := []model.Order{NewDeliveredOrder(ids.NextID()), NewDeliveredOrder(ids.NextID())}
+	err := s.transactor.RunRepeatableRead(s.ctx, func(ctx context.Context) error {
+		_, err := s.orderStorage.AddOrders(ctx, orders, []string{"1", "2"})
+		if err != nil {
+			return err
+		}
+		return s.wrapperStorage.AddWrappers(ctx,
+			[]wrapper.Wrapper{*orders[0].Wrapper, *orders[1].Wrapper}, []string{orders[0].ID, orders[1].ID})
+	})
+	require.Nil(s.T(), err)
+
+	for _, order := range orders {
+		response, err := s.getOrder(order)
+		require.Nil(s.T(), err)
+		require.EqualExportedValues(s.T(), order, response)
+	}
+}
+
 func (s *WrapperTestSuite) TestGet() {
 	order := NewDeliveredOrder(ids.NextID())
 	err := db.CreateWrapper(s.ctx, order, "3131")